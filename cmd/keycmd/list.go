@@ -147,7 +147,7 @@ func listKeys(*cobra.Command, []string) error {
 	var addrInfos []addressInfo
 	networks := []models.Network{}
 	if local || all {
-		networks = append(networks, models.LocalNetwork)
+		networks = append(networks, app.GetLocalNetwork())
 	}
 	if testnet || all {
 		networks = append(networks, models.FujiNetwork)
@@ -164,7 +164,7 @@ func listKeys(*cobra.Command, []string) error {
 		if err != nil {
 			return err
 		}
-		network := models.NetworkFromString(networkStr)
+		network := app.GetNetworkFromString(networkStr)
 		networks = append(networks, network)
 	}
 	queryLedger := len(ledgerIndices) > 0
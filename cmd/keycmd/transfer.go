@@ -160,7 +160,7 @@ func transferF(*cobra.Command, []string) error {
 	var network models.Network
 	switch {
 	case local:
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	case testnet:
 		network = models.FujiNetwork
 	case mainnet:
@@ -173,7 +173,7 @@ func transferF(*cobra.Command, []string) error {
 		if err != nil {
 			return err
 		}
-		network = models.NetworkFromString(networkStr)
+		network = app.GetNetworkFromString(networkStr)
 	}
 
 	var err error
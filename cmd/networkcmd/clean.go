@@ -3,15 +3,16 @@
 package networkcmd
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"regexp"
 
+	"github.com/ava-labs/avalanche-cli/cmd/subnetcmd"
 	"github.com/ava-labs/avalanche-cli/pkg/elasticsubnet"
 
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
-	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/shirou/gopsutil/process"
@@ -19,7 +20,10 @@ import (
 	"go.uber.org/zap"
 )
 
-var hard bool
+var (
+	hard        bool
+	cleanSubnet string
+)
 
 func newCleanCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -27,7 +31,12 @@ func newCleanCmd() *cobra.Command {
 		Short: "Stop the running local network and delete state",
 		Long: `The network clean command shuts down your local, multi-node network. All deployed Subnets
 shutdown and delete their state. You can restart the network by deploying a new Subnet
-configuration.`,
+configuration.
+
+If you provide the --subnet flag, only that Subnet is removed from the local network: the
+network is stopped into its snapshot, the nodes are set to stop running its blockchain, and
+its VM plugin and local deploy info are deleted. The other deployed Subnets keep their IDs
+and state, and are restored on the next network start.`,
 		RunE:         clean,
 		Args:         cobra.ExactArgs(0),
 		SilenceUsage: true,
@@ -39,16 +48,34 @@ configuration.`,
 		false,
 		"Also clean downloaded avalanchego and plugin binaries",
 	)
+	cmd.Flags().StringVar(&cleanSubnet, "subnet", "", "only remove the given subnet from the local network")
 
 	return cmd
 }
 
 func clean(*cobra.Command, []string) error {
+	if cleanSubnet != "" {
+		if hard {
+			return errors.New("--hard can't be used together with --subnet")
+		}
+		wasRunning, err := subnetcmd.RemoveLocalSubnet(cleanSubnet)
+		if err != nil {
+			return err
+		}
+		if wasRunning {
+			ux.Logger.PrintToUser("Run network start to restart the network with the other Subnets")
+		}
+		return nil
+	}
+
 	app.Log.Info("killing gRPC server process...")
 
 	if err := subnet.SetDefaultSnapshot(app.GetSnapshotsDir(), true); err != nil {
 		app.Log.Warn("failed resetting default snapshot", zap.Error(err))
 	}
+	if err := app.SetDeterministicLocalNetwork(false); err != nil {
+		app.Log.Warn("failed clearing deterministic network mode", zap.Error(err))
+	}
 
 	if err := binutils.KillgRPCServerProcess(app); err != nil {
 		app.Log.Warn("failed killing server process", zap.Error(err))
@@ -96,7 +123,7 @@ func removeLocalDeployInfoFromSidecars() error {
 			return err
 		}
 
-		delete(sc.Networks, models.Local.String())
+		delete(sc.Networks, app.GetLocalNetwork().Name())
 		if err = app.UpdateSidecar(&sc); err != nil {
 			return err
 		}
@@ -117,7 +144,7 @@ func removeLocalElasticSubnetInfoFromSidecars() error {
 			return err
 		}
 
-		delete(sc.ElasticSubnet, models.Local.String())
+		delete(sc.ElasticSubnet, app.GetLocalNetwork().Name())
 		if err = app.UpdateSidecar(&sc); err != nil {
 			return err
 		}
@@ -0,0 +1,208 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/cmd/subnetcmd"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/faucet"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultFaucetPort     = 8090
+	faucetRequestTimeout  = 2 * time.Minute
+	faucetShutdownTimeout = 5 * time.Second
+)
+
+var (
+	faucetSubnet    string
+	faucetHost      string
+	faucetPort      int
+	faucetKeyName   string
+	faucetAmount    float64
+	faucetRateLimit time.Duration
+	faucetDevnet    bool
+	faucetEndpoint  string
+)
+
+func newFaucetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "faucet",
+		Short: "Serve a faucet for the local network or a devnet",
+		Long: `The network faucet command serves an HTTP faucet that sends test tokens from a funded key.
+
+The faucet serves a web page at its root, and a json API:
+  GET  /api/chains     lists the chains the faucet sends tokens on
+  POST /api/drip       sends tokens, given a body like {"chain": "C", "address": "0x..."}
+  GET  /api/transfers  lists the latest transfers issued
+
+It sends AVAX on the P-Chain and the C-Chain, and the native token of all Subnet-EVM
+Subnets deployed to the network, or only of the one given with --subnet. Subnet tokens are
+sent with 18 decimals, unless the chain config of the Subnet sets "decimals". Each address can
+receive tokens on a chain once per rate limit period. Issued transfers are logged to
+~/.avalanche-cli/logs/faucet-transfers.log.
+
+By default the faucet targets the local network, and sends tokens from the ewoq key. Use
+--devnet and --endpoint to target a devnet, and --key to use another stored key.`,
+		RunE:         runFaucet,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&faucetSubnet, "subnet", "", "only send tokens on this subnet, besides P-Chain and C-Chain")
+	cmd.Flags().StringVar(&faucetHost, "host", "127.0.0.1", "address to listen on")
+	cmd.Flags().IntVar(&faucetPort, "port", defaultFaucetPort, "port to listen on")
+	cmd.Flags().StringVar(&faucetKeyName, "key", "", "stored key to send tokens from (default ewoq key)")
+	cmd.Flags().Float64Var(&faucetAmount, "amount", 1, "amount of tokens to send on each request")
+	cmd.Flags().DurationVar(&faucetRateLimit, "rate-limit", time.Hour, "min time between two requests from the same address on the same chain")
+	cmd.Flags().BoolVar(&faucetDevnet, "devnet", false, "serve a faucet for a devnet")
+	cmd.Flags().StringVar(&faucetEndpoint, "endpoint", "", "use the given endpoint for network operations")
+	return cmd
+}
+
+func runFaucet(*cobra.Command, []string) error {
+	network := app.GetLocalNetwork()
+	if faucetDevnet {
+		network = models.DevnetNetwork
+	}
+	if faucetEndpoint != "" {
+		network.Endpoint = faucetEndpoint
+	}
+	if network.Endpoint == "" {
+		return errors.New("--endpoint is required for devnet")
+	}
+	if err := subnetcmd.SetDevnetNetworkID(&network); err != nil {
+		return err
+	}
+
+	var (
+		sk  *key.SoftKey
+		err error
+	)
+	if faucetKeyName != "" {
+		sk, err = key.LoadSoft(network.ID, app.GetKeyPath(faucetKeyName))
+	} else {
+		sk, err = key.LoadEwoq(network.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	chains, err := getFaucetChains(network, sk)
+	if err != nil {
+		return err
+	}
+	logPath := filepath.Join(app.GetBaseDir(), constants.LogDir, constants.FaucetTransfersLogFile)
+	f, err := faucet.New(chains, faucetAmount, faucetRateLimit, logPath)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:              net.JoinHostPort(faucetHost, strconv.Itoa(faucetPort)),
+		Handler:           http.TimeoutHandler(f.Handler(), faucetRequestTimeout, "faucet request timed out"),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	ux.Logger.PrintToUser("Faucet for %s serving at http://%s", network.Name(), server.Addr)
+	ux.Logger.PrintToUser("Sending %g tokens per request from %s / %s", faucetAmount, sk.C(), sk.P()[0])
+	for _, chain := range f.Chains() {
+		ux.Logger.PrintToUser("  - %s (%s)", chain.Name(), chain.Symbol())
+	}
+	ux.Logger.PrintToUser("Transfers are logged to %s", logPath)
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), faucetShutdownTimeout)
+	defer shutdownCancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// getFaucetChains returns P-Chain, C-Chain, and the Subnet-EVM chains deployed to [network]
+func getFaucetChains(network models.Network, sk *key.SoftKey) ([]faucet.Chain, error) {
+	chains := []faucet.Chain{
+		faucet.NewPChain(network, sk.KeyChain()),
+		faucet.NewEVMChain("C", "AVAX", faucet.EVMTokenDecimals, network.CChainEndpoint(), sk.Key().ToECDSA()),
+	}
+	subnetNames := []string{faucetSubnet}
+	if faucetSubnet == "" {
+		var err error
+		subnetNames, err = app.GetSidecarNames()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, subnetName := range subnetNames {
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return nil, err
+		}
+		networkData, ok := sc.Networks[network.Name()]
+		if !ok || networkData.BlockchainID == ids.Empty {
+			if faucetSubnet != "" {
+				return nil, fmt.Errorf("subnet %s is not deployed to %s", subnetName, network.Name())
+			}
+			continue
+		}
+		if sc.VM != models.SubnetEvm {
+			if faucetSubnet != "" {
+				return nil, fmt.Errorf("subnet %s does not use Subnet-EVM", subnetName)
+			}
+			continue
+		}
+		decimals, err := getSubnetTokenDecimals(subnetName)
+		if err != nil {
+			return nil, err
+		}
+		rpcURL := fmt.Sprintf("%s/ext/bc/%s/rpc", network.Endpoint, networkData.BlockchainID)
+		chains = append(chains, faucet.NewEVMChain(subnetName, sc.TokenName, decimals, rpcURL, sk.Key().ToECDSA()))
+	}
+	return chains, nil
+}
+
+// getSubnetTokenDecimals returns the number of decimals of the native token of [subnetName],
+// as set by the "decimals" key of its chain config, or else the EVM default
+func getSubnetTokenDecimals(subnetName string) (uint8, error) {
+	if !app.ChainConfigExists(subnetName) {
+		return faucet.EVMTokenDecimals, nil
+	}
+	chainConfigBytes, err := app.LoadRawChainConfig(subnetName)
+	if err != nil {
+		return 0, err
+	}
+	chainConfig := struct {
+		Decimals *uint8 `json:"decimals"`
+	}{}
+	if err := json.Unmarshal(chainConfigBytes, &chainConfig); err != nil {
+		return 0, fmt.Errorf("invalid chain config of subnet %s: %w", subnetName, err)
+	}
+	if chainConfig.Decimals == nil {
+		return faucet.EVMTokenDecimals, nil
+	}
+	return *chainConfig.Decimals, nil
+}
@@ -0,0 +1,433 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// avalanchego plain log lines start with a [01-02|15:04:05.000] timestamp
+	logTimestampLayout = "01-02|15:04:05.000"
+	logPollInterval    = 500 * time.Millisecond
+	backendLogPrefix   = "backend"
+	mainLogFileName    = "main.log"
+)
+
+var (
+	logsNodeName  string
+	logsChain     string
+	logsFollow    bool
+	logsLevel     string
+	logsSince     time.Duration
+	logsJSON      bool
+	primaryChains = []string{"P", "X", "C"}
+
+	errNoLogSources = errors.New("no log files found for the given selection")
+)
+
+func newLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Shows the logs of the local network",
+		Long: `The network logs command shows the logs of the local network nodes, merged in
+a single stream where each line is prefixed with its node and chain.
+
+By default the main log of every node and the backend log are shown. Use --node to
+select a single node, and --chain to show the log of a given chain instead. The chain
+can be given by subnet name, blockchain ID, or as P, X or C for the Primary Network
+chains.
+
+With --follow, the command keeps printing new log lines as they are written, until
+interrupted. Use --level to hide lines below the given level, --since to hide lines
+older than the given duration, and --json to output JSON lines.`,
+		RunE:         networkLogs,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&logsNodeName, "node", "", "only show logs of the given node")
+	cmd.Flags().StringVar(&logsChain, "chain", "", "show logs of the given chain (subnet name, blockchain ID, P, X or C)")
+	cmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "keep printing new log lines")
+	cmd.Flags().StringVar(&logsLevel, "level", "", "minimum log level to show (verbo, debug, trace, info, warn, error, fatal)")
+	cmd.Flags().DurationVar(&logsSince, "since", 0, "only show lines newer than the given duration (ex: 10m)")
+	cmd.Flags().BoolVar(&logsJSON, "json", false, "output JSON lines")
+	return cmd
+}
+
+type logSource struct {
+	prefix string
+	path   string
+}
+
+type logEntry struct {
+	Source  string    `json:"source"`
+	Time    time.Time `json:"time,omitempty"`
+	Level   string    `json:"level,omitempty"`
+	Message string    `json:"message"`
+}
+
+// logFilter holds the line filtering settings common to all sources
+type logFilter struct {
+	minLevel logging.Level
+	since    time.Time
+}
+
+func (f logFilter) accepts(entry logEntry) bool {
+	if !f.since.IsZero() && !entry.Time.IsZero() && entry.Time.Before(f.since) {
+		return false
+	}
+	if entry.Level != "" {
+		level, err := logging.ToLevel(entry.Level)
+		if err == nil && level < f.minLevel {
+			return false
+		}
+	}
+	return true
+}
+
+func networkLogs(*cobra.Command, []string) error {
+	filter := logFilter{minLevel: logging.Verbo}
+	if logsLevel != "" {
+		level, err := logging.ToLevel(logsLevel)
+		if err != nil {
+			return err
+		}
+		filter.minLevel = level
+	}
+	if logsSince != 0 {
+		filter.since = time.Now().Add(-logsSince)
+	}
+
+	sources, err := getLogSources()
+	if err != nil {
+		return err
+	}
+	if len(sources) == 0 {
+		return errNoLogSources
+	}
+
+	printer := newLogPrinter(os.Stdout, logsJSON, sources)
+	if !logsFollow {
+		entries := []logEntry{}
+		for _, source := range sources {
+			sourceEntries, err := readLogEntries(source, filter)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, sourceEntries...)
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Time.Before(entries[j].Time)
+		})
+		for _, entry := range entries {
+			printer.print(entry)
+		}
+		return nil
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	wg := sync.WaitGroup{}
+	for _, source := range sources {
+		wg.Add(1)
+		go func(source logSource) {
+			defer wg.Done()
+			followLog(ctx, source, filter, printer)
+		}(source)
+	}
+	wg.Wait()
+	return nil
+}
+
+// getLogSources returns the log files matching the --node and --chain selection,
+// using the cluster info of the running network if available, or the latest
+// network run directory otherwise
+func getLogSources() ([]logSource, error) {
+	nodeLogDirs, err := getNodeLogDirs()
+	if err != nil {
+		return nil, err
+	}
+	nodeNames := make([]string, 0, len(nodeLogDirs))
+	for nodeName := range nodeLogDirs {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	if logsNodeName != "" {
+		if _, ok := nodeLogDirs[logsNodeName]; !ok {
+			return nil, fmt.Errorf("node %q not found in local network. available nodes: %s", logsNodeName, strings.Join(nodeNames, ", "))
+		}
+		nodeNames = []string{logsNodeName}
+	}
+
+	logFileName := mainLogFileName
+	chainPrefix := ""
+	if logsChain != "" {
+		chainID, err := resolveLogChain(logsChain)
+		if err != nil {
+			return nil, err
+		}
+		logFileName = chainID + ".log"
+		chainPrefix = "/" + logsChain
+	}
+
+	sources := []logSource{}
+	for _, nodeName := range nodeNames {
+		sources = append(sources, logSource{
+			prefix: nodeName + chainPrefix,
+			path:   filepath.Join(nodeLogDirs[nodeName], logFileName),
+		})
+	}
+	if logsNodeName == "" && logsChain == "" {
+		if backendLogFile, err := binutils.GetBackendLogFile(app); err == nil && backendLogFile != "" {
+			sources = append(sources, logSource{prefix: backendLogPrefix, path: backendLogFile})
+		}
+	}
+	return sources, nil
+}
+
+// getNodeLogDirs maps node names to their log directories
+func getNodeLogDirs() (map[string]string, error) {
+	nodeLogDirs := map[string]string{}
+	if cli, err := binutils.NewGRPCClient(binutils.WithAvoidRPCVersionCheck(true)); err == nil {
+		if clusterInfo, err := getLocalClusterInfo(cli); err == nil {
+			for nodeName, nodeInfo := range clusterInfo.NodeInfos {
+				nodeLogDirs[nodeName] = nodeInfo.LogDir
+			}
+			return nodeLogDirs, nil
+		}
+	}
+	// network not running: use the latest run directory
+	networkDirs, err := filepath.Glob(filepath.Join(app.GetRunDir(), "network_*"))
+	if err != nil {
+		return nil, err
+	}
+	if len(networkDirs) == 0 {
+		return nil, fmt.Errorf("no local network logs found at %s", app.GetRunDir())
+	}
+	// timestamped names sort chronologically
+	sort.Strings(networkDirs)
+	latestNetworkDir := networkDirs[len(networkDirs)-1]
+	entries, err := os.ReadDir(latestNetworkDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		logDir := filepath.Join(latestNetworkDir, entry.Name(), "logs")
+		if entry.IsDir() && utils.DirectoryExists(logDir) {
+			nodeLogDirs[entry.Name()] = logDir
+		}
+	}
+	return nodeLogDirs, nil
+}
+
+// resolveLogChain returns the name of the log file (without extension) for [chain],
+// which can be a primary network chain alias, a blockchain ID, or a subnet name
+func resolveLogChain(chain string) (string, error) {
+	for _, primaryChain := range primaryChains {
+		if strings.EqualFold(chain, primaryChain) {
+			return primaryChain, nil
+		}
+	}
+	if _, err := ids.FromString(chain); err == nil {
+		return chain, nil
+	}
+	if cli, err := binutils.NewGRPCClient(binutils.WithAvoidRPCVersionCheck(true)); err == nil {
+		if clusterInfo, err := getLocalClusterInfo(cli); err == nil {
+			if blockchainID := getBlockchainIDByName(clusterInfo, chain); blockchainID != "" {
+				return blockchainID, nil
+			}
+		}
+	}
+	if app.SidecarExists(chain) {
+		sc, err := app.LoadSidecar(chain)
+		if err != nil {
+			return "", err
+		}
+		if blockchainID := sc.Networks[app.GetLocalNetwork().Name()].BlockchainID; blockchainID != ids.Empty {
+			return blockchainID.String(), nil
+		}
+	}
+	return "", fmt.Errorf("chain %q is not deployed on local network", chain)
+}
+
+func getBlockchainIDByName(clusterInfo *rpcpb.ClusterInfo, chainName string) string {
+	for blockchainID, chainInfo := range clusterInfo.CustomChains {
+		if chainInfo.ChainName == chainName {
+			return blockchainID
+		}
+	}
+	return ""
+}
+
+// parseLogLine extracts timestamp and level from an avalanchego log line, in either
+// plain or json format. Lines that can't be parsed (ex: stack traces) inherit the
+// timestamp and level of [prev]
+func parseLogLine(source string, line string, prev logEntry, now time.Time) logEntry {
+	entry := logEntry{Source: source, Message: line, Time: prev.Time, Level: prev.Level}
+	if strings.HasPrefix(line, "{") {
+		jsonLine := struct {
+			Level     string `json:"level"`
+			Timestamp string `json:"timestamp"`
+		}{}
+		if err := json.Unmarshal([]byte(line), &jsonLine); err == nil {
+			entry.Level = strings.ToUpper(jsonLine.Level)
+			if t, err := time.Parse(time.RFC3339Nano, jsonLine.Timestamp); err == nil {
+				entry.Time = t
+			}
+		}
+		return entry
+	}
+	if !strings.HasPrefix(line, "[") || len(line) < len(logTimestampLayout)+2 {
+		return entry
+	}
+	t, err := time.ParseInLocation(logTimestampLayout, line[1:len(logTimestampLayout)+1], now.Location())
+	if err != nil {
+		return entry
+	}
+	// the log timestamp has no year
+	t = time.Date(now.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), now.Location())
+	if t.After(now.Add(24 * time.Hour)) {
+		t = t.AddDate(-1, 0, 0)
+	}
+	entry.Time = t
+	fields := strings.Fields(line[len(logTimestampLayout)+2:])
+	if len(fields) > 0 {
+		if _, err := logging.ToLevel(fields[0]); err == nil {
+			entry.Level = strings.ToUpper(fields[0])
+		}
+	}
+	return entry
+}
+
+func readLogEntries(source logSource, filter logFilter) ([]logEntry, error) {
+	f, err := os.Open(source.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	entries := []logEntry{}
+	prev := logEntry{}
+	now := time.Now()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		entry := parseLogLine(source.prefix, scanner.Text(), prev, now)
+		prev = entry
+		if filter.accepts(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// followLog prints new lines of [source] until [ctx] is done. If a --since
+// filter was given, previous lines are printed first, otherwise it starts
+// at the end of the file
+func followLog(ctx context.Context, source logSource, filter logFilter, printer *logPrinter) {
+	var (
+		f      *os.File
+		reader *bufio.Reader
+		err    error
+	)
+	defer func() {
+		if f != nil {
+			_ = f.Close()
+		}
+	}()
+	prev := logEntry{}
+	partial := ""
+	for {
+		if f == nil {
+			// the file may not exist yet, ex: chain still being created
+			f, err = os.Open(source.path)
+			if err == nil {
+				if filter.since.IsZero() {
+					if _, err := f.Seek(0, io.SeekEnd); err != nil {
+						return
+					}
+				}
+				reader = bufio.NewReader(f)
+			} else {
+				f = nil
+			}
+		}
+		if f != nil {
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					partial += line
+					break
+				}
+				line = partial + strings.TrimRight(line, "\r\n")
+				partial = ""
+				entry := parseLogLine(source.prefix, line, prev, time.Now())
+				prev = entry
+				if filter.accepts(entry) {
+					printer.print(entry)
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(logPollInterval):
+		}
+	}
+}
+
+// logPrinter serializes the output of concurrently followed log sources
+type logPrinter struct {
+	lock        sync.Mutex
+	writer      io.Writer
+	useJSON     bool
+	encoder     *json.Encoder
+	prefixWidth int
+}
+
+func newLogPrinter(writer io.Writer, useJSON bool, sources []logSource) *logPrinter {
+	prefixWidth := 0
+	for _, source := range sources {
+		if len(source.prefix) > prefixWidth {
+			prefixWidth = len(source.prefix)
+		}
+	}
+	return &logPrinter{
+		writer:      writer,
+		useJSON:     useJSON,
+		encoder:     json.NewEncoder(writer),
+		prefixWidth: prefixWidth,
+	}
+}
+
+func (p *logPrinter) print(entry logEntry) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.useJSON {
+		_ = p.encoder.Encode(entry)
+		return
+	}
+	fmt.Fprintf(p.writer, "%-*s | %s\n", p.prefixWidth, entry.Source, entry.Message)
+}
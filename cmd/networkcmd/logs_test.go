@@ -0,0 +1,71 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLogLine(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2023, 10, 20, 12, 0, 0, 0, time.UTC)
+
+	entry := parseLogLine("node1", "[10-20|11:59:58.250] WARN chains/manager.go:123 some warning", logEntry{}, now)
+	require.Equal("node1", entry.Source)
+	require.Equal("WARN", entry.Level)
+	require.Equal(time.Date(2023, 10, 20, 11, 59, 58, 250000000, time.UTC), entry.Time)
+
+	// continuation lines inherit time and level
+	cont := parseLogLine("node1", "goroutine 1 [running]:", entry, now)
+	require.Equal(entry.Time, cont.Time)
+	require.Equal("WARN", cont.Level)
+	require.Equal("goroutine 1 [running]:", cont.Message)
+
+	// dates after now belong to previous year
+	entry = parseLogLine("node1", "[12-31|23:00:00.000] INFO msg", logEntry{}, now)
+	require.Equal(2022, entry.Time.Year())
+
+	entry = parseLogLine("node1", `{"level":"error","timestamp":"2023-10-20T11:00:00.000Z","msg":"boom"}`, logEntry{}, now)
+	require.Equal("ERROR", entry.Level)
+	require.Equal(time.Date(2023, 10, 20, 11, 0, 0, 0, time.UTC), entry.Time.UTC())
+}
+
+func TestReadLogEntriesFilter(t *testing.T) {
+	require := require.New(t)
+	logPath := filepath.Join(t.TempDir(), "main.log")
+	now := time.Now()
+	old := now.Add(-time.Hour).Format(logTimestampLayout)
+	recent := now.Add(-time.Minute).Format(logTimestampLayout)
+	content := "[" + old + "] ERROR old error\n" +
+		"[" + recent + "] DEBUG recent debug\n" +
+		"[" + recent + "] ERROR recent error\n" +
+		"stack trace line\n"
+	require.NoError(os.WriteFile(logPath, []byte(content), 0o600))
+
+	source := logSource{prefix: "node1", path: logPath}
+	entries, err := readLogEntries(source, logFilter{minLevel: logging.Verbo})
+	require.NoError(err)
+	require.Len(entries, 4)
+
+	entries, err = readLogEntries(source, logFilter{minLevel: logging.Info, since: now.Add(-10 * time.Minute)})
+	require.NoError(err)
+	require.Len(entries, 2)
+	require.Equal("["+recent+"] ERROR recent error", entries[0].Message)
+	require.Equal("stack trace line", entries[1].Message)
+
+	buf := &bytes.Buffer{}
+	printer := newLogPrinter(buf, false, []logSource{source, {prefix: "backend"}})
+	printer.print(entries[1])
+	require.Equal("node1   | stack trace line\n", buf.String())
+
+	entries, err = readLogEntries(logSource{prefix: "node1", path: logPath + ".missing"}, logFilter{})
+	require.NoError(err)
+	require.Empty(entries)
+}
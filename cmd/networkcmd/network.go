@@ -23,7 +23,12 @@ When you deploy a Subnet locally, it runs on a local, multi-node Avalanche netwo
 subnet deploy command starts this network in the background. This command suite allows you
 to shutdown, restart, and clear that network.
 
-This network currently supports multiple, concurrently deployed Subnets.`,
+This network currently supports multiple, concurrently deployed Subnets.
+
+Several independent local networks can run side by side by giving each one a name with the
+global --local-network flag. Each named network has its own snapshots, run files, backend
+ports and node ports. Subnet deployment info is kept for each local network, so a Subnet
+can be deployed to several of them.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			err := cmd.Help()
 			if err != nil {
@@ -40,5 +45,11 @@ This network currently supports multiple, concurrently deployed Subnets.`,
 	cmd.AddCommand(newCleanCmd())
 	// network status
 	cmd.AddCommand(newStatusCmd())
+	// network logs
+	cmd.AddCommand(newLogsCmd())
+	// network faucet
+	cmd.AddCommand(newFaucetCmd())
+	// network node
+	cmd.AddCommand(newNodeCmd())
 	return cmd
 }
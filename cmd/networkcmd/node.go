@@ -0,0 +1,280 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanche-network-runner/server"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	removeNodeData bool
+
+	ErrNoLocalNetwork = errors.New("no local network running")
+)
+
+func newNodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node",
+		Short: "Manage individual nodes of the local network",
+		Long: `The network node command suite provides a collection of tools to control the
+individual nodes of the running local network.
+
+Nodes can be listed, restarted, paused, resumed, added and removed
+without affecting the rest of the network, which makes it possible to test
+validator liveness and uptime locally.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+		Args: cobra.ExactArgs(0),
+	}
+	// network node list
+	cmd.AddCommand(newNodeListCmd())
+	// network node restart
+	cmd.AddCommand(newNodeRestartCmd())
+	// network node pause
+	cmd.AddCommand(newNodePauseCmd())
+	// network node resume
+	cmd.AddCommand(newNodeResumeCmd())
+	// network node add
+	cmd.AddCommand(newNodeAddCmd())
+	// network node remove
+	cmd.AddCommand(newNodeRemoveCmd())
+	return cmd
+}
+
+func newNodeListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "Lists the nodes of the local network",
+		Long: `The network node list command lists all nodes of the running local network,
+together with their NodeID, endpoint, state and tracked subnets.`,
+		RunE:         listNodes,
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+	}
+}
+
+func newNodeRestartCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restart [nodeName]",
+		Short: "Restarts a node of the local network",
+		Long: `The network node restart command restarts the given node of the local network,
+keeping its configuration, data and ports.`,
+		RunE:         restartNode,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+}
+
+func newNodePauseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pause [nodeName]",
+		Short: "Pauses a node of the local network",
+		Long: `The network node pause command stops the process of the given node while
+keeping it registered in the local network in paused state.
+
+A paused node can be brought back with network node resume.`,
+		RunE:         pauseNode,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+}
+
+func newNodeResumeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume [nodeName]",
+		Short: "Resumes a paused node of the local network",
+		Long: `The network node resume command starts again a previously paused node of the
+local network, using the same configuration, data and ports.`,
+		RunE:         resumeNode,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+}
+
+func newNodeRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove [nodeName]",
+		Short: "Removes a node from the local network",
+		Long: `The network node remove command stops the given node and removes it from the
+local network. Removed nodes can't be resumed.
+
+If you provide the --delete-data flag, the node data directory is also deleted.`,
+		RunE:         removeNode,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVar(&removeNodeData, "delete-data", false, "also delete the data directory of the removed node")
+	return cmd
+}
+
+// getLocalClusterInfo returns the cluster info of the running local network,
+// or ErrNoLocalNetwork if there is none
+func getLocalClusterInfo(cli client.Client) (*rpcpb.ClusterInfo, error) {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	status, err := cli.Status(ctx)
+	if err != nil {
+		if server.IsServerError(err, server.ErrNotBootstrapped) {
+			return nil, ErrNoLocalNetwork
+		}
+		return nil, err
+	}
+	if status == nil || status.ClusterInfo == nil {
+		return nil, ErrNoLocalNetwork
+	}
+	return status.ClusterInfo, nil
+}
+
+// getLocalNodeInfo returns the info of [nodeName] in the running local network
+func getLocalNodeInfo(cli client.Client, nodeName string) (*rpcpb.NodeInfo, error) {
+	clusterInfo, err := getLocalClusterInfo(cli)
+	if err != nil {
+		return nil, err
+	}
+	nodeInfo, ok := clusterInfo.NodeInfos[nodeName]
+	if !ok {
+		return nil, fmt.Errorf("node %q not found in local network. available nodes: %s", nodeName, strings.Join(clusterInfo.NodeNames, ", "))
+	}
+	return nodeInfo, nil
+}
+
+func listNodes(*cobra.Command, []string) error {
+	cli, err := binutils.NewGRPCClient()
+	if err != nil {
+		return err
+	}
+	clusterInfo, err := getLocalClusterInfo(cli)
+	if err != nil {
+		return err
+	}
+	nodeNames := clusterInfo.NodeNames
+	sort.Strings(nodeNames)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Node", "NodeID", "Endpoint", "State", "Tracked Subnets"})
+	table.SetRowLine(true)
+	for _, nodeName := range nodeNames {
+		nodeInfo, ok := clusterInfo.NodeInfos[nodeName]
+		if !ok {
+			continue
+		}
+		state := logging.Green.Wrap("Running")
+		if nodeInfo.Paused {
+			state = logging.Red.Wrap("Paused")
+		}
+		trackedSubnets := strings.ReplaceAll(nodeInfo.WhitelistedSubnets, ",", "\n")
+		table.Append([]string{nodeName, nodeInfo.Id, nodeInfo.Uri, state, trackedSubnets})
+	}
+	table.Render()
+	return nil
+}
+
+func restartNode(_ *cobra.Command, args []string) error {
+	nodeName := args[0]
+	cli, err := binutils.NewGRPCClient()
+	if err != nil {
+		return err
+	}
+	if _, err := getLocalNodeInfo(cli, nodeName); err != nil {
+		return err
+	}
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	ux.Logger.PrintToUser("Restarting node %s...", nodeName)
+	if _, err := cli.RestartNode(ctx, nodeName, client.WithPluginDir(app.GetPluginsDir())); err != nil {
+		return fmt.Errorf("failed to restart node %s: %w", nodeName, err)
+	}
+	ux.Logger.PrintToUser("Node %s restarted", nodeName)
+	return nil
+}
+
+func pauseNode(_ *cobra.Command, args []string) error {
+	nodeName := args[0]
+	cli, err := binutils.NewGRPCClient()
+	if err != nil {
+		return err
+	}
+	nodeInfo, err := getLocalNodeInfo(cli, nodeName)
+	if err != nil {
+		return err
+	}
+	if nodeInfo.Paused {
+		ux.Logger.PrintToUser("Node %s is already paused", nodeName)
+		return nil
+	}
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	if _, err := cli.PauseNode(ctx, nodeName); err != nil {
+		return fmt.Errorf("failed to pause node %s: %w", nodeName, err)
+	}
+	ux.Logger.PrintToUser("Node %s paused", nodeName)
+	return nil
+}
+
+func resumeNode(_ *cobra.Command, args []string) error {
+	nodeName := args[0]
+	cli, err := binutils.NewGRPCClient()
+	if err != nil {
+		return err
+	}
+	nodeInfo, err := getLocalNodeInfo(cli, nodeName)
+	if err != nil {
+		return err
+	}
+	if !nodeInfo.Paused {
+		ux.Logger.PrintToUser("Node %s is already running", nodeName)
+		return nil
+	}
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	if _, err := cli.ResumeNode(ctx, nodeName); err != nil {
+		return fmt.Errorf("failed to resume node %s: %w", nodeName, err)
+	}
+	ux.Logger.PrintToUser("Node %s resumed", nodeName)
+	return nil
+}
+
+func removeNode(_ *cobra.Command, args []string) error {
+	nodeName := args[0]
+	cli, err := binutils.NewGRPCClient()
+	if err != nil {
+		return err
+	}
+	nodeInfo, err := getLocalNodeInfo(cli, nodeName)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	if _, err := cli.RemoveNode(ctx, nodeName); err != nil {
+		return fmt.Errorf("failed to remove node %s: %w", nodeName, err)
+	}
+	ux.Logger.PrintToUser("Node %s removed from local network", nodeName)
+	if removeNodeData && nodeInfo.DbDir != "" {
+		// db dir is located inside the node data dir
+		nodeDataDir := filepath.Dir(nodeInfo.DbDir)
+		if err := os.RemoveAll(nodeDataDir); err != nil {
+			return fmt.Errorf("failed to delete node data at %s: %w", nodeDataDir, err)
+		}
+		ux.Logger.PrintToUser("Node data at %s deleted", nodeDataDir)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+)
+
+const trackSubnetsKey = "track-subnets"
+
+var (
+	addNodeTrackSubnets     bool
+	addNodePrimaryValidator bool
+)
+
+func newNodeAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add [nodeName]",
+		Short: "Adds a new node to the local network",
+		Long: `The network node add command adds a new node to the running local network.
+
+If no node name is given, the next free nodeN name is used. The new node uses the
+same avalanchego binary, plugin dir and global node config as the rest of the network.
+
+If you provide the --track-subnets flag, the node tracks all subnets currently deployed
+on the local network. If you provide the --primary-validator flag, the node is also added
+as a Primary Network validator using the ewoq key, so that it can afterwards be added as
+a subnet validator with avalanche subnet addValidator --local.`,
+		RunE:         addNode,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVar(&addNodeTrackSubnets, "track-subnets", false, "make the new node track all locally deployed subnets")
+	cmd.Flags().BoolVar(&addNodePrimaryValidator, "primary-validator", false, "add the new node as a Primary Network validator")
+	return cmd
+}
+
+func addNode(_ *cobra.Command, args []string) error {
+	cli, err := binutils.NewGRPCClient()
+	if err != nil {
+		return err
+	}
+	clusterInfo, err := getLocalClusterInfo(cli)
+	if err != nil {
+		return err
+	}
+
+	var nodeName string
+	if len(args) > 0 {
+		nodeName = args[0]
+		if _, ok := clusterInfo.NodeInfos[nodeName]; ok {
+			return fmt.Errorf("node %q already exists in local network", nodeName)
+		}
+	} else {
+		nodeName = getNextNodeName(clusterInfo)
+	}
+
+	// reuse the binary of the nodes already running
+	execPath := ""
+	for _, nodeInfo := range clusterInfo.NodeInfos {
+		execPath = nodeInfo.ExecPath
+		break
+	}
+
+	nodeConfig := map[string]interface{}{}
+	configStr, err := app.Conf.LoadNodeConfig()
+	if err != nil {
+		return err
+	}
+	if configStr != "" {
+		if err := json.Unmarshal([]byte(configStr), &nodeConfig); err != nil {
+			return fmt.Errorf("invalid global node config: %w", err)
+		}
+	}
+	if addNodeTrackSubnets {
+		subnetIDs := getDeployedSubnetIDs(clusterInfo)
+		if len(subnetIDs) == 0 {
+			ux.Logger.PrintToUser("No subnets deployed on local network. Node will only track the Primary Network")
+		} else {
+			nodeConfig[trackSubnetsKey] = strings.Join(subnetIDs, ",")
+		}
+	}
+	nodeConfigBytes, err := json.Marshal(nodeConfig)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+
+	if app.IsDeterministicLocalNetwork() {
+		ux.Logger.PrintToUser("Warning: added nodes get generated staking keys, so the network is no longer deterministic")
+	}
+	ux.Logger.PrintToUser("Adding node %s to local network...", nodeName)
+	if _, err := cli.AddNode(
+		ctx,
+		nodeName,
+		execPath,
+		client.WithPluginDir(app.GetPluginsDir()),
+		client.WithGlobalNodeConfig(string(nodeConfigBytes)),
+	); err != nil {
+		return fmt.Errorf("failed to add node %s: %w", nodeName, err)
+	}
+
+	ux.Logger.PrintToUser("Waiting for network to be healthy...")
+	clusterInfo, err = subnet.WaitForHealthy(ctx, cli)
+	if err != nil {
+		return fmt.Errorf("failed waiting for network to become healthy: %w", err)
+	}
+	fmt.Println()
+
+	nodeInfo, ok := clusterInfo.NodeInfos[nodeName]
+	if !ok {
+		return fmt.Errorf("node %s not found in local network after being added", nodeName)
+	}
+	ux.Logger.PrintToUser("Node %s added with NodeID %s and endpoint %s", nodeName, nodeInfo.Id, nodeInfo.Uri)
+
+	if addNodePrimaryValidator {
+		if err := addLocalPrimaryValidator(nodeInfo.Uri); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("To make this node validate a subnet, run: avalanche subnet addValidator <subnetName> --local --nodeID %s", nodeInfo.Id)
+	}
+	return nil
+}
+
+// getNextNodeName returns the first nodeN name not yet used in the local network
+func getNextNodeName(clusterInfo *rpcpb.ClusterInfo) string {
+	for i := 1; ; i++ {
+		nodeName := fmt.Sprintf("node%d", i)
+		if _, ok := clusterInfo.NodeInfos[nodeName]; !ok {
+			return nodeName
+		}
+	}
+}
+
+// getDeployedSubnetIDs returns the sorted IDs of the subnets that have at least one
+// blockchain deployed on the local network
+func getDeployedSubnetIDs(clusterInfo *rpcpb.ClusterInfo) []string {
+	subnetIDs := map[string]struct{}{}
+	for _, chainInfo := range clusterInfo.CustomChains {
+		subnetIDs[chainInfo.SubnetId] = struct{}{}
+	}
+	sortedSubnetIDs := maps.Keys(subnetIDs)
+	sort.Strings(sortedSubnetIDs)
+	return sortedSubnetIDs
+}
+
+// addLocalPrimaryValidator adds the node at [nodeURI] as a Primary Network validator of the
+// local network, staking the minimum amount for the minimum duration with the ewoq key
+func addLocalPrimaryValidator(nodeURI string) error {
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	nodeID, proofOfPossession, err := info.NewClient(nodeURI).GetNodeID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get node BLS info: %w", err)
+	}
+	network := app.GetLocalNetwork()
+	sk, err := key.LoadEwoq(network.ID)
+	if err != nil {
+		return err
+	}
+	kc := sk.KeyChain()
+	start := time.Now().Add(constants.PrimaryNetworkValidatingStartLeadTimeNodeCmd)
+	end := start.Add(genesis.LocalParams.MinStakeDuration)
+	ux.Logger.PrintToUser("Adding node %s as Primary Network validator, staking from %s to %s", nodeID, start.Format(constants.TimeParseLayout), end.Format(constants.TimeParseLayout))
+	deployer := subnet.NewPublicDeployer(app, false, kc, network)
+	_, err = deployer.AddPermissionlessValidator(
+		ids.Empty,
+		ids.Empty,
+		nodeID,
+		genesis.LocalParams.MinValidatorStake,
+		uint64(start.Unix()),
+		uint64(end.Unix()),
+		kc.Addresses().List()[0],
+		genesis.LocalParams.MinDelegationFee,
+		nil,
+		proofOfPossession,
+	)
+	return err
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNextNodeName(t *testing.T) {
+	require := require.New(t)
+	clusterInfo := &rpcpb.ClusterInfo{
+		NodeInfos: map[string]*rpcpb.NodeInfo{
+			"node1": {},
+			"node2": {},
+			"node4": {},
+		},
+	}
+	require.Equal("node3", getNextNodeName(clusterInfo))
+	clusterInfo.NodeInfos["node3"] = &rpcpb.NodeInfo{}
+	require.Equal("node5", getNextNodeName(clusterInfo))
+	delete(clusterInfo.NodeInfos, "node1")
+	require.Equal("node1", getNextNodeName(clusterInfo))
+	require.Equal("node1", getNextNodeName(&rpcpb.ClusterInfo{}))
+}
+
+func TestGetDeployedSubnetIDs(t *testing.T) {
+	require := require.New(t)
+	clusterInfo := &rpcpb.ClusterInfo{
+		CustomChains: map[string]*rpcpb.CustomChainInfo{
+			"chain1": {SubnetId: "subnetB"},
+			"chain2": {SubnetId: "subnetA"},
+			"chain3": {SubnetId: "subnetB"},
+		},
+	}
+	require.Equal([]string{"subnetA", "subnetB"}, getDeployedSubnetIDs(clusterInfo))
+	require.Empty(getDeployedSubnetIDs(&rpcpb.ClusterInfo{}))
+}
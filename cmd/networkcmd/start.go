@@ -4,12 +4,17 @@ package networkcmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"path"
+	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/primarygenesis"
 	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
@@ -17,12 +22,16 @@ import (
 	"github.com/ava-labs/avalanche-network-runner/client"
 	"github.com/ava-labs/avalanche-network-runner/server"
 	anrutils "github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/spf13/cobra"
 )
 
 var (
 	userProvidedAvagoVersion string
 	snapshotName             string
+	deterministic            bool
+	genesisPath              string
 )
 
 const latest = "latest"
@@ -35,7 +44,32 @@ func newStartCmd() *cobra.Command {
 
 By default, the command loads the default snapshot. If you provide the --snapshot-name
 flag, the network loads that snapshot instead. The command fails if the local network is
-already running.`,
+already running.
+
+If you provide the --deterministic flag, the network is started from a clean copy of the
+bootstrap snapshot, which has fixed staking keys and a fixed genesis start time, discarding
+any previous state of the default snapshot. Subnets deployed afterwards with subnet deploy
+--local are assigned the preloaded Subnet IDs in deploy order, so running the same commands
+on a clean network yields the same Subnet IDs and Blockchain IDs.
+
+If you provide the --genesis flag, a fresh network is booted from a custom primary network
+genesis, discarding any previous state of the default snapshot. The file can either be a
+full genesis, whose initial stakers must be local network nodes, or a genesis spec, from
+which a genesis is generated with the local nodes as initial stakers, and with the ewoq key
+funded on all chains, or else the stored key given as fundedKey. A spec can set the initial
+stake duration and offset, the staking params of the network, and additional funded addresses:
+{
+  "initialStakeDuration": 31536000,
+  "initialStakeDurationOffset": 5400,
+  "minValidatorStake": 2000000000000,
+  "minDelegatorStake": 25000000000,
+  "minStakeDuration": 86400,
+  "maxStakeDuration": 31536000,
+  "allocations": [{"avaxAddr": "X-local1...", "xAmount": 1000000000, "pAmount": 1000000000}],
+  "cChainAllocations": {"0x...": "0x52B7D2DCC80CD2E4000000"}
+}
+The genesis is validated before booting the network. Subnets deployed to a network with a
+custom genesis are created on their own new Subnet.`,
 
 		RunE:         StartNetwork,
 		Args:         cobra.ExactArgs(0),
@@ -44,11 +78,27 @@ already running.`,
 
 	cmd.Flags().StringVar(&userProvidedAvagoVersion, "avalanchego-version", latest, "use this version of avalanchego (ex: v1.17.12)")
 	cmd.Flags().StringVar(&snapshotName, "snapshot-name", constants.DefaultSnapshotName, "name of snapshot to use to start the network from")
+	cmd.Flags().BoolVar(&deterministic, "deterministic", false, "start a clean network with fixed keys and genesis, for reproducible deploys")
+	cmd.Flags().StringVar(&genesisPath, "genesis", "", "start a fresh network from this primary network genesis or genesis spec file")
 
 	return cmd
 }
 
 func StartNetwork(*cobra.Command, []string) error {
+	if deterministic && snapshotName != constants.DefaultSnapshotName {
+		return errors.New("--deterministic can't be used together with --snapshot-name")
+	}
+	if genesisPath != "" && (deterministic || snapshotName != constants.DefaultSnapshotName) {
+		return errors.New("--genesis can't be used together with --deterministic or --snapshot-name")
+	}
+	var genesisBytes []byte
+	if genesisPath != "" {
+		var err error
+		genesisBytes, err = os.ReadFile(genesisPath)
+		if err != nil {
+			return fmt.Errorf("failed reading genesis file: %w", err)
+		}
+	}
 	avagoVersion, err := determineAvagoVersion(userProvidedAvagoVersion)
 	if err != nil {
 		return err
@@ -83,6 +133,20 @@ func StartNetwork(*cobra.Command, []string) error {
 		return nil
 	}
 
+	if deterministic {
+		if err := resetDeterministicSnapshot(); err != nil {
+			return err
+		}
+	}
+	if genesisPath != "" {
+		if err := setCustomGenesisSnapshot(genesisBytes); err != nil {
+			return err
+		}
+	}
+	if err := app.SetDeterministicLocalNetwork(deterministic); err != nil {
+		return err
+	}
+
 	var startMsg string
 	if snapshotName == constants.DefaultSnapshotName {
 		startMsg = "Starting previously deployed and stopped snapshot"
@@ -99,6 +163,12 @@ func StartNetwork(*cobra.Command, []string) error {
 
 	pluginDir := app.GetPluginsDir()
 
+	if offset := app.GetLocalNetworkPortsOffset(); offset != 0 {
+		if err := subnet.SetSnapshotNodePorts(app.GetSnapshotsDir(), snapshotName, constants.AvalanchegoAPIPort+offset); err != nil {
+			return err
+		}
+	}
+
 	loadSnapshotOpts := []client.OpOption{
 		client.WithExecPath(avalancheGoBinPath),
 		client.WithRootDataDir(outputDir),
@@ -137,6 +207,98 @@ func StartNetwork(*cobra.Command, []string) error {
 	return nil
 }
 
+// resetDeterministicSnapshot replaces the default snapshot with the bootstrap one, and
+// checks that booting it does not depend on generated keys or on wall-clock time
+func resetDeterministicSnapshot() error {
+	ux.Logger.PrintToUser("Deterministic mode: resetting default snapshot to the bootstrap snapshot")
+	if err := subnet.SetDefaultSnapshot(app.GetSnapshotsDir(), true); err != nil {
+		return fmt.Errorf("failed resetting default snapshot: %w", err)
+	}
+	if offset := app.GetLocalNetworkPortsOffset(); offset != 0 {
+		if err := subnet.SetSnapshotNodePorts(app.GetSnapshotsDir(), constants.DefaultSnapshotName, constants.AvalanchegoAPIPort+offset); err != nil {
+			return err
+		}
+	}
+	genesisStartTime, err := subnet.CheckDeterministicSnapshot(app.GetSnapshotsDir(), constants.DefaultSnapshotName)
+	if err != nil {
+		return fmt.Errorf("bootstrap snapshot can't be used for a deterministic network: %w", err)
+	}
+	ux.Logger.PrintToUser("Using fixed staking keys and genesis start time %s", genesisStartTime.Format(time.RFC3339))
+	return nil
+}
+
+// setCustomGenesisSnapshot replaces the default snapshot with a fresh one that boots from
+// [genesisBytes], which contain either a full primary network genesis or a genesis spec
+func setCustomGenesisSnapshot(genesisBytes []byte) error {
+	ux.Logger.PrintToUser("Custom genesis: resetting default snapshot")
+	if err := subnet.SetDefaultSnapshot(app.GetSnapshotsDir(), true); err != nil {
+		return fmt.Errorf("failed resetting default snapshot: %w", err)
+	}
+	if offset := app.GetLocalNetworkPortsOffset(); offset != 0 {
+		if err := subnet.SetSnapshotNodePorts(app.GetSnapshotsDir(), constants.DefaultSnapshotName, constants.AvalanchegoAPIPort+offset); err != nil {
+			return err
+		}
+	}
+	nodeIDs, err := subnet.GetSnapshotNodeIDs(app.GetSnapshotsDir(), constants.DefaultSnapshotName)
+	if err != nil {
+		return err
+	}
+	spec := &primarygenesis.Spec{}
+	if primarygenesis.IsFullGenesis(genesisBytes) {
+		ux.Logger.PrintToUser("Using genesis %s", genesisPath)
+	} else {
+		spec, err = primarygenesis.LoadSpec(genesisPath)
+		if err != nil {
+			return err
+		}
+		if spec.NetworkID != 0 && spec.NetworkID != constants.LocalNetworkID {
+			return fmt.Errorf("the network ID of the local network can't be changed from %d", constants.LocalNetworkID)
+		}
+		if spec.FundedKey != "" {
+			if err := spec.SetFundedKey(constants.LocalNetworkID, app.GetKeyPath(spec.FundedKey)); err != nil {
+				return err
+			}
+		}
+		genesisBytes, err = generateLocalGenesis(spec, nodeIDs)
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Generated genesis from spec %s", genesisPath)
+	}
+	stakers, err := primarygenesis.Validate(constants.LocalNetworkID, genesisBytes, spec)
+	if err != nil {
+		return fmt.Errorf("invalid genesis: %w", err)
+	}
+	localNodeIDs := set.Of(nodeIDs...)
+	for _, staker := range stakers {
+		if !localNodeIDs.Contains(staker) {
+			return fmt.Errorf("invalid genesis: initial staker %s is not a local network node. Local node IDs are %s", staker, localNodeIDs)
+		}
+	}
+	return subnet.SetSnapshotGenesis(app.GetSnapshotsDir(), constants.DefaultSnapshotName, genesisBytes, spec.NodeFlags())
+}
+
+// generateLocalGenesis generates a local network genesis from [spec], where [nodeIDs] are
+// the initial stakers, and the ewoq key is funded
+func generateLocalGenesis(spec *primarygenesis.Spec, nodeIDs []ids.NodeID) ([]byte, error) {
+	// get random staking key for the genesis
+	k, err := key.NewSoft(constants.LocalNetworkID)
+	if err != nil {
+		return nil, err
+	}
+	stakingAddrStr := k.X()[0]
+	k, err = key.LoadEwoq(constants.LocalNetworkID)
+	if err != nil {
+		return nil, err
+	}
+	walletAddrStr := k.X()[0]
+	nodeIDStrs := make([]string, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		nodeIDStrs[i] = nodeID.String()
+	}
+	return primarygenesis.Generate(constants.LocalNetworkID, spec, walletAddrStr, stakingAddrStr, nodeIDStrs)
+}
+
 func determineAvagoVersion(userProvidedAvagoVersion string) (string, error) {
 	// a specific user provided version should override this calculation, so just return
 	if userProvidedAvagoVersion != latest {
@@ -165,15 +327,15 @@ func determineAvagoVersion(userProvidedAvagoVersion string) (string, error) {
 
 		// if you have a custom vm, you must provide the version explicitly
 		// if you upgrade from subnet-evm to a custom vm, the RPC version will be 0
-		if sc.VM == models.CustomVM || sc.Networks[models.Local.String()].RPCVersion == 0 {
+		if sc.VM == models.CustomVM || sc.Networks[app.GetLocalNetwork().Name()].RPCVersion == 0 {
 			continue
 		}
 
 		if currentRPCVersion == -1 {
-			currentRPCVersion = sc.Networks[models.Local.String()].RPCVersion
+			currentRPCVersion = sc.Networks[app.GetLocalNetwork().Name()].RPCVersion
 		}
 
-		if sc.Networks[models.Local.String()].RPCVersion != currentRPCVersion {
+		if sc.Networks[app.GetLocalNetwork().Name()].RPCVersion != currentRPCVersion {
 			return "", fmt.Errorf(
 				"RPC version mismatch. Expected %d, got %d for Subnet %s. Upgrade all subnets to the same RPC version to launch the network",
 				currentRPCVersion,
@@ -3,27 +3,46 @@
 package networkcmd
 
 import (
+	"time"
+
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-network-runner/server"
 	"github.com/spf13/cobra"
 )
 
+var (
+	watchStatus         bool
+	watchStatusInterval time.Duration
+)
+
 func newStatusCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Prints the status of the local network",
 		Long: `The network status command prints whether or not a local Avalanche
-network is running and some basic stats about the network.`,
+network is running and some basic stats about the network.
+
+If you provide the --watch flag, the command shows a dashboard that is refreshed
+every --interval, with the health, bootstrap state and P-Chain height of each node,
+and the latest block, block time and pending transactions of each deployed chain.`,
 
 		RunE:         networkStatus,
 		Args:         cobra.ExactArgs(0),
 		SilenceUsage: true,
 	}
+	cmd.Flags().BoolVarP(&watchStatus, "watch", "w", false, "keep refreshing the network status until interrupted")
+	cmd.Flags().DurationVar(&watchStatusInterval, "interval", 5*time.Second, "refresh interval for --watch")
+	return cmd
 }
 
 func networkStatus(*cobra.Command, []string) error {
+	if watchStatus {
+		return watchNetworkStatus(watchStatusInterval)
+	}
+
 	ux.Logger.PrintToUser("Requesting network status...")
 
 	cli, err := binutils.NewGRPCClient()
@@ -49,14 +68,15 @@ func networkStatus(*cobra.Command, []string) error {
 		ux.Logger.PrintToUser("Healthy: %t", status.ClusterInfo.Healthy)
 		ux.Logger.PrintToUser("Custom VMs healthy: %t", status.ClusterInfo.CustomChainsHealthy)
 		ux.Logger.PrintToUser("Number of nodes: %d", len(status.ClusterInfo.NodeNames))
-		ux.Logger.PrintToUser("Number of custom VMs: %d", len(status.ClusterInfo.CustomChains))
+		customChains := subnet.GetTrackedCustomChains(status.ClusterInfo)
+		ux.Logger.PrintToUser("Number of custom VMs: %d", len(customChains))
 		ux.Logger.PrintToUser("======================================== Node information ========================================")
 		for n, nodeInfo := range status.ClusterInfo.NodeInfos {
 			ux.Logger.PrintToUser("%s has ID %s and endpoint %s ", n, nodeInfo.Id, nodeInfo.Uri)
 		}
 		ux.Logger.PrintToUser("==================================== Custom VM information =======================================")
 		for _, nodeInfo := range status.ClusterInfo.NodeInfos {
-			for blockchainID := range status.ClusterInfo.CustomChains {
+			for blockchainID := range customChains {
 				ux.Logger.PrintToUser("Endpoint at %s for blockchain %q: %s/ext/bc/%s/rpc", nodeInfo.Name, blockchainID, nodeInfo.GetUri(), blockchainID)
 			}
 		}
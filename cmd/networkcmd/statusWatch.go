@@ -0,0 +1,257 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/signal"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/coreth/ethclient"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	statusWatchRequestTimeout = 3 * time.Second
+	clearScreen               = "\033[H\033[2J"
+	notAvailable              = "-"
+)
+
+type nodeStatus struct {
+	name         string
+	nodeID       string
+	uri          string
+	paused       bool
+	reachable    bool
+	healthy      bool
+	bootstrapped bool
+	pHeight      uint64
+}
+
+type chainStatus struct {
+	name         string
+	blockchainID string
+	reachable    bool
+	blockNumber  uint64
+	blockTime    time.Time
+	pendingTxs   uint64
+}
+
+// watchNetworkStatus refreshes a status dashboard of the local network every
+// [interval] until interrupted. Nodes or chains that can't be reached are shown
+// as such instead of failing
+func watchNetworkStatus(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid refresh interval %s", interval)
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	for {
+		buf := &bytes.Buffer{}
+		clusterInfo, err := getWatchedClusterInfo()
+		if err != nil {
+			renderStatusHeader(buf, interval, time.Now())
+			fmt.Fprintf(buf, "%s\n", logging.Red.Wrap(fmt.Sprintf("Local network unavailable: %s", err)))
+		} else {
+			nodes, chains := collectNetworkStatus(ctx, clusterInfo)
+			renderStatusDashboard(buf, interval, time.Now(), nodes, chains)
+		}
+		fmt.Print(clearScreen)
+		fmt.Print(buf.String())
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func getWatchedClusterInfo() (*rpcpb.ClusterInfo, error) {
+	cli, err := binutils.NewGRPCClient(binutils.WithAvoidRPCVersionCheck(true))
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+	return getLocalClusterInfo(cli)
+}
+
+// collectNetworkStatus queries all nodes and chains in parallel
+func collectNetworkStatus(ctx context.Context, clusterInfo *rpcpb.ClusterInfo) ([]nodeStatus, []chainStatus) {
+	nodeNames := make([]string, 0, len(clusterInfo.NodeInfos))
+	for nodeName := range clusterInfo.NodeInfos {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	customChains := subnet.GetTrackedCustomChains(clusterInfo)
+	blockchainIDs := make([]string, 0, len(customChains))
+	for blockchainID := range customChains {
+		blockchainIDs = append(blockchainIDs, blockchainID)
+	}
+	sort.Slice(blockchainIDs, func(i, j int) bool {
+		return customChains[blockchainIDs[i]].ChainName < customChains[blockchainIDs[j]].ChainName
+	})
+
+	nodes := make([]nodeStatus, len(nodeNames))
+	wg := sync.WaitGroup{}
+	for i, nodeName := range nodeNames {
+		nodeInfo := clusterInfo.NodeInfos[nodeName]
+		nodes[i] = nodeStatus{
+			name:   nodeName,
+			nodeID: nodeInfo.Id,
+			uri:    nodeInfo.Uri,
+			paused: nodeInfo.Paused,
+		}
+		if nodeInfo.Paused {
+			continue
+		}
+		wg.Add(1)
+		go func(status *nodeStatus) {
+			defer wg.Done()
+			queryNodeStatus(ctx, status)
+		}(&nodes[i])
+	}
+	wg.Wait()
+
+	// chain info is taken from the first node answering its API
+	uris := []string{}
+	for _, node := range nodes {
+		if node.reachable {
+			uris = append(uris, node.uri)
+		}
+	}
+	chains := make([]chainStatus, len(blockchainIDs))
+	for i, blockchainID := range blockchainIDs {
+		chains[i] = chainStatus{
+			name:         customChains[blockchainID].ChainName,
+			blockchainID: blockchainID,
+		}
+		wg.Add(1)
+		go func(status *chainStatus) {
+			defer wg.Done()
+			for _, uri := range uris {
+				if queryChainStatus(ctx, uri, status) {
+					return
+				}
+			}
+		}(&chains[i])
+	}
+	wg.Wait()
+	return nodes, chains
+}
+
+func queryNodeStatus(ctx context.Context, status *nodeStatus) {
+	ctx, cancel := context.WithTimeout(ctx, statusWatchRequestTimeout)
+	defer cancel()
+	healthReply, err := health.NewClient(status.uri).Health(ctx, nil)
+	if err != nil {
+		return
+	}
+	status.reachable = true
+	status.healthy = healthReply.Healthy
+	if bootstrapped, err := info.NewClient(status.uri).IsBootstrapped(ctx, "P"); err == nil {
+		status.bootstrapped = bootstrapped
+	}
+	if height, err := platformvm.NewClient(status.uri).GetHeight(ctx); err == nil {
+		status.pHeight = height
+	}
+}
+
+// queryChainStatus fills [status] with the chain info obtained from the node at [uri],
+// returning false if the chain RPC could not be reached
+func queryChainStatus(ctx context.Context, uri string, status *chainStatus) bool {
+	ctx, cancel := context.WithTimeout(ctx, statusWatchRequestTimeout)
+	defer cancel()
+	ethClient, err := ethclient.DialContext(ctx, fmt.Sprintf("%s/ext/bc/%s/rpc", uri, status.blockchainID))
+	if err != nil {
+		return false
+	}
+	defer ethClient.Close()
+	header, err := ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return false
+	}
+	status.reachable = true
+	status.blockNumber = header.Number.Uint64()
+	status.blockTime = time.Unix(int64(header.Time), 0)
+	txPoolStatus := struct {
+		Pending hexutil.Uint64 `json:"pending"`
+		Queued  hexutil.Uint64 `json:"queued"`
+	}{}
+	if err := ethClient.Client().CallContext(ctx, &txPoolStatus, "txpool_status"); err == nil {
+		status.pendingTxs = uint64(txPoolStatus.Pending)
+	}
+	return true
+}
+
+func renderStatusHeader(w io.Writer, interval time.Duration, now time.Time) {
+	fmt.Fprintf(w, "Local network status at %s (refreshing every %s, Ctrl+C to exit)\n\n", now.Format(time.TimeOnly), interval)
+}
+
+func renderStatusDashboard(w io.Writer, interval time.Duration, now time.Time, nodes []nodeStatus, chains []chainStatus) {
+	renderStatusHeader(w, interval, now)
+
+	nodesTable := tablewriter.NewWriter(w)
+	nodesTable.SetHeader([]string{"Node", "NodeID", "Endpoint", "Healthy", "Bootstrapped", "P-Chain Height"})
+	nodesTable.SetRowLine(true)
+	for _, node := range nodes {
+		healthy, bootstrapped, pHeight := notAvailable, notAvailable, notAvailable
+		switch {
+		case node.paused:
+			healthy = logging.Yellow.Wrap("Paused")
+		case !node.reachable:
+			healthy = logging.Red.Wrap("Unreachable")
+		default:
+			healthy = boolStatus(node.healthy)
+			bootstrapped = boolStatus(node.bootstrapped)
+			pHeight = strconv.FormatUint(node.pHeight, 10)
+		}
+		nodesTable.Append([]string{node.name, node.nodeID, node.uri, healthy, bootstrapped, pHeight})
+	}
+	nodesTable.Render()
+
+	if len(chains) == 0 {
+		fmt.Fprintln(w, "\nNo subnets deployed")
+		return
+	}
+	fmt.Fprintln(w)
+	chainsTable := tablewriter.NewWriter(w)
+	chainsTable.SetHeader([]string{"Chain", "BlockchainID", "Latest Block", "Block Time", "Pending Txs"})
+	chainsTable.SetRowLine(true)
+	for _, chain := range chains {
+		if !chain.reachable {
+			chainsTable.Append([]string{chain.name, chain.blockchainID, logging.Red.Wrap("Unreachable"), notAvailable, notAvailable})
+			continue
+		}
+		blockTime := fmt.Sprintf("%s (%s ago)", chain.blockTime.Format(time.TimeOnly), now.Sub(chain.blockTime).Truncate(time.Second))
+		chainsTable.Append([]string{
+			chain.name,
+			chain.blockchainID,
+			strconv.FormatUint(chain.blockNumber, 10),
+			blockTime,
+			strconv.FormatUint(chain.pendingTxs, 10),
+		})
+	}
+	chainsTable.Render()
+}
+
+func boolStatus(b bool) string {
+	if b {
+		return logging.Green.Wrap("Yes")
+	}
+	return logging.Red.Wrap("No")
+}
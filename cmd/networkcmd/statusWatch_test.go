@@ -0,0 +1,38 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package networkcmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderStatusDashboard(t *testing.T) {
+	require := require.New(t)
+	now := time.Date(2023, 10, 20, 12, 0, 0, 0, time.UTC)
+	nodes := []nodeStatus{
+		{name: "node1", nodeID: "NodeID-1", uri: "http://127.0.0.1:9650", reachable: true, healthy: true, bootstrapped: true, pHeight: 42},
+		{name: "node2", nodeID: "NodeID-2", uri: "http://127.0.0.1:9652"},
+		{name: "node3", nodeID: "NodeID-3", uri: "http://127.0.0.1:9654", paused: true},
+	}
+	chains := []chainStatus{
+		{name: "mychain", blockchainID: "abc", reachable: true, blockNumber: 7, blockTime: now.Add(-90 * time.Second), pendingTxs: 3},
+		{name: "otherchain", blockchainID: "def"},
+	}
+	buf := &bytes.Buffer{}
+	renderStatusDashboard(buf, 5*time.Second, now, nodes, chains)
+	out := buf.String()
+	require.Contains(out, "refreshing every 5s")
+	require.Contains(out, "42")
+	require.Contains(out, "Unreachable")
+	require.Contains(out, "Paused")
+	require.Contains(out, "1m30s ago")
+	require.Contains(out, "otherchain")
+
+	buf.Reset()
+	renderStatusDashboard(buf, time.Second, now, nodes, nil)
+	require.Contains(buf.String(), "No subnets deployed")
+}
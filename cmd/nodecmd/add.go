@@ -0,0 +1,184 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/cmd/subnetcmd"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var trackSubnets []string
+
+func newAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add [clusterName]",
+		Short: "(ALPHA Warning) Add new nodes to a cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node add command grows an existing cluster with new validator node/s.
+New nodes are created on the same cloud service, region and server
+configuration as the cluster
+(or on the local machine, or the servers given with --existing-hosts, when
+the cluster was created that way), set up with the AvalancheGo version the
+cluster is running, and added to the cluster inventory.
+
+If the cluster runs a Devnet, the new nodes join it by bootstrapping from
+the other nodes of the cluster. Use --track-subnets to also have the new
+nodes sync with the given Subnets.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         addNodes,
+	}
+	cmd.Flags().IntVar(&numNodes, "num-nodes", 0, "number of nodes to add")
+	cmd.Flags().BoolVar(&useStaticIP, "use-static-ip", true, "attach static Public IP on cloud servers")
+	cmd.Flags().BoolVar(&authorizeAccess, "authorize-access", false, "authorize CLI to create cloud resources")
+	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
+	cmd.Flags().BoolVar(&useLatestAvalanchegoVersion, "latest-avalanchego-version", false, "install latest avalanchego version on new node/s")
+	cmd.Flags().StringVar(&useAvalanchegoVersionFromSubnet, "avalanchego-version-from-subnet", "", "install latest avalanchego version, that is compatible with the given subnet, on new node/s")
+	cmd.Flags().StringVar(&existingHostsPath, "existing-hosts", "", "add the existing servers listed in given JSON file, for clusters created with --existing-hosts")
+	cmd.Flags().StringSliceVar(&trackSubnets, "track-subnets", nil, "make new node/s sync with the given subnets")
+	return cmd
+}
+
+// getClusterAvalancheGoVersion returns the AvalancheGo version to install on new nodes of
+// [clusterName]. Unless a version flag is given, it is the version run by the cluster
+func getClusterAvalancheGoVersion(clusterName string) (string, error) {
+	if useLatestAvalanchegoVersion || useAvalanchegoVersionFromSubnet != "" {
+		return getAvalancheGoVersion()
+	}
+	clusterNodes, err := getClusterNodes(clusterName)
+	if err != nil {
+		return "", err
+	}
+	nodeConfig, err := app.LoadClusterNodeConfig(clusterNodes[0])
+	if err != nil {
+		return "", err
+	}
+	ansibleHostID, err := models.HostCloudIDToAnsibleID(getNodeCloudService(nodeConfig), nodeConfig.NodeID)
+	if err != nil {
+		return "", err
+	}
+	vmVersions, err := nodeExecutor.GetVMVersions([]string{ansibleHostID})
+	if version := vmVersions[ansibleHostID][constants.PlatformKeyName]; err == nil && version != "" && version != constants.AvalancheGoVersionUnknown {
+		ux.Logger.PrintToUser("Using AvalancheGo version %s, as run by cluster %s", version, clusterName)
+		return version, nil
+	}
+	ux.Logger.PrintToUser("Could not get the AvalancheGo version run by cluster %s", clusterName)
+	return getAvalancheGoVersion()
+}
+
+// getNodeCloudService returns the cloud service of [nodeConfig], which is not set for
+// nodes created when only AWS was supported
+func getNodeCloudService(nodeConfig models.NodeConfig) string {
+	if nodeConfig.CloudService == "" {
+		return constants.AWSCloudService
+	}
+	return nodeConfig.CloudService
+}
+
+func addNodes(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	if useLatestAvalanchegoVersion && useAvalanchegoVersionFromSubnet != "" {
+		return fmt.Errorf("could not use both latest avalanchego version and avalanchego version based on given subnet")
+	}
+	for _, subnetName := range trackSubnets {
+		if _, err := subnetcmd.ValidateSubnetNameAndGetChains([]string{subnetName}); err != nil {
+			return err
+		}
+	}
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	clusterConfig := clustersConfig.Clusters[clusterName]
+	network := clusterConfig.Network
+	nodeConfig, err := app.LoadClusterNodeConfig(clusterConfig.Nodes[0])
+	if err != nil {
+		return err
+	}
+	cloudService := getNodeCloudService(nodeConfig)
+	if cloudService == constants.ExistingHostsService {
+		if existingHostsPath == "" {
+			return errors.New("--existing-hosts is required to add node/s to a cluster of existing servers")
+		}
+		if numNodes != 0 {
+			return errors.New("number of nodes is given by the existing hosts file")
+		}
+	} else if existingHostsPath != "" {
+		return fmt.Errorf("cluster %s was not created on existing servers", clusterName)
+	}
+	if err := setupNodeExecutor(clusterName); err != nil {
+		return err
+	}
+	avalancheGoVersion, err := getClusterAvalancheGoVersion(clusterName)
+	if err != nil {
+		return err
+	}
+
+	var ansibleHostIDs []string
+	switch cloudService {
+	case constants.LocalMachineService:
+		ansibleHostIDs, err = addLocalNodesToCluster(network, clusterName)
+		if err != nil {
+			return err
+		}
+	case constants.ExistingHostsService:
+		_, ansibleHostIDs, err = addExistingHostsToCluster(network, clusterName)
+		if err != nil {
+			return err
+		}
+	default:
+		// new instances go to the same region or zone of the cluster
+		cmdLineRegion = nodeConfig.Region
+		// new servers are created with the same configuration of the cluster ones
+		serverConfig := getNodeCloudServerConfig(cloudService, nodeConfig)
+		cloudConfig, _, err := createCloudNodes(network, clusterName, cloudService, serverConfig)
+		if err != nil {
+			return err
+		}
+		ansibleHostIDs, err = utils.MapWithError(cloudConfig.InstanceIDs, func(s string) (string, error) { return models.HostCloudIDToAnsibleID(cloudService, s) })
+		if err != nil {
+			return err
+		}
+	}
+
+	ux.Logger.PrintToUser("Installing AvalancheGo and starting bootstrap process on the new node(s) ...")
+	if err := setupNodes(network, avalancheGoVersion, clusterName, ansibleHostIDs); err != nil {
+		return err
+	}
+	if cloudService != constants.LocalMachineService {
+		if err := setupBuildEnv(ansibleHostIDs); err != nil {
+			return err
+		}
+	}
+	if network.Kind == models.Devnet {
+		ux.Logger.PrintToUser("Joining new node(s) to the Devnet ...")
+		if err := joinDevnet(clusterName, ansibleHostIDs); err != nil {
+			return err
+		}
+	}
+	for _, subnetName := range trackSubnets {
+		untrackedNodes, err := trackSubnetOnHosts(ansibleHostIDs, subnetName, network)
+		if err != nil {
+			return err
+		}
+		if len(untrackedNodes) > 0 {
+			return fmt.Errorf("node(s) %s failed to sync with subnet %s", untrackedNodes, subnetName)
+		}
+	}
+
+	ux.Logger.PrintToUser("Node(s) %s added to cluster %s and bootstrapping!", ansibleHostIDs, clusterName)
+	ux.Logger.PrintToUser(fmt.Sprintf("Check node bootstrap status with avalanche node status %s", clusterName))
+	printMonitoringSetupHint(clusterName)
+	return nil
+}
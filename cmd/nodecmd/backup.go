@@ -0,0 +1,471 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	backupManifestFileName = "manifest.json"
+	backupNodesDir         = "nodes"
+	backupSSHKeysDir       = "ssh"
+	backupTerraformCache   = ".terraform"
+)
+
+var backupOutput string
+
+// clusterBackupManifest describes the contents of a cluster backup, as needed to restore it
+type clusterBackupManifest struct {
+	ClusterName   string
+	ClusterConfig models.ClusterConfig
+	Nodes         []string          // cloud IDs of the nodes, and of the monitoring instance if any
+	SSHKeys       map[string]string // maps the original path of each ssh key to its file in the backup
+	KeyPairs      map[string]string // maps the key pair names used by the nodes to their cert path
+}
+
+func newBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup [clusterName]",
+		Short: "(ALPHA Warning) Save the node identities and configs of a cluster into an encrypted file",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node backup command packs everything needed to manage a cluster from
+another machine into a password encrypted file: the staking cert, key and BLS
+signer key of each node, the node and cluster configs, the cluster inventories,
+the terraform state of its cloud resources, and the ssh keys used to access
+its nodes.
+
+Keep the password safe, as the staking keys can't be recovered from the backup
+without it. Use node restore to restore the cluster from the backup.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         backupCluster,
+	}
+	cmd.Flags().StringVarP(&backupOutput, "output", "o", "", "file to write the backup to")
+	return cmd
+}
+
+func newRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [backupFile]",
+		Short: "(ALPHA Warning) Restore a cluster from a backup file",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node restore command restores a cluster saved with node backup, after
+asking for the backup password. The node identities, configs, inventories and
+terraform state are restored into the local Avalanche-CLI dir, and the ssh keys
+into the user .ssh dir. The cluster config and inventories are updated to point
+to the restored ssh keys.
+
+The cluster, and the nodes in it, must not already exist on this machine.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         restoreCluster,
+	}
+	return cmd
+}
+
+// copyDir copies the regular files of [srcDir] into [dstDir], keeping their relative paths.
+// Subdirs are only copied if [recursive], skipping the ones named [skipDirName]
+func copyDir(srcDir string, dstDir string, recursive bool, skipDirName string) error {
+	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != srcDir && (!recursive || info.Name() == skipDirName) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		dstPath := filepath.Join(dstDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(dstPath), constants.DefaultPerms755); err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(dstPath, content, info.Mode().Perm())
+	})
+}
+
+// getBackupRelPath returns the path of [path] relative to the nodes dir, as kept in the backup
+func getBackupRelPath(path string) (string, error) {
+	return filepath.Rel(app.GetNodesDir(), path)
+}
+
+// getClusterSSHKeys returns the ssh keys used to access the nodes of the cluster, as found
+// in the node configs and in [inventoryDirs]
+func getClusterSSHKeys(nodes []string, inventoryDirs []string) ([]string, error) {
+	sshKeys := []string{}
+	addSSHKey := func(sshKey string) {
+		if sshKey != "" && utils.FileExists(sshKey) && !slices.Contains(sshKeys, sshKey) {
+			sshKeys = append(sshKeys, sshKey)
+		}
+	}
+	for _, node := range nodes {
+		nodeConfig, err := app.LoadClusterNodeConfig(node)
+		if err != nil {
+			return nil, err
+		}
+		addSSHKey(nodeConfig.CertPath)
+	}
+	for _, inventoryDir := range inventoryDirs {
+		if !utils.DirectoryExists(inventoryDir) {
+			continue
+		}
+		hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(inventoryDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, host := range hosts {
+			addSSHKey(host.SSHPrivateKeyPath)
+		}
+	}
+	return sshKeys, nil
+}
+
+func captureBackupPassword() (string, error) {
+	password, err := app.Prompt.CapturePassword("Enter a password to encrypt the backup")
+	if err != nil {
+		return "", err
+	}
+	confirmation, err := app.Prompt.CapturePassword("Enter the password again")
+	if err != nil {
+		return "", err
+	}
+	if password != confirmation {
+		return "", errors.New("passwords don't match")
+	}
+	return password, nil
+}
+
+func backupCluster(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if backupOutput == "" {
+		return errors.New("the file to write the backup to must be given with --output")
+	}
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	clusterConfig := clustersConfig.Clusters[clusterName]
+	manifest := clusterBackupManifest{
+		ClusterName:   clusterName,
+		ClusterConfig: clusterConfig,
+		Nodes:         append([]string{}, clusterConfig.Nodes...),
+		SSHKeys:       map[string]string{},
+		KeyPairs:      map[string]string{},
+	}
+	if clusterConfig.MonitoringInstance != "" && !slices.Contains(manifest.Nodes, clusterConfig.MonitoringInstance) {
+		manifest.Nodes = append(manifest.Nodes, clusterConfig.MonitoringInstance)
+	}
+	backupDir, err := os.MkdirTemp("", "avalanche-cli-backup")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(backupDir)
+
+	ux.Logger.PrintToUser("Collecting the files of cluster %s ...", clusterName)
+	for _, node := range manifest.Nodes {
+		// only the identity and config files are kept, not the data of local machine nodes
+		if err := copyDir(app.GetNodeInstanceDirPath(node), filepath.Join(backupDir, backupNodesDir, node), false, ""); err != nil {
+			return fmt.Errorf("failed to back up files of node %s: %w", node, err)
+		}
+		nodeConfig, err := app.LoadClusterNodeConfig(node)
+		if err != nil {
+			return err
+		}
+		if certPath, ok := clustersConfig.KeyPair[nodeConfig.KeyPair]; ok {
+			manifest.KeyPairs[nodeConfig.KeyPair] = certPath
+		}
+	}
+	inventoryDirs := []string{app.GetAnsibleInventoryDirPath(clusterName), app.GetMonitoringInventoryDirPath(clusterName)}
+	for _, dir := range append(inventoryDirs, app.GetClusterDirPath(clusterName), app.GetClusterTerraformDir(clusterName)) {
+		if !utils.DirectoryExists(dir) {
+			continue
+		}
+		relPath, err := getBackupRelPath(dir)
+		if err != nil {
+			return err
+		}
+		// terraform providers are downloaded again when needed
+		if err := copyDir(dir, filepath.Join(backupDir, relPath), true, backupTerraformCache); err != nil {
+			return err
+		}
+	}
+	sshKeys, err := getClusterSSHKeys(manifest.Nodes, inventoryDirs)
+	if err != nil {
+		return err
+	}
+	for i, sshKey := range sshKeys {
+		backupFileName := fmt.Sprintf("%d_%s", i, filepath.Base(sshKey))
+		content, err := os.ReadFile(sshKey)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Join(backupDir, backupSSHKeysDir), constants.DefaultPerms755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(backupDir, backupSSHKeysDir, backupFileName), content, constants.WriteReadUserOnlyPerms); err != nil {
+			return err
+		}
+		manifest.SSHKeys[sshKey] = backupFileName
+	}
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(backupDir, backupManifestFileName), manifestBytes, constants.WriteReadUserOnlyPerms); err != nil {
+		return err
+	}
+
+	archiveDir, err := os.MkdirTemp("", "avalanche-cli-backup-archive")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(archiveDir)
+	archivePath := filepath.Join(archiveDir, "backup.tar.gz")
+	if err := utils.CreateTarGz(backupDir, archivePath); err != nil {
+		return err
+	}
+	archive, err := os.ReadFile(archivePath)
+	if err != nil {
+		return err
+	}
+	password, err := captureBackupPassword()
+	if err != nil {
+		return err
+	}
+	encryptedArchive, err := utils.EncryptWithPassword(archive, password)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(backupOutput, encryptedArchive, constants.WriteReadUserOnlyPerms); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Backup of cluster %s, with %d node(s) and %d ssh key(s), saved at %s", clusterName, len(manifest.Nodes), len(manifest.SSHKeys), backupOutput)
+	return nil
+}
+
+// restoreSSHKeys restores the ssh keys of the backup into the user .ssh dir, and returns the
+// path each original path is restored to. Keys already found there with the same content are
+// reused. The paths of the keys written are also returned, even on failure, so that they can
+// be removed
+func restoreSSHKeys(backupDir string, manifest clusterBackupManifest) (map[string]string, []string, error) {
+	restoredPaths := map[string]string{}
+	writtenPaths := []string{}
+	for originalPath, backupFileName := range manifest.SSHKeys {
+		content, err := os.ReadFile(filepath.Join(backupDir, backupSSHKeysDir, backupFileName))
+		if err != nil {
+			return nil, writtenPaths, err
+		}
+		sshKeyPath, err := app.GetSSHCertFilePath(filepath.Base(originalPath))
+		if err != nil {
+			return nil, writtenPaths, err
+		}
+		if utils.FileExists(sshKeyPath) {
+			existingContent, err := os.ReadFile(sshKeyPath)
+			if err != nil {
+				return nil, writtenPaths, err
+			}
+			if !bytes.Equal(content, existingContent) {
+				return nil, writtenPaths, fmt.Errorf("a different ssh key already exists at %s", sshKeyPath)
+			}
+		} else {
+			if err := os.MkdirAll(filepath.Dir(sshKeyPath), constants.UserOnlyWriteReadExecPerms); err != nil {
+				return nil, writtenPaths, err
+			}
+			writtenPaths = append(writtenPaths, sshKeyPath)
+			if err := os.WriteFile(sshKeyPath, content, constants.WriteReadUserOnlyPerms); err != nil {
+				return nil, writtenPaths, err
+			}
+		}
+		restoredPaths[originalPath] = sshKeyPath
+	}
+	return restoredPaths, writtenPaths, nil
+}
+
+// restoreInventory writes the inventory found in [backupInventoryDir] into [inventoryDir],
+// pointing the hosts to their restored ssh keys
+func restoreInventory(backupInventoryDir string, inventoryDir string, sshKeyPaths map[string]string) error {
+	if !utils.DirectoryExists(backupInventoryDir) {
+		return nil
+	}
+	hosts, err := ansible.GetInventoryFromAnsibleInventoryFile(backupInventoryDir)
+	if err != nil {
+		return err
+	}
+	for i := range hosts {
+		if sshKeyPath, ok := sshKeyPaths[hosts[i].SSHPrivateKeyPath]; ok {
+			hosts[i].SSHPrivateKeyPath = sshKeyPath
+		}
+	}
+	return ansible.AddHostsToAnsibleInventory(inventoryDir, hosts)
+}
+
+func restoreCluster(_ *cobra.Command, args []string) error {
+	encryptedArchive, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	password, err := app.Prompt.CapturePassword("Enter the backup password")
+	if err != nil {
+		return err
+	}
+	archive, err := utils.DecryptWithPassword(encryptedArchive, password)
+	if err != nil {
+		return err
+	}
+	backupDir, err := os.MkdirTemp("", "avalanche-cli-restore")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(backupDir)
+	if err := binutils.InstallArchive("tar.gz", archive, backupDir); err != nil {
+		return fmt.Errorf("failed to extract backup: %w", err)
+	}
+	manifestBytes, err := os.ReadFile(filepath.Join(backupDir, backupManifestFileName))
+	if err != nil {
+		return err
+	}
+	manifest := clusterBackupManifest{}
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return fmt.Errorf("invalid backup manifest: %w", err)
+	}
+	clusterName := manifest.ClusterName
+
+	clustersConfig := models.ClustersConfig{}
+	if app.ClustersConfigExists() {
+		clustersConfig, err = app.LoadClustersConfig()
+		if err != nil {
+			return err
+		}
+	}
+	if _, ok := clustersConfig.Clusters[clusterName]; ok {
+		return fmt.Errorf("cluster %s already exists", clusterName)
+	}
+	for _, node := range manifest.Nodes {
+		if utils.DirectoryExists(app.GetNodeInstanceDirPath(node)) {
+			return fmt.Errorf("files of node %s already exist at %s", node, app.GetNodeInstanceDirPath(node))
+		}
+	}
+
+	ux.Logger.PrintToUser("Restoring cluster %s ...", clusterName)
+	// everything written is removed if the restore fails, so that it can be retried
+	restored := false
+	restoredPaths := []string{}
+	defer func() {
+		if restored {
+			return
+		}
+		for _, path := range restoredPaths {
+			if err := os.RemoveAll(path); err != nil {
+				ux.Logger.PrintToUser("Failed to remove %s: %s", path, err)
+			}
+		}
+	}()
+	sshKeyPaths, writtenSSHKeyPaths, err := restoreSSHKeys(backupDir, manifest)
+	restoredPaths = append(restoredPaths, writtenSSHKeyPaths...)
+	if err != nil {
+		return err
+	}
+	hasGCPNodes := false
+	for _, node := range manifest.Nodes {
+		restoredPaths = append(restoredPaths, app.GetNodeInstanceDirPath(node))
+		if err := copyDir(filepath.Join(backupDir, backupNodesDir, node), app.GetNodeInstanceDirPath(node), false, ""); err != nil {
+			return fmt.Errorf("failed to restore files of node %s: %w", node, err)
+		}
+		nodeConfig, err := app.LoadClusterNodeConfig(node)
+		if err != nil {
+			return err
+		}
+		if sshKeyPath, ok := sshKeyPaths[nodeConfig.CertPath]; ok {
+			nodeConfig.CertPath = sshKeyPath
+			if err := app.CreateNodeCloudConfigFile(node, &nodeConfig); err != nil {
+				return err
+			}
+		}
+		if nodeConfig.CloudService == constants.GCPCloudService {
+			hasGCPNodes = true
+		}
+	}
+	for _, inventoryDir := range []string{app.GetAnsibleInventoryDirPath(clusterName), app.GetMonitoringInventoryDirPath(clusterName)} {
+		relPath, err := getBackupRelPath(inventoryDir)
+		if err != nil {
+			return err
+		}
+		if !utils.DirectoryExists(inventoryDir) {
+			restoredPaths = append(restoredPaths, inventoryDir)
+		}
+		if err := restoreInventory(filepath.Join(backupDir, relPath), inventoryDir, sshKeyPaths); err != nil {
+			return err
+		}
+	}
+	for _, dir := range []string{app.GetClusterDirPath(clusterName), app.GetClusterTerraformDir(clusterName)} {
+		relPath, err := getBackupRelPath(dir)
+		if err != nil {
+			return err
+		}
+		if utils.DirectoryExists(filepath.Join(backupDir, relPath)) {
+			if !utils.DirectoryExists(dir) {
+				restoredPaths = append(restoredPaths, dir)
+			}
+			if err := copyDir(filepath.Join(backupDir, relPath), dir, true, ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	if clustersConfig.Clusters == nil {
+		clustersConfig.Clusters = map[string]models.ClusterConfig{}
+	}
+	clustersConfig.Clusters[clusterName] = manifest.ClusterConfig
+	if clustersConfig.KeyPair == nil {
+		clustersConfig.KeyPair = map[string]string{}
+	}
+	for keyPairName, certPath := range manifest.KeyPairs {
+		if _, ok := clustersConfig.KeyPair[keyPairName]; ok {
+			continue
+		}
+		if sshKeyPath, ok := sshKeyPaths[certPath]; ok {
+			certPath = sshKeyPath
+		}
+		clustersConfig.KeyPair[keyPairName] = certPath
+	}
+	if err := app.WriteClustersConfigFile(&clustersConfig); err != nil {
+		return err
+	}
+	restored = true
+	ux.Logger.PrintToUser("Cluster %s restored, with %d node(s) and %d ssh key(s)", clusterName, len(manifest.Nodes), len(sshKeyPaths))
+	if hasGCPNodes {
+		ux.Logger.PrintToUser("Cluster %s has GCP nodes, GCP credentials will be asked for on the next cloud operation", clusterName)
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configNode          string
+	configCurrent       bool
+	configUnset         bool
+	configDryRun        bool
+	configForce         bool
+	configHealthTimeout time.Duration
+)
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "(ALPHA Warning) Manage the avalanchego config of the cluster nodes",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node config command suite manages the avalanchego config (node.json) of the
+nodes of a cluster. The desired config changes are kept locally for each
+cluster, for all its nodes or overridden for a given node, and are pushed to the
+nodes with node config apply.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+	// node config get
+	cmd.AddCommand(newConfigGetCmd())
+	// node config set
+	cmd.AddCommand(newConfigSetCmd())
+	// node config apply
+	cmd.AddCommand(newConfigApplyCmd())
+	return cmd
+}
+
+func newConfigGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get [clusterName] [key]",
+		Short: "(ALPHA Warning) Show the avalanchego config of the cluster nodes",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node config get command shows the avalanchego config changes set for a
+cluster, or for a node of it with --node. Use --current to show the node.json
+currently found on the nodes instead. If a key is given, only its value is
+shown.`,
+		SilenceUsage: true,
+		Args:         cobra.RangeArgs(1, 2),
+		RunE:         getConfig,
+	}
+	cmd.Flags().StringVar(&configNode, "node", "", "show the config of the given node, by cloud instance ID or Avalanche NodeID")
+	cmd.Flags().BoolVar(&configCurrent, "current", false, "show the node.json currently found on the nodes")
+	return cmd
+}
+
+func newConfigSetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set [clusterName] [key] [value]",
+		Short: "(ALPHA Warning) Set an avalanchego config value for the cluster nodes",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node config set command sets an avalanchego config value for all nodes of
+a cluster, or only for a node of it with --node. The value is parsed as JSON,
+and taken as a string if it is not valid JSON. Use --unset to remove the key
+from the node.json of the nodes instead.
+
+The change is only saved locally; use node config apply to push it to the
+nodes.`,
+		SilenceUsage: true,
+		Args:         cobra.RangeArgs(2, 3),
+		RunE:         setConfig,
+	}
+	cmd.Flags().StringVar(&configNode, "node", "", "only set the value for the given node, by cloud instance ID or Avalanche NodeID")
+	cmd.Flags().BoolVar(&configUnset, "unset", false, "remove the key from the node.json of the nodes")
+	return cmd
+}
+
+func newConfigApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply [clusterName]",
+		Short: "(ALPHA Warning) Push the avalanchego config changes to the cluster nodes",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node config apply command shows the difference between the node.json of
+each node of a cluster and its desired config, and pushes the changes to the
+nodes. Nodes are updated and restarted one at a time, waiting up to
+--health-timeout for each one to be healthy before moving on to the next one.
+If a node doesn't come back in time, its previous node.json is restored and the
+rollout is aborted.
+
+Use --dry-run to only show the changes, and --node to only update one node.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         applyConfig,
+	}
+	cmd.Flags().StringVar(&configNode, "node", "", "only update the given node, by cloud instance ID or Avalanche NodeID")
+	cmd.Flags().BoolVar(&configDryRun, "dry-run", false, "only show the changes to apply")
+	cmd.Flags().BoolVar(&configForce, "force", false, "don't ask for confirmation before updating the nodes")
+	cmd.Flags().DurationVar(&configHealthTimeout, "health-timeout", 10*time.Minute, "time to wait for an updated node to become healthy")
+	return cmd
+}
+
+// getClusterHostIDs returns the ansible host IDs of the nodes of [clusterName], or only the one
+// of [node] if given
+func getClusterHostIDs(clusterName string, node string) ([]string, error) {
+	if node == "" {
+		return ansible.GetAnsibleHostsFromInventory(app.GetAnsibleInventoryDirPath(clusterName))
+	}
+	cloudID, err := getClusterNode(clusterName, node)
+	if err != nil {
+		return nil, err
+	}
+	nodeConfig, err := app.LoadClusterNodeConfig(cloudID)
+	if err != nil {
+		return nil, err
+	}
+	hostID, err := models.HostCloudIDToAnsibleID(getNodeCloudService(nodeConfig), cloudID)
+	if err != nil {
+		return nil, err
+	}
+	return []string{hostID}, nil
+}
+
+// getCurrentNodeConfigs returns the node.json found on [hostIDs], by host ID
+func getCurrentNodeConfigs(hostIDs []string) (map[string]map[string]interface{}, error) {
+	nodeConfigsBytes, err := nodeExecutor.GetNodeConfig(hostIDs)
+	if err != nil {
+		return nil, err
+	}
+	nodeConfigs := map[string]map[string]interface{}{}
+	for _, hostID := range hostIDs {
+		nodeConfig := map[string]interface{}{}
+		if err := json.Unmarshal(nodeConfigsBytes[hostID], &nodeConfig); err != nil {
+			return nil, fmt.Errorf("invalid node.json on host %s: %w", hostID, err)
+		}
+		nodeConfigs[hostID] = nodeConfig
+	}
+	return nodeConfigs, nil
+}
+
+func printConfigValues(values map[string]interface{}, key string) error {
+	if key != "" {
+		value, ok := values[key]
+		if !ok {
+			return fmt.Errorf("key %s is not set", key)
+		}
+		valueBytes, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser(string(valueBytes))
+		return nil
+	}
+	valuesBytes, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser(string(valuesBytes))
+	return nil
+}
+
+func getConfig(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	key := ""
+	if len(args) > 1 {
+		key = args[1]
+	}
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	if !configCurrent {
+		clusterConfig, err := app.LoadClusterAvalancheGoConfig(clusterName)
+		if err != nil {
+			return err
+		}
+		if configNode == "" {
+			return printConfigValues(clusterConfig.Cluster, key)
+		}
+		cloudID, err := getClusterNode(clusterName, configNode)
+		if err != nil {
+			return err
+		}
+		return printConfigValues(clusterConfig.GetNodeChanges(cloudID), key)
+	}
+	if err := setupNodeExecutor(clusterName); err != nil {
+		return err
+	}
+	hostIDs, err := getClusterHostIDs(clusterName, configNode)
+	if err != nil {
+		return err
+	}
+	nodeConfigs, err := getCurrentNodeConfigs(hostIDs)
+	if err != nil {
+		return err
+	}
+	sort.Strings(hostIDs)
+	for _, hostID := range hostIDs {
+		ux.Logger.PrintToUser("Node %s:", hostID)
+		if err := printConfigValues(nodeConfigs[hostID], key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setConfig(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	key := args[1]
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	var value interface{}
+	switch {
+	case configUnset && len(args) > 2:
+		return errors.New("a value can't be given together with --unset")
+	case !configUnset && len(args) < 3:
+		return errors.New("a value must be given, or --unset to remove the key")
+	case !configUnset:
+		if err := json.Unmarshal([]byte(args[2]), &value); err != nil {
+			value = args[2]
+		}
+	}
+	node := ""
+	if configNode != "" {
+		var err error
+		node, err = getClusterNode(clusterName, configNode)
+		if err != nil {
+			return err
+		}
+	}
+	clusterConfig, err := app.LoadClusterAvalancheGoConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	clusterConfig.Set(node, key, value)
+	if err := app.WriteClusterAvalancheGoConfig(clusterName, clusterConfig); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Config of cluster %s updated. Run avalanche node config apply %s to push it to the nodes", clusterName, clusterName)
+	return nil
+}
+
+func printConfigChanges(hostID string, changes []models.AvalancheGoConfigChange) {
+	ux.Logger.PrintToUser("Node %s:", hostID)
+	if len(changes) == 0 {
+		ux.Logger.PrintToUser("  no changes")
+		return
+	}
+	for _, change := range changes {
+		currentBytes, _ := json.Marshal(change.Current)
+		desiredBytes, _ := json.Marshal(change.Desired)
+		switch {
+		case change.IsNew:
+			ux.Logger.PrintToUser("  + %s: %s", change.Key, desiredBytes)
+		case change.IsRemove:
+			ux.Logger.PrintToUser("  - %s: %s", change.Key, currentBytes)
+		default:
+			ux.Logger.PrintToUser("  ~ %s: %s -> %s", change.Key, currentBytes, desiredBytes)
+		}
+	}
+}
+
+// pushNodeConfig writes [nodeConfig] as the node.json of [hostID] and restarts the node
+func pushNodeConfig(hostID string, nodeConfig map[string]interface{}) error {
+	nodeConfigBytes, err := json.MarshalIndent(nodeConfig, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp("", "avalanchego-config")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	nodeConfigPath := filepath.Join(tmpDir, constants.NodeFileName)
+	if err := os.WriteFile(nodeConfigPath, nodeConfigBytes, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	if err := nodeExecutor.SetNodeConfig(hostID, nodeConfigPath); err != nil {
+		return err
+	}
+	if err := nodeExecutor.StopNode(hostID); err != nil {
+		return err
+	}
+	return nodeExecutor.StartNode(hostID)
+}
+
+func applyConfig(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	if err := setupNodeExecutor(clusterName); err != nil {
+		return err
+	}
+	clusterConfig, err := app.LoadClusterAvalancheGoConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	hostIDs, err := getClusterHostIDs(clusterName, configNode)
+	if err != nil {
+		return err
+	}
+	sort.Strings(hostIDs)
+	currentConfigs, err := getCurrentNodeConfigs(hostIDs)
+	if err != nil {
+		return err
+	}
+	desiredConfigs := map[string]map[string]interface{}{}
+	hostIDsToUpdate := []string{}
+	for _, hostID := range hostIDs {
+		_, cloudID, err := models.HostAnsibleIDToCloudID(hostID)
+		if err != nil {
+			return err
+		}
+		desiredConfigs[hostID] = clusterConfig.GetDesiredNodeConfig(cloudID, currentConfigs[hostID])
+		changes := models.DiffAvalancheGoConfig(currentConfigs[hostID], desiredConfigs[hostID])
+		printConfigChanges(hostID, changes)
+		if len(changes) > 0 {
+			hostIDsToUpdate = append(hostIDsToUpdate, hostID)
+		}
+	}
+	if len(hostIDsToUpdate) == 0 {
+		ux.Logger.PrintToUser("All nodes in cluster %s are up to date", clusterName)
+		return nil
+	}
+	if configDryRun {
+		return nil
+	}
+	if !configForce {
+		yes, err := app.Prompt.CaptureYesNo(fmt.Sprintf("%d node(s) will be updated and restarted. Do you want to proceed?", len(hostIDsToUpdate)))
+		if err != nil {
+			return err
+		}
+		if !yes {
+			ux.Logger.PrintToUser("Aborted")
+			return nil
+		}
+	}
+	for i, hostID := range hostIDsToUpdate {
+		ux.Logger.PrintToUser("Updating node %s (%d/%d) ...", hostID, i+1, len(hostIDsToUpdate))
+		updateErr := pushNodeConfig(hostID, desiredConfigs[hostID])
+		if updateErr == nil {
+			if unhealthyNodes := waitForNodesHealthy([]string{hostID}, nil, configHealthTimeout); len(unhealthyNodes) > 0 {
+				updateErr = fmt.Errorf("node not healthy after %s", configHealthTimeout)
+			}
+		}
+		if updateErr == nil {
+			continue
+		}
+		ux.Logger.PrintToUser("Failed to update node %s: %s. Restoring its previous config ...", hostID, updateErr)
+		if err := pushNodeConfig(hostID, currentConfigs[hostID]); err != nil {
+			return fmt.Errorf("failed to restore config of node %s after update failure %s: %w", hostID, updateErr, err)
+		}
+		return fmt.Errorf("aborted config update of cluster %s on node %s: %w", clusterName, hostID, updateErr)
+	}
+	ux.Logger.PrintToUser("Config of cluster %s applied to %d node(s)", clusterName, len(hostIDsToUpdate))
+	return nil
+}
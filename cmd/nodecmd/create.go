@@ -11,7 +11,6 @@ import (
 	"os/exec"
 	"os/user"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/ava-labs/avalanche-cli/cmd/subnetcmd"
@@ -26,6 +25,7 @@ import (
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/primarygenesis"
 
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/spf13/cobra"
@@ -53,6 +53,9 @@ var (
 	cmdLineGCPCredentialsPath       string
 	cmdLineGCPProjectName           string
 	cmdLineAlternativeKeyPairName   string
+	genesisSpecPath                 string
+	// devnetGenesisSpec customizes the genesis of the Devnet created by node create
+	devnetGenesisSpec = &primarygenesis.Spec{}
 )
 
 type CloudConfig struct {
@@ -63,6 +66,11 @@ type CloudConfig struct {
 	SecurityGroup string
 	CertFilePath  string
 	ImageID       string
+	Arch          string
+	InstanceType  string
+	DiskSize      int
+	DiskType      string
+	DiskIOPS      int
 }
 
 func newCreateCmd() *cobra.Command {
@@ -82,7 +90,38 @@ status by running avalanche node status
 
 The created node will be part of group of validators called <clusterName> 
 and users can call node commands with <clusterName> so that the command
-will apply to all nodes in the cluster`,
+will apply to all nodes in the cluster
+
+Cloud servers are created by default with Ubuntu 20.04 on amd64, on a
+c5.2xlarge (AWS) or e2-standard-8 (GCP) instance with a 1000 GB disk. Use
+--arm64, --node-type, --disk-size, --disk-type, --disk-iops and --os-image to
+change it, e.g. small arm64 servers for a cheap Devnet.
+
+To set up validators on your own servers instead of creating cloud servers, use
+--existing-hosts with a JSON file listing them, e.g.
+[{"ip": "1.2.3.4", "sshUser": "ubuntu", "sshKeyPath": "~/.ssh/id_rsa"}]
+Servers must run Ubuntu, and the ssh user needs passwordless sudo.
+
+To customize the genesis of a Devnet, use --genesis-spec with a JSON file
+setting any of: the network ID (networkID), the funded address or stored key
+(fundedAddr, fundedKey) used instead of ewoq, the reward address and delegation
+fee of the initial validators (rewardAddr, delegationFee), additional X-Chain
+and P-Chain allocations with their unlock schedule (allocations), C-Chain
+allocations or a full C-Chain genesis (cChainAllocations, cChainGenesis), the
+staking parameters (initialStakeDuration, initialStakeDurationOffset,
+minValidatorStake, minDelegatorStake, minStakeDuration, maxStakeDuration) and
+the genesis message (message). The genesis is validated before being pushed to
+the nodes.
+
+To keep the NodeIDs of existing validators, e.g. when migrating them to the
+cloud, use --staking-dir or --staking-files to give the nodes their existing
+staker.crt, staker.key and signer.key instead of generating new ones.
+--staking-dir can hold the files of one node, or a subdir with them for each
+node to create.
+
+To try out node commands without a cloud account, use --local-machine to run
+the node/s as avalanchego processes on this machine, each one with its own
+data dir and ports.`,
 		SilenceUsage: true,
 		Args:         cobra.ExactArgs(1),
 		RunE:         createNodes,
@@ -101,19 +140,65 @@ will apply to all nodes in the cluster`,
 	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
 	cmd.Flags().BoolVar(&createOnFuji, "fuji", false, "create node/s in Fuji Network")
 	cmd.Flags().BoolVar(&createDevnet, "devnet", false, "create node/s into a new Devnet")
+	cmd.Flags().StringVar(&existingHostsPath, "existing-hosts", "", "set up node/s on the existing servers listed in given JSON file, instead of creating cloud servers")
+	cmd.Flags().BoolVar(&useLocalMachine, "local-machine", false, "run node/s as avalanchego processes on the local machine, instead of creating cloud servers")
+	cmd.Flags().BoolVar(&useARM64, "arm64", false, "create arm64 cloud servers (AWS Graviton / GCP Tau T2A)")
+	cmd.Flags().StringVar(&cmdLineNodeType, "node-type", "", "cloud instance type to use (defaults to c5.2xlarge on AWS, e2-standard-8 on GCP)")
+	cmd.Flags().IntVar(&cmdLineDiskSize, "disk-size", 0, fmt.Sprintf("disk size in GB of the cloud servers (defaults to %d)", constants.CloudServerStorageSize))
+	cmd.Flags().StringVar(&cmdLineDiskType, "disk-type", "", "disk type of the cloud servers (gp3, gp2, io1, io2 on AWS, pd-standard, pd-balanced, pd-ssd on GCP)")
+	cmd.Flags().IntVar(&cmdLineDiskIOPS, "disk-iops", 0, "provisioned IOPS of the disk, for AWS gp3, io1 and io2 disk types")
+	cmd.Flags().StringVar(&cmdLineOSImage, "os-image", "", "AMI ID on AWS, or image name on GCP, to use instead of the latest Ubuntu 20.04")
+	cmd.Flags().StringVar(&stakingDir, "staking-dir", "", "import the staking files found in given dir, with a subdir for each node, instead of generating new ones")
+	cmd.Flags().StringVar(&genesisSpecPath, "genesis-spec", "", "customize the Devnet genesis with the given genesis spec file")
+	cmd.Flags().StringSliceVar(&stakingFiles, "staking-files", nil, "import the given staker.crt, staker.key and signer.key files, in this order, for a single node")
 	return cmd
 }
 
-func createNodes(_ *cobra.Command, args []string) error {
+func createNodes(cmd *cobra.Command, args []string) error {
 	if useLatestAvalanchegoVersion && useAvalanchegoVersionFromSubnet != "" {
 		return fmt.Errorf("could not use both latest avalanchego version and avalanchego version based on given subnet")
 	}
 	if useAWS && useGCP {
 		return fmt.Errorf("could not use both AWS and GCP cloud options")
 	}
+	if existingHostsPath != "" {
+		if err := checkExistingHostsFlags(); err != nil {
+			return err
+		}
+	}
+	if useLocalMachine {
+		if err := checkLocalMachineFlags(); err != nil {
+			return err
+		}
+	}
 	if !useAWS && awsProfile != constants.AWSDefaultCredential {
 		return fmt.Errorf("could not use AWS profile for non AWS cloud option")
 	}
+	var err error
+	if genesisSpecPath != "" {
+		if !createDevnet {
+			return errors.New("a genesis spec can only be given when creating a Devnet")
+		}
+		devnetGenesisSpec, err = primarygenesis.LoadSpec(genesisSpecPath)
+		if err != nil {
+			return err
+		}
+		if devnetGenesisSpec.FundedKey != "" && !app.KeyExists(devnetGenesisSpec.FundedKey) {
+			return fmt.Errorf("funded key %s of the genesis spec not found", devnetGenesisSpec.FundedKey)
+		}
+	}
+	importedStakingIdentities, err = loadStakingIdentities(stakingDir, stakingFiles)
+	if err != nil {
+		return err
+	}
+	if importedStakingIdentities != nil && existingHostsPath == "" {
+		switch {
+		case numNodes == 0:
+			numNodes = len(importedStakingIdentities)
+		case numNodes != len(importedStakingIdentities):
+			return fmt.Errorf("%d staking identities given for %d nodes", len(importedStakingIdentities), numNodes)
+		}
+	}
 	clusterName := args[0]
 
 	endpoint := ""
@@ -132,6 +217,12 @@ func createNodes(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if existingHostsPath != "" {
+		return createNodesOnExistingHosts(network, clusterName)
+	}
+	if useLocalMachine {
+		return createLocalNodes(network, clusterName)
+	}
 
 	cloudService, err := setCloudService()
 	if err != nil {
@@ -143,38 +234,80 @@ func createNodes(_ *cobra.Command, args []string) error {
 	if cloudService != constants.GCPCloudService && cmdLineGCPProjectName != "" {
 		return fmt.Errorf("set to use GCP project but cloud option is not GCP")
 	}
-	if err := terraform.CheckIsInstalled(); err != nil {
+	// the wizard is offered along with the cloud region one
+	serverConfig, err := getCloudServerConfig(cloudService, cmdLineRegion == "" && !serverConfigFlagsChanged(cmd))
+	if err != nil {
 		return err
 	}
-	if err := ansible.CheckIsInstalled(); err != nil {
+	cloudConfig, publicIPMap, err := createCloudNodes(network, clusterName, cloudService, serverConfig)
+	if err != nil {
 		return err
 	}
-	err = terraform.RemoveDirectory(app.GetTerraformDir())
+	avalancheGoVersion, err := getAvalancheGoVersion()
 	if err != nil {
 		return err
 	}
-	usr, err := user.Current()
+
+	ux.Logger.PrintToUser("Installing AvalancheGo and Avalanche-CLI and starting bootstrap process on the newly created Avalanche node(s) ...")
+	ansibleHostIDs, err := utils.MapWithError(cloudConfig.InstanceIDs, func(s string) (string, error) { return models.HostCloudIDToAnsibleID(cloudService, s) })
 	if err != nil {
 		return err
 	}
+	if err = setupNodes(network, avalancheGoVersion, clusterName, ansibleHostIDs); err != nil {
+		return err
+	}
+	if err = setupBuildEnv(ansibleHostIDs); err != nil {
+		return err
+	}
+
+	if network.Kind == models.Devnet {
+		if err := setupCreatedDevnet(clusterName, ansibleHostIDs); err != nil {
+			return err
+		}
+	}
+
+	printResults(cloudConfig, publicIPMap, ansibleHostIDs)
+	ux.Logger.PrintToUser("AvalancheGo and Avalanche-CLI installed and node(s) are bootstrapping!")
+	return nil
+}
+
+// createCloudNodes creates [numNodes] instances of [cloudService] for [clusterName], and registers
+// them in the cluster config and inventory. Returns the cloud config of the created instances
+// and their public IPs
+func createCloudNodes(network models.Network, clusterName, cloudService string, serverConfig cloudServerConfig) (CloudConfig, map[string]string, error) {
+	if err := terraform.CheckIsInstalled(); err != nil {
+		return CloudConfig{}, nil, err
+	}
+	if useAnsible {
+		if err := ansible.CheckIsInstalled(); err != nil {
+			return CloudConfig{}, nil, err
+		}
+	}
+	// each set of created nodes keeps its own terraform state, so node destroy can later
+	// delete exactly the cloud resources created for the cluster
+	terraformDir := filepath.Join(app.GetClusterTerraformDir(clusterName), time.Now().UTC().Format("20060102150405"))
+	usr, err := user.Current()
+	if err != nil {
+		return CloudConfig{}, nil, err
+	}
 	cloudConfig := CloudConfig{}
 	publicIPMap := map[string]string{}
 	gcpProjectName := ""
 	gcpCredentialFilepath := ""
 	if cloudService == constants.AWSCloudService {
 		// Get AWS Credential, region and AMI
-		ec2Svc, region, ami, err := getAWSCloudConfig(awsProfile, cmdLineRegion, authorizeAccess)
+		ec2Svc, region, ami, err := getAWSCloudConfig(awsProfile, cmdLineRegion, authorizeAccess, serverConfig)
 		if err != nil {
-			return err
+			return CloudConfig{}, nil, err
 		}
-		cloudConfig, err = createAWSInstances(ec2Svc, numNodes, awsProfile, region, ami, usr)
+		cloudConfig, err = createAWSInstances(ec2Svc, numNodes, awsProfile, region, ami, usr, terraformDir, serverConfig)
 		if err != nil {
-			return err
+			return CloudConfig{}, nil, err
 		}
 		if !useStaticIP {
 			publicIPMap, err = awsAPI.GetInstancePublicIPs(ec2Svc, cloudConfig.InstanceIDs)
 			if err != nil {
-				return err
+				return CloudConfig{}, nil, err
 			}
 		} else {
 			for i, node := range cloudConfig.InstanceIDs {
@@ -183,18 +316,18 @@ func createNodes(_ *cobra.Command, args []string) error {
 		}
 	} else {
 		// Get GCP Credential, zone, Image ID, service account key file path, and GCP project name
-		gcpClient, zone, imageID, credentialFilepath, projectName, err := getGCPConfig(cmdLineRegion)
+		gcpClient, zone, imageID, credentialFilepath, projectName, err := getGCPConfig(cmdLineRegion, serverConfig)
 		if err != nil {
-			return err
+			return CloudConfig{}, nil, err
 		}
-		cloudConfig, err = createGCPInstance(usr, gcpClient, numNodes, zone, imageID, credentialFilepath, projectName, clusterName)
+		cloudConfig, err = createGCPInstance(usr, gcpClient, numNodes, zone, imageID, credentialFilepath, projectName, clusterName, terraformDir, serverConfig)
 		if err != nil {
-			return err
+			return CloudConfig{}, nil, err
 		}
 		if !useStaticIP {
 			publicIPMap, err = gcpAPI.GetInstancePublicIPs(gcpClient, projectName, zone, cloudConfig.InstanceIDs)
 			if err != nil {
-				return err
+				return CloudConfig{}, nil, err
 			}
 		} else {
 			for i, node := range cloudConfig.InstanceIDs {
@@ -206,52 +339,20 @@ func createNodes(_ *cobra.Command, args []string) error {
 	}
 
 	if err = createClusterNodeConfig(network, cloudConfig, clusterName, cloudService); err != nil {
-		return err
+		return CloudConfig{}, nil, err
 	}
 	if cloudService == constants.GCPCloudService {
 		if err = updateClustersConfigGCPKeyFilepath(gcpProjectName, gcpCredentialFilepath); err != nil {
-			return err
+			return CloudConfig{}, nil, err
 		}
 	}
-	err = terraform.RemoveDirectory(app.GetTerraformDir())
-	if err != nil {
-		return err
-	}
-
 	time.Sleep(30 * time.Second)
 
-	avalancheGoVersion, err := getAvalancheGoVersion()
-	if err != nil {
-		return err
-	}
 	inventoryPath := app.GetAnsibleInventoryDirPath(clusterName)
 	if err = ansible.CreateAnsibleHostInventory(inventoryPath, cloudConfig.CertFilePath, cloudService, publicIPMap); err != nil {
-		return err
-	}
-
-	ux.Logger.PrintToUser("Installing AvalancheGo and Avalanche-CLI and starting bootstrap process on the newly created Avalanche node(s) ...")
-	ansibleHostIDs, err := utils.MapWithError(cloudConfig.InstanceIDs, func(s string) (string, error) { return models.HostCloudIDToAnsibleID(cloudService, s) })
-	if err != nil {
-		return err
-	}
-	createdAnsibleHostIDs := strings.Join(ansibleHostIDs, ",")
-	if err = runAnsible(inventoryPath, network, avalancheGoVersion, clusterName, createdAnsibleHostIDs); err != nil {
-		return err
-	}
-	if err = setupBuildEnv(inventoryPath, createdAnsibleHostIDs); err != nil {
-		return err
-	}
-
-	if network.Kind == models.Devnet {
-		ux.Logger.PrintToUser("Setting up Devnet ...")
-		if err := setupDevnet(clusterName); err != nil {
-			return err
-		}
+		return CloudConfig{}, nil, err
 	}
-
-	printResults(cloudConfig, publicIPMap, ansibleHostIDs)
-	ux.Logger.PrintToUser("AvalancheGo and Avalanche-CLI installed and node(s) are bootstrapping!")
-	return nil
+	return cloudConfig, publicIPMap, nil
 }
 
 // createClusterNodeConfig creates node config and save it in .avalanche-cli/nodes/{instanceID}
@@ -272,6 +373,11 @@ func createClusterNodeConfig(network models.Network, cloudConfig CloudConfig, cl
 			SecurityGroup: cloudConfig.SecurityGroup,
 			ElasticIP:     publicIP,
 			CloudService:  cloudService,
+			Arch:          cloudConfig.Arch,
+			InstanceType:  cloudConfig.InstanceType,
+			DiskSize:      cloudConfig.DiskSize,
+			DiskType:      cloudConfig.DiskType,
+			DiskIOPS:      cloudConfig.DiskIOPS,
 		}
 		err := app.CreateNodeCloudConfigFile(cloudConfig.InstanceIDs[i], &nodeConfig)
 		if err != nil {
@@ -328,43 +434,40 @@ func addNodeToClustersConfig(network models.Network, nodeID, clusterName string)
 	return app.WriteClustersConfigFile(&clustersConfig)
 }
 
-// setupAnsible we need to remove existing ansible directory and its contents in .avalanche-cli dir
-// before calling every ansible run command just in case there is a change in playbook
-func setupAnsible(clusterName string) error {
-	err := app.SetupAnsibleEnv()
-	if err != nil {
+func setupNodes(network models.Network, avalancheGoVersion, clusterName string, ansibleHostIDs []string) error {
+	if err := setupNodeExecutor(clusterName); err != nil {
+		return err
+	}
+	if err := distributeStakingCertAndKey(ansibleHostIDs); err != nil {
 		return err
 	}
-	if err = ansible.Setup(app.GetAnsibleDir()); err != nil {
+	if err := nodeExecutor.SetupNode(ansibleHostIDs, avalancheGoVersion, network.Kind == models.Devnet); err != nil {
 		return err
 	}
-	return updateAnsiblePublicIPs(clusterName)
+	// Devnet nodes are left stopped until the Devnet genesis is set, so their NodeIDs are
+	// checked by setupCreatedDevnet
+	if importedStakingIdentities != nil && network.Kind != models.Devnet {
+		return checkNodeIDs(ansibleHostIDs)
+	}
+	return nil
 }
 
-func runAnsible(inventoryPath string, network models.Network, avalancheGoVersion, clusterName, ansibleHostIDs string) error {
-	if err := setupAnsible(clusterName); err != nil {
+// setupCreatedDevnet sets up the Devnet of the newly created cluster [clusterName], and checks
+// the NodeIDs of [ansibleHostIDs] once they are started, if their staking files were imported
+func setupCreatedDevnet(clusterName string, ansibleHostIDs []string) error {
+	ux.Logger.PrintToUser("Setting up Devnet ...")
+	if err := setupDevnet(clusterName, devnetGenesisSpec); err != nil {
 		return err
 	}
-	if err := distributeStakingCertAndKey(strings.Split(ansibleHostIDs, ","), inventoryPath); err != nil {
-		return err
+	if importedStakingIdentities != nil {
+		return checkNodeIDs(ansibleHostIDs)
 	}
-	return ansible.RunAnsiblePlaybookSetupNode(
-		app.Conf.GetConfigPath(),
-		app.GetAnsibleDir(),
-		inventoryPath,
-		avalancheGoVersion,
-		fmt.Sprint(network.Kind == models.Devnet),
-		ansibleHostIDs,
-	)
+	return nil
 }
 
-func setupBuildEnv(inventoryPath, ansibleHostIDs string) error {
+func setupBuildEnv(ansibleHostIDs []string) error {
 	ux.Logger.PrintToUser("Installing Custom VM build environment on the cloud server(s) ...")
-	ansibleTargetHosts := "all"
-	if ansibleHostIDs != "" {
-		ansibleTargetHosts = ansibleHostIDs
-	}
-	return ansible.RunAnsiblePlaybookSetupBuildEnv(app.GetAnsibleDir(), inventoryPath, ansibleTargetHosts)
+	return nodeExecutor.SetupBuildEnv(ansibleHostIDs)
 }
 
 func getNodeID(nodeDir string) (ids.NodeID, error) {
@@ -417,7 +520,35 @@ func generateNodeCertAndKeys(stakerCertFilePath, stakerKeyFilePath, blsKeyFilePa
 	return nodeID, nil
 }
 
-func distributeStakingCertAndKey(ansibleHostIDs []string, inventoryPath string) error {
+// importStakingCertAndKey stores the imported staking identities in the node dirs of
+// [ansibleHostIDs], one for each node
+func importStakingCertAndKey(ansibleHostIDs []string) error {
+	if len(importedStakingIdentities) != len(ansibleHostIDs) {
+		return fmt.Errorf("%d staking identities given for %d nodes", len(importedStakingIdentities), len(ansibleHostIDs))
+	}
+	ux.Logger.PrintToUser("Importing staking keys in local machine...")
+	for i, ansibleInstanceID := range ansibleHostIDs {
+		_, instanceID, err := models.HostAnsibleIDToCloudID(ansibleInstanceID)
+		if err != nil {
+			return err
+		}
+		if err := writeStakingIdentity(importedStakingIdentities[i], app.GetNodeInstanceDirPath(instanceID)); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Imported staking keys for host %s[%s] ", instanceID, importedStakingIdentities[i].NodeID.String())
+	}
+	ux.Logger.PrintToUser("Make sure the validators previously using these staking keys are stopped")
+	return nil
+}
+
+func distributeStakingCertAndKey(ansibleHostIDs []string) error {
+	if importedStakingIdentities != nil {
+		if err := importStakingCertAndKey(ansibleHostIDs); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Copying staking keys to remote machine(s)...")
+		return nodeExecutor.CopyStakingFiles(ansibleHostIDs, app.GetNodesDir())
+	}
 	ux.Logger.PrintToUser("Generating staking keys in local machine...")
 	eg := errgroup.Group{}
 	for _, ansibleInstanceID := range ansibleHostIDs {
@@ -445,7 +576,7 @@ func distributeStakingCertAndKey(ansibleHostIDs []string, inventoryPath string)
 		return err
 	}
 	ux.Logger.PrintToUser("Copying staking keys to remote machine(s)...")
-	return ansible.RunAnsiblePlaybookCopyStakingFiles(app.GetAnsibleDir(), strings.Join(ansibleHostIDs, ","), app.GetNodesDir(), inventoryPath)
+	return nodeExecutor.CopyStakingFiles(ansibleHostIDs, app.GetNodesDir())
 }
 
 func getIPAddress() (string, error) {
@@ -585,7 +716,7 @@ func printResults(cloudConfig CloudConfig, publicIPMap map[string]string, ansibl
 		ux.Logger.PrintToUser("")
 		ux.Logger.PrintToUser("To ssh to node, run: ")
 		ux.Logger.PrintToUser("")
-		ux.Logger.PrintToUser(utils.GetSSHConnectionString(publicIP, cloudConfig.CertFilePath))
+		ux.Logger.PrintToUser(utils.GetSSHConnectionString(constants.AnsibleSSHUser, publicIP, cloudConfig.CertFilePath))
 		ux.Logger.PrintToUser("")
 		ux.Logger.PrintToUser("======================================")
 	}
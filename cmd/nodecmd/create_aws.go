@@ -114,7 +114,7 @@ func promptKeyPairName(ec2Svc *ec2.EC2) (string, string, error) {
 	return certName, newKeyPairName, nil
 }
 
-func getAWSCloudConfig(awsProfile string, region string, authorizeAccess bool) (*ec2.EC2, string, string, error) {
+func getAWSCloudConfig(awsProfile string, region string, authorizeAccess bool, serverConfig cloudServerConfig) (*ec2.EC2, string, string, error) {
 	if region == "" {
 		var err error
 		usEast1 := "us-east-1"
@@ -141,7 +141,10 @@ func getAWSCloudConfig(awsProfile string, region string, authorizeAccess bool) (
 		return nil, "", "", err
 	}
 	ec2Svc := ec2.New(sess)
-	ami, err := awsAPI.GetUbuntuAMIID(ec2Svc)
+	if serverConfig.OSImage != "" {
+		return ec2Svc, region, serverConfig.OSImage, nil
+	}
+	ami, err := awsAPI.GetUbuntuAMIID(ec2Svc, serverConfig.Arch)
 	if err != nil {
 		if strings.Contains(err.Error(), "RequestExpired: Request has expired") {
 			printExpiredCredentialsOutput(awsProfile)
@@ -161,7 +164,9 @@ func createEC2Instances(rootBody *hclwrite.Body,
 	ami,
 	certName,
 	keyPairName,
-	securityGroupName string,
+	securityGroupName,
+	terraformDir string,
+	serverConfig cloudServerConfig,
 ) ([]string, []string, string, string, error) {
 	if err := terraformaws.SetCloudCredentials(rootBody, awsProfile, region); err != nil {
 		return nil, nil, "", "", err
@@ -230,24 +235,24 @@ func createEC2Instances(rootBody *hclwrite.Body,
 	if useStaticIP {
 		terraformaws.SetElasticIPs(rootBody, numNodes)
 	}
-	terraformaws.SetupInstances(rootBody, securityGroupName, useExistingKeyPair, keyPairName, ami, numNodes)
+	terraformaws.SetupInstances(rootBody, securityGroupName, useExistingKeyPair, keyPairName, ami, serverConfig.InstanceType, serverConfig.DiskType, serverConfig.DiskSize, serverConfig.DiskIOPS, numNodes)
 	terraformaws.SetOutput(rootBody, useStaticIP)
-	err = app.CreateTerraformDir()
+	err = app.CreateTerraformDir(terraformDir)
 	if err != nil {
 		return nil, nil, "", "", err
 	}
-	err = terraform.SaveConf(app.GetTerraformDir(), hclFile)
+	err = terraform.SaveConf(terraformDir, hclFile)
 	if err != nil {
 		return nil, nil, "", "", err
 	}
-	instanceIDs, elasticIPs, err := terraformaws.RunTerraform(app.GetTerraformDir(), useStaticIP)
+	instanceIDs, elasticIPs, err := terraformaws.RunTerraform(terraformDir, useStaticIP)
 	if err != nil {
 		return nil, nil, "", "", fmt.Errorf("%s, %w", constants.ErrCreatingAWSNode, err)
 	}
 	ux.Logger.PrintToUser("New EC2 instance(s) successfully created in AWS!")
 	if !useExistingKeyPair {
 		// takes the cert file downloaded from AWS through terraform and moves it to .ssh directory
-		err = addCertToSSH(certName)
+		err = addCertToSSH(terraformDir, certName)
 		if err != nil {
 			return nil, nil, "", "", err
 		}
@@ -259,7 +264,7 @@ func createEC2Instances(rootBody *hclwrite.Body,
 	return instanceIDs, elasticIPs, sshCertPath, keyPairName, nil
 }
 
-func createAWSInstances(ec2Svc *ec2.EC2, numNodes int, awsProfile, region, ami string, usr *user.User) (CloudConfig, error) {
+func createAWSInstances(ec2Svc *ec2.EC2, numNodes int, awsProfile, region, ami string, usr *user.User, terraformDir string, serverConfig cloudServerConfig) (CloudConfig, error) {
 	prefix := usr.Username + "-" + region + constants.AvalancheCLISuffix
 	certName := prefix + "-" + region + constants.CertSuffix
 	securityGroupName := prefix + "-" + region + constants.AWSSecurityGroupSuffix
@@ -269,7 +274,7 @@ func createAWSInstances(ec2Svc *ec2.EC2, numNodes int, awsProfile, region, ami s
 	}
 
 	// Create new EC2 instances
-	instanceIDs, elasticIPs, certFilePath, keyPairName, err := createEC2Instances(rootBody, ec2Svc, hclFile, numNodes, awsProfile, region, ami, certName, prefix, securityGroupName)
+	instanceIDs, elasticIPs, certFilePath, keyPairName, err := createEC2Instances(rootBody, ec2Svc, hclFile, numNodes, awsProfile, region, ami, certName, prefix, securityGroupName, terraformDir, serverConfig)
 	if err != nil {
 		if err.Error() == constants.EIPLimitErr {
 			ux.Logger.PrintToUser("Failed to create AWS cloud server(s), please try creating again in a different region")
@@ -279,7 +284,7 @@ func createAWSInstances(ec2Svc *ec2.EC2, numNodes int, awsProfile, region, ami s
 		if strings.Contains(err.Error(), constants.ErrCreatingAWSNode) {
 			// we stop created instances so that user doesn't pay for unused EC2 instances
 			ux.Logger.PrintToUser("Stopping all created AWS instances due to error to prevent charge for unused AWS instances...")
-			instanceIDs, instanceIDErr := terraformaws.GetInstanceIDs(app.GetTerraformDir())
+			instanceIDs, instanceIDErr := terraformaws.GetInstanceIDs(terraformDir)
 			if instanceIDErr != nil {
 				return CloudConfig{}, instanceIDErr
 			}
@@ -312,6 +317,11 @@ func createAWSInstances(ec2Svc *ec2.EC2, numNodes int, awsProfile, region, ami s
 		securityGroupName,
 		certFilePath,
 		ami,
+		serverConfig.Arch,
+		serverConfig.InstanceType,
+		serverConfig.DiskSize,
+		serverConfig.DiskType,
+		serverConfig.DiskIOPS,
 	}
 	return awsCloudConfig, nil
 }
@@ -348,8 +358,8 @@ func requestStopAWSNodeAuth() error {
 }
 
 // addCertToSSH takes the cert file downloaded from AWS through terraform and moves it to .ssh directory
-func addCertToSSH(certName string) error {
-	certPath := app.GetTempCertPath(certName)
+func addCertToSSH(terraformDir, certName string) error {
+	certPath := app.GetTempCertPath(terraformDir, certName)
 	err := os.Chmod(certPath, 0o400)
 	if err != nil {
 		return err
@@ -9,109 +9,50 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/ansible"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/key"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/primarygenesis"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/config"
-	coreth_params "github.com/ava-labs/coreth/params"
+	"golang.org/x/exp/slices"
 )
 
-// difference between unlock schedule locktime and startime in original genesis
-const (
-	genesisLocktimeStartimeDelta    = 2836800
-	hexa0Str                        = "0x0"
-	defaultLocalCChainFundedAddress = "8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
-	defaultLocalCChainFundedBalance = "0x295BE96E64066972000000"
-	allocationCommonEthAddress      = "0xb3d82b1367d362de99ab59a658165aff520cbd4d"
-)
-
-func generateCustomCchainGenesis() ([]byte, error) {
-	cChainGenesisMap := map[string]interface{}{}
-	cChainGenesisMap["config"] = coreth_params.AvalancheLocalChainConfig
-	cChainGenesisMap["nonce"] = hexa0Str
-	cChainGenesisMap["timestamp"] = hexa0Str
-	cChainGenesisMap["extraData"] = "0x00"
-	cChainGenesisMap["gasLimit"] = "0x5f5e100"
-	cChainGenesisMap["difficulty"] = hexa0Str
-	cChainGenesisMap["mixHash"] = "0x0000000000000000000000000000000000000000000000000000000000000000"
-	cChainGenesisMap["coinbase"] = "0x0000000000000000000000000000000000000000"
-	cChainGenesisMap["alloc"] = map[string]interface{}{
-		defaultLocalCChainFundedAddress: map[string]interface{}{
-			"balance": defaultLocalCChainFundedBalance,
-		},
-	}
-	cChainGenesisMap["number"] = hexa0Str
-	cChainGenesisMap["gasUsed"] = hexa0Str
-	cChainGenesisMap["parentHash"] = "0x0000000000000000000000000000000000000000000000000000000000000000"
-	return json.Marshal(cChainGenesisMap)
+// loadClusterGenesisSpec loads the genesis spec the Devnet of [clusterName] was created with,
+// or an empty one if none was given
+func loadClusterGenesisSpec(clusterName string) (*primarygenesis.Spec, error) {
+	specPath := app.GetClusterGenesisSpecPath(clusterName)
+	if !utils.FileExists(specPath) {
+		return &primarygenesis.Spec{}, nil
+	}
+	return primarygenesis.LoadSpec(specPath)
 }
 
-func generateCustomGenesis(networkID uint32, walletAddr string, stakingAddr string, nodeIDs []string) ([]byte, error) {
-	genesisMap := map[string]interface{}{}
-
-	// cchain
-	cChainGenesisBytes, err := generateCustomCchainGenesis()
+// writeClusterGenesisSpec saves [spec] as the genesis spec of the Devnet of [clusterName], so
+// nodes later added to the Devnet use the same staking params
+func writeClusterGenesisSpec(clusterName string, spec *primarygenesis.Spec) error {
+	specBytes, err := json.MarshalIndent(spec, "", "  ")
 	if err != nil {
-		return nil, err
+		return err
 	}
-	genesisMap["cChainGenesis"] = string(cChainGenesisBytes)
-
-	// pchain genesis
-	genesisMap["networkID"] = networkID
-	startTime := time.Now().Unix()
-	genesisMap["startTime"] = startTime
-	initialStakers := []map[string]interface{}{}
-	for _, nodeID := range nodeIDs {
-		initialStaker := map[string]interface{}{
-			"nodeID":        nodeID,
-			"rewardAddress": walletAddr,
-			"delegationFee": 1000000,
-		}
-		initialStakers = append(initialStakers, initialStaker)
-	}
-	genesisMap["initialStakeDuration"] = 31536000
-	genesisMap["initialStakeDurationOffset"] = 5400
-	genesisMap["initialStakers"] = initialStakers
-	lockTime := startTime + genesisLocktimeStartimeDelta
-	allocations := []interface{}{}
-	alloc := map[string]interface{}{
-		"avaxAddr":      walletAddr,
-		"ethAddr":       allocationCommonEthAddress,
-		"initialAmount": 300000000000000000,
-		"unlockSchedule": []interface{}{
-			map[string]interface{}{"amount": 20000000000000000},
-			map[string]interface{}{"amount": 10000000000000000, "locktime": lockTime},
-		},
-	}
-	allocations = append(allocations, alloc)
-	alloc = map[string]interface{}{
-		"avaxAddr":      stakingAddr,
-		"ethAddr":       allocationCommonEthAddress,
-		"initialAmount": 0,
-		"unlockSchedule": []interface{}{
-			map[string]interface{}{"amount": 10000000000000000, "locktime": lockTime},
-		},
-	}
-	allocations = append(allocations, alloc)
-	genesisMap["allocations"] = allocations
-	genesisMap["initialStakedFunds"] = []interface{}{
-		stakingAddr,
-	}
-	genesisMap["message"] = "{{ fun_quote }}"
-
-	return json.MarshalIndent(genesisMap, "", " ")
+	specPath := app.GetClusterGenesisSpecPath(clusterName)
+	if err := os.MkdirAll(filepath.Dir(specPath), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	return os.WriteFile(specPath, specBytes, constants.WriteReadReadPerms)
 }
 
-func setupDevnet(clusterName string) error {
+// setupDevnet creates a Devnet with the nodes of [clusterName] as initial validators, with a
+// genesis customized by [spec]. Nodes are restarted from a clean chain state, so this is also
+// used to reset an existing Devnet
+func setupDevnet(clusterName string, spec *primarygenesis.Spec) error {
 	if err := checkCluster(clusterName); err != nil {
 		return err
 	}
-	if err := setupAnsible(clusterName); err != nil {
+	if err := setupNodeExecutor(clusterName); err != nil {
 		return err
 	}
 	ansibleHostIDs, err := ansible.GetAnsibleHostsFromInventory(app.GetAnsibleInventoryDirPath(clusterName))
@@ -135,7 +76,15 @@ func setupDevnet(clusterName string) error {
 	}
 
 	// set devnet network
-	network := models.NewDevnetNetwork(ansibleHosts[ansibleHostIDs[0]].IP, 9650)
+	httpPort, _, err := getNodePorts(cloudHostIDs[0])
+	if err != nil {
+		return err
+	}
+	network := models.NewDevnetNetwork(ansibleHosts[ansibleHostIDs[0]].IP, int(httpPort))
+	// the network ID is kept in the cluster network, used by node and subnet commands
+	if spec.NetworkID != 0 {
+		network.ID = spec.NetworkID
+	}
 	ux.Logger.PrintToUser("Devnet Network Id: %d", network.ID)
 	ux.Logger.PrintToUser("Devnet Endpoint: %s", network.Endpoint)
 
@@ -146,18 +95,33 @@ func setupDevnet(clusterName string) error {
 	}
 	stakingAddrStr := k.X()[0]
 
-	// get ewoq key as funded key for devnet genesis
+	// get ewoq key as funded key for devnet genesis, unless the spec funds other one
 	k, err = key.LoadEwoq(network.ID)
 	if err != nil {
 		return err
 	}
 	walletAddrStr := k.X()[0]
+	clusterSpec := spec
+	if spec.FundedKey != "" {
+		// resolve a copy, so the spec saved for the cluster keeps referencing the key
+		resolvedSpec := *spec
+		spec = &resolvedSpec
+		if err := spec.SetFundedKey(network.ID, app.GetKeyPath(spec.FundedKey)); err != nil {
+			return err
+		}
+	}
 
 	// create genesis file at each node dir
-	genesisBytes, err := generateCustomGenesis(network.ID, walletAddrStr, stakingAddrStr, nodeIDs)
+	genesisBytes, err := primarygenesis.Generate(network.ID, spec, walletAddrStr, stakingAddrStr, nodeIDs)
 	if err != nil {
 		return err
 	}
+	if _, err := primarygenesis.Validate(network.ID, genesisBytes, spec); err != nil {
+		return fmt.Errorf("invalid devnet genesis: %w", err)
+	}
+	if err := writeClusterGenesisSpec(clusterName, clusterSpec); err != nil {
+		return err
+	}
 	for _, cloudHostID := range cloudHostIDs {
 		outFile := filepath.Join(app.GetNodeInstanceDirPath(cloudHostID), "genesis.json")
 		if err := os.WriteFile(outFile, genesisBytes, constants.WriteReadReadPerms); err != nil {
@@ -166,36 +130,28 @@ func setupDevnet(clusterName string) error {
 	}
 
 	// create avalanchego conf node.json at each node dir
+	avalancheGoConfig, err := app.LoadClusterAvalancheGoConfig(clusterName)
+	if err != nil {
+		return err
+	}
 	bootstrapIPs := []string{}
 	bootstrapIDs := []string{}
 	for i, ansibleHostID := range ansibleHostIDs {
 		cloudHostID := cloudHostIDs[i]
-		confMap := map[string]interface{}{}
-		confMap[config.HTTPHostKey] = ""
-		confMap[config.PublicIPKey] = ansibleHosts[ansibleHostID].IP
-		confMap[config.NetworkNameKey] = fmt.Sprintf("network-%d", network.ID)
-		confMap[config.BootstrapIDsKey] = strings.Join(bootstrapIDs, ",")
-		confMap[config.BootstrapIPsKey] = strings.Join(bootstrapIPs, ",")
-		confMap[config.GenesisFileKey] = "/home/ubuntu/.avalanchego/configs/genesis.json"
-		bootstrapIDs = append(bootstrapIDs, nodeIDs[i])
-		bootstrapIPs = append(bootstrapIPs, ansibleHosts[ansibleHostID].IP+":9651")
-		confBytes, err := json.MarshalIndent(confMap, "", " ")
+		_, stakingPort, err := getNodePorts(cloudHostID)
 		if err != nil {
 			return err
 		}
-		outFile := filepath.Join(app.GetNodeInstanceDirPath(cloudHostID), "node.json")
-		if err := os.WriteFile(outFile, confBytes, constants.WriteReadReadPerms); err != nil {
+		nodeFlags := avalancheGoConfig.GetDesiredNodeConfig(cloudHostID, spec.NodeFlags())
+		if err := writeDevnetNodeConf(cloudHostID, ansibleHosts[ansibleHostID].IP, network, bootstrapIDs, bootstrapIPs, nodeFlags); err != nil {
 			return err
 		}
+		bootstrapIDs = append(bootstrapIDs, nodeIDs[i])
+		bootstrapIPs = append(bootstrapIPs, fmt.Sprintf("%s:%d", ansibleHosts[ansibleHostID].IP, stakingPort))
 	}
 
 	// update node/s genesis + conf and start
-	if err := ansible.RunAnsiblePlaybookSetupDevnet(
-		app.GetAnsibleDir(),
-		strings.Join(ansibleHostIDs, ","),
-		app.GetNodesDir(),
-		app.GetAnsibleInventoryDirPath(clusterName),
-	); err != nil {
+	if err := nodeExecutor.SetupDevnet(ansibleHostIDs, app.GetNodesDir()); err != nil {
 		return err
 	}
 
@@ -205,9 +161,101 @@ func setupDevnet(clusterName string) error {
 		return err
 	}
 	clusterConfig := clustersConfig.Clusters[clusterName]
-	clustersConfig.Clusters[clusterName] = models.ClusterConfig{
-		Network: network,
-		Nodes:   clusterConfig.Nodes,
-	}
+	clusterConfig.Network = network
+	clustersConfig.Clusters[clusterName] = clusterConfig
 	return app.WriteClustersConfigFile(&clustersConfig)
 }
+
+// writeDevnetNodeConf creates avalanchego conf node.json at the node dir of [cloudHostID],
+// bootstrapping from the given nodes, and with the additional avalanchego [nodeFlags], which
+// include the avalanchego config set for the node with node config set
+func writeDevnetNodeConf(cloudHostID string, publicIP string, network models.Network, bootstrapIDs []string, bootstrapIPs []string, nodeFlags map[string]interface{}) error {
+	confMap := map[string]interface{}{}
+	for flag, value := range nodeFlags {
+		confMap[flag] = value
+	}
+	confMap[config.HTTPHostKey] = ""
+	confMap[config.PublicIPKey] = publicIP
+	confMap[config.NetworkNameKey] = fmt.Sprintf("network-%d", network.ID)
+	confMap[config.BootstrapIDsKey] = strings.Join(bootstrapIDs, ",")
+	confMap[config.BootstrapIPsKey] = strings.Join(bootstrapIPs, ",")
+	// avalanchego expands env vars on the genesis file path, so this works for any ssh user
+	confMap[config.GenesisFileKey] = "$HOME/" + constants.CloudNodeConfigPath + constants.GenesisFileName
+	confBytes, err := json.MarshalIndent(confMap, "", " ")
+	if err != nil {
+		return err
+	}
+	outFile := filepath.Join(app.GetNodeInstanceDirPath(cloudHostID), constants.NodeFileName)
+	return os.WriteFile(outFile, confBytes, constants.WriteReadReadPerms)
+}
+
+// joinDevnet sets up [newAnsibleHostIDs] to join the existing devnet of [clusterName], using
+// the genesis of the devnet and bootstrapping from the other nodes of the cluster
+func joinDevnet(clusterName string, newAnsibleHostIDs []string) error {
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	network := clustersConfig.Clusters[clusterName].Network
+	spec, err := loadClusterGenesisSpec(clusterName)
+	if err != nil {
+		return err
+	}
+	avalancheGoConfig, err := app.LoadClusterAvalancheGoConfig(clusterName)
+	if err != nil {
+		return err
+	}
+	ansibleHosts, err := ansible.GetInventoryFromAnsibleInventoryFile(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	var genesisBytes []byte
+	bootstrapIDs := []string{}
+	bootstrapIPs := []string{}
+	for _, host := range ansibleHosts {
+		if slices.Contains(newAnsibleHostIDs, host.NodeID) {
+			continue
+		}
+		_, cloudHostID, err := models.HostAnsibleIDToCloudID(host.NodeID)
+		if err != nil {
+			return err
+		}
+		if genesisBytes == nil {
+			genesisBytes, err = os.ReadFile(filepath.Join(app.GetNodeInstanceDirPath(cloudHostID), constants.GenesisFileName))
+			if err != nil {
+				return fmt.Errorf("failed to load devnet genesis of cluster %s: %w", clusterName, err)
+			}
+		}
+		nodeID, err := getNodeID(app.GetNodeInstanceDirPath(cloudHostID))
+		if err != nil {
+			return err
+		}
+		_, stakingPort, err := getNodePorts(cloudHostID)
+		if err != nil {
+			return err
+		}
+		bootstrapIDs = append(bootstrapIDs, nodeID.String())
+		bootstrapIPs = append(bootstrapIPs, fmt.Sprintf("%s:%d", host.IP, stakingPort))
+	}
+	if genesisBytes == nil {
+		return fmt.Errorf("no devnet node found in cluster %s to join from", clusterName)
+	}
+	for _, host := range ansibleHosts {
+		if !slices.Contains(newAnsibleHostIDs, host.NodeID) {
+			continue
+		}
+		_, cloudHostID, err := models.HostAnsibleIDToCloudID(host.NodeID)
+		if err != nil {
+			return err
+		}
+		outFile := filepath.Join(app.GetNodeInstanceDirPath(cloudHostID), constants.GenesisFileName)
+		if err := os.WriteFile(outFile, genesisBytes, constants.WriteReadReadPerms); err != nil {
+			return err
+		}
+		nodeFlags := avalancheGoConfig.GetDesiredNodeConfig(cloudHostID, spec.NodeFlags())
+		if err := writeDevnetNodeConf(cloudHostID, host.IP, network, bootstrapIDs, bootstrapIPs, nodeFlags); err != nil {
+			return err
+		}
+	}
+	return nodeExecutor.SetupDevnet(newAnsibleHostIDs, app.GetNodesDir())
+}
@@ -0,0 +1,194 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+var existingHostsPath string
+
+// existingHost is an entry of the --existing-hosts file
+type existingHost struct {
+	IP         string `json:"ip"`
+	SSHUser    string `json:"sshUser"`
+	SSHKeyPath string `json:"sshKeyPath"`
+}
+
+// loadExistingHosts reads the servers listed at [hostsPath], and returns them as inventory
+// hosts. Servers are identified by their IP
+func loadExistingHosts(hostsPath string) ([]models.Host, error) {
+	hostsBytes, err := os.ReadFile(hostsPath)
+	if err != nil {
+		return nil, err
+	}
+	existingHosts := []existingHost{}
+	if err := json.Unmarshal(hostsBytes, &existingHosts); err != nil {
+		return nil, fmt.Errorf("failed to parse existing hosts file %s: %w", hostsPath, err)
+	}
+	if len(existingHosts) == 0 {
+		return nil, fmt.Errorf("no hosts found in existing hosts file %s", hostsPath)
+	}
+	hosts := []models.Host{}
+	seenIPs := map[string]bool{}
+	for _, existingHost := range existingHosts {
+		if net.ParseIP(existingHost.IP) == nil {
+			return nil, fmt.Errorf("invalid host IP %q", existingHost.IP)
+		}
+		if seenIPs[existingHost.IP] {
+			return nil, fmt.Errorf("host %s is listed more than once", existingHost.IP)
+		}
+		seenIPs[existingHost.IP] = true
+		sshUser := existingHost.SSHUser
+		if sshUser == "" {
+			sshUser = constants.AnsibleSSHUser
+		}
+		if existingHost.SSHKeyPath == "" {
+			return nil, fmt.Errorf("missing ssh key path for host %s", existingHost.IP)
+		}
+		sshKeyPath := existingHost.SSHKeyPath
+		if strings.HasPrefix(sshKeyPath, "~/") {
+			sshKeyPath = utils.UserHomePath(strings.TrimPrefix(sshKeyPath, "~/"))
+		}
+		sshKeyPath, err = filepath.Abs(sshKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		if !utils.FileExists(sshKeyPath) {
+			return nil, fmt.Errorf("ssh key %s for host %s not found", sshKeyPath, existingHost.IP)
+		}
+		ansibleHostID, err := models.HostCloudIDToAnsibleID(constants.ExistingHostsService, existingHost.IP)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, models.Host{
+			NodeID:            ansibleHostID,
+			IP:                existingHost.IP,
+			SSHUser:           sshUser,
+			SSHPrivateKeyPath: sshKeyPath,
+			SSHCommonArgs:     constants.AnsibleSSHInventoryParams,
+		})
+	}
+	return hosts, nil
+}
+
+func checkExistingHostsFlags() error {
+	if useAWS || useGCP || cmdLineRegion != "" || cmdLineGCPCredentialsPath != "" || cmdLineGCPProjectName != "" ||
+		cmdLineAlternativeKeyPairName != "" || awsProfile != constants.AWSDefaultCredential || authorizeAccess {
+		return errors.New("cloud options can't be used when creating node/s on existing hosts")
+	}
+	if numNodes != 0 {
+		return errors.New("number of nodes is given by the existing hosts file")
+	}
+	return nil
+}
+
+// createNodesOnExistingHosts sets up validators on servers not created by the CLI. Servers are
+// added to the cluster inventory with their own ssh settings, and then follow the same setup
+// flow as cloud servers
+func createNodesOnExistingHosts(network models.Network, clusterName string) error {
+	hosts, ansibleHostIDs, err := addExistingHostsToCluster(network, clusterName)
+	if err != nil {
+		return err
+	}
+	avalancheGoVersion, err := getAvalancheGoVersion()
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Installing AvalancheGo and Avalanche-CLI and starting bootstrap process on the existing host(s) ...")
+	if err := setupNodes(network, avalancheGoVersion, clusterName, ansibleHostIDs); err != nil {
+		return err
+	}
+	if err := setupBuildEnv(ansibleHostIDs); err != nil {
+		return err
+	}
+	if network.Kind == models.Devnet {
+		if err := setupCreatedDevnet(clusterName, ansibleHostIDs); err != nil {
+			return err
+		}
+	}
+
+	printExistingHostsResults(hosts)
+	ux.Logger.PrintToUser("AvalancheGo and Avalanche-CLI installed and node(s) are bootstrapping!")
+	return nil
+}
+
+// addExistingHostsToCluster checks ssh access to the servers of the --existing-hosts file, and
+// registers them in the cluster config and inventory. Returns the hosts and their ansible host IDs
+func addExistingHostsToCluster(network models.Network, clusterName string) ([]models.Host, []string, error) {
+	hosts, err := loadExistingHosts(existingHostsPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if useAnsible {
+		for _, host := range hosts {
+			if host.SSHUser != constants.AnsibleSSHUser {
+				return nil, nil, fmt.Errorf("ansible playbooks require the %s ssh user, found %s for host %s", constants.AnsibleSSHUser, host.SSHUser, host.IP)
+			}
+		}
+	}
+	ux.Logger.PrintToUser("Checking ssh access to the existing host(s) ...")
+	if _, err := ssh.RunOnHosts(hosts, func(*ssh.Client) (struct{}, error) { return struct{}{}, nil }); err != nil {
+		return nil, nil, err
+	}
+	ansibleHostIDs := []string{}
+	for _, host := range hosts {
+		_, hostID, err := models.HostAnsibleIDToCloudID(host.NodeID)
+		if err != nil {
+			return nil, nil, err
+		}
+		nodeConfig := models.NodeConfig{
+			NodeID:       hostID,
+			CertPath:     host.SSHPrivateKeyPath,
+			ElasticIP:    host.IP,
+			CloudService: constants.ExistingHostsService,
+		}
+		if err := app.CreateNodeCloudConfigFile(hostID, &nodeConfig); err != nil {
+			return nil, nil, err
+		}
+		if err := addNodeToClustersConfig(network, hostID, clusterName); err != nil {
+			return nil, nil, err
+		}
+		ansibleHostIDs = append(ansibleHostIDs, host.NodeID)
+	}
+	if err := ansible.AddHostsToAnsibleInventory(app.GetAnsibleInventoryDirPath(clusterName), hosts); err != nil {
+		return nil, nil, err
+	}
+	return hosts, ansibleHostIDs, nil
+}
+
+func printExistingHostsResults(hosts []models.Host) {
+	ux.Logger.PrintToUser("======================================")
+	ux.Logger.PrintToUser("AVALANCHE NODE(S) SUCCESSFULLY SET UP!")
+	ux.Logger.PrintToUser("======================================")
+	ux.Logger.PrintToUser("Please wait until the node(s) are successfully bootstrapped to run further commands on the node(s)")
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Here are the details of the set up node(s): ")
+	for _, host := range hosts {
+		_, hostID, _ := models.HostAnsibleIDToCloudID(host.NodeID)
+		ux.Logger.PrintToUser("======================================")
+		ux.Logger.PrintToUser(fmt.Sprintf("Node %s details: ", host.NodeID))
+		ux.Logger.PrintToUser(fmt.Sprintf("Public IP: %s", host.IP))
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser(fmt.Sprintf("staker.crt and staker.key are stored at %s. If anything happens to your node or the machine node runs on, these files can be used to fully recreate your node.", app.GetNodeInstanceDirPath(hostID)))
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("To ssh to node, run: ")
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser(utils.GetSSHConnectionString(host.SSHUser, host.IP, host.SSHPrivateKeyPath))
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser("======================================")
+	}
+}
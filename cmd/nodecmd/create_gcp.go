@@ -97,7 +97,7 @@ func getGCPCloudCredentials() (*compute.Service, string, string, error) {
 	return computeService, gcpProjectName, gcpCredentialsPath, err
 }
 
-func getGCPConfig(zone string) (*compute.Service, string, string, string, string, error) {
+func getGCPConfig(zone string, serverConfig cloudServerConfig) (*compute.Service, string, string, string, string, error) {
 	if zone == "" {
 		usEast := "us-east1-b"
 		usCentral := "us-central1-c"
@@ -123,9 +123,12 @@ func getGCPConfig(zone string) (*compute.Service, string, string, string, string
 	if err != nil {
 		return nil, "", "", "", "", err
 	}
-	imageID, err := gcpAPI.GetUbuntuImageID(gcpClient)
-	if err != nil {
-		return nil, "", "", "", "", err
+	imageID := serverConfig.OSImage
+	if imageID == "" {
+		imageID, err = gcpAPI.GetUbuntuImageID(gcpClient, serverConfig.Arch)
+		if err != nil {
+			return nil, "", "", "", "", err
+		}
 	}
 	return gcpClient, zone, imageID, gcpCredentialFilePath, projectName, nil
 }
@@ -149,7 +152,9 @@ func createGCEInstances(rootBody *hclwrite.Body,
 	ami,
 	cliDefaultName,
 	projectName,
-	credentialsPath string,
+	credentialsPath,
+	terraformDir string,
+	serverConfig cloudServerConfig,
 ) ([]string, []string, string, string, error) {
 	keyPairName := fmt.Sprintf("%s-keypair", cliDefaultName)
 	sshKeyPath, err := app.GetSSHCertFilePath(keyPairName)
@@ -213,15 +218,15 @@ func createGCEInstances(rootBody *hclwrite.Body,
 	if err != nil {
 		return nil, nil, "", "", err
 	}
-	terraformgcp.SetupInstances(rootBody, networkName, string(sshPublicKey), ami, publicIPName, nodeName, numNodes, networkExists)
+	terraformgcp.SetupInstances(rootBody, networkName, string(sshPublicKey), ami, publicIPName, nodeName, serverConfig.InstanceType, serverConfig.DiskType, serverConfig.DiskSize, numNodes, networkExists)
 	if useStaticIP {
 		terraformgcp.SetOutput(rootBody)
 	}
-	err = app.CreateTerraformDir()
+	err = app.CreateTerraformDir(terraformDir)
 	if err != nil {
 		return nil, nil, "", "", err
 	}
-	err = terraform.SaveConf(app.GetTerraformDir(), hclFile)
+	err = terraform.SaveConf(terraformDir, hclFile)
 	if err != nil {
 		return nil, nil, "", "", err
 	}
@@ -229,7 +234,7 @@ func createGCEInstances(rootBody *hclwrite.Body,
 	for i := 0; i < numNodes; i++ {
 		instanceIDs = append(instanceIDs, fmt.Sprintf("%s-%s", nodeName, strconv.Itoa(i)))
 	}
-	elasticIPs, err := terraformgcp.RunTerraform(app.GetTerraformDir(), useStaticIP)
+	elasticIPs, err := terraformgcp.RunTerraform(terraformDir, useStaticIP)
 	if err != nil {
 		return instanceIDs, nil, "", "", errors.New(constants.ErrCreatingGCPNode)
 	}
@@ -250,6 +255,8 @@ func createGCPInstance(
 	gcpCredentialFilepath string,
 	gcpProjectName string,
 	clusterName string,
+	terraformDir string,
+	serverConfig cloudServerConfig,
 ) (CloudConfig, error) {
 	defaultAvalancheCLIPrefix := usr.Username + constants.AvalancheCLISuffix
 	hclFile, rootBody, err := terraform.InitConf()
@@ -266,6 +273,8 @@ func createGCPInstance(
 		defaultAvalancheCLIPrefix,
 		gcpProjectName,
 		gcpCredentialFilepath,
+		terraformDir,
+		serverConfig,
 	)
 	if err != nil {
 		ux.Logger.PrintToUser("Failed to create GCP cloud server")
@@ -305,6 +314,11 @@ func createGCPInstance(
 		fmt.Sprintf("%s-network", defaultAvalancheCLIPrefix),
 		certFilePath,
 		imageID,
+		serverConfig.Arch,
+		serverConfig.InstanceType,
+		serverConfig.DiskSize,
+		serverConfig.DiskType,
+		serverConfig.DiskIOPS,
 	}
 	return gcpCloudConfig, nil
 }
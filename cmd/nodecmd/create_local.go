@@ -0,0 +1,207 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os/user"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+)
+
+const localNodeIP = "127.0.0.1"
+
+var useLocalMachine bool
+
+func checkLocalMachineFlags() error {
+	if useAWS || useGCP || cmdLineRegion != "" || cmdLineGCPCredentialsPath != "" || cmdLineGCPProjectName != "" ||
+		cmdLineAlternativeKeyPairName != "" || awsProfile != constants.AWSDefaultCredential || authorizeAccess {
+		return errors.New("cloud options can't be used when creating node/s on the local machine")
+	}
+	if existingHostsPath != "" {
+		return errors.New("could not use both existing hosts and local machine options")
+	}
+	if useAnsible {
+		return errors.New("ansible can't be used with node/s running on the local machine")
+	}
+	return nil
+}
+
+// getLocalNodesPorts returns the ports assigned to all local machine nodes, so they are not
+// given again while those nodes are not running
+func getLocalNodesPorts() (map[uint32]bool, error) {
+	usedPorts := map[uint32]bool{}
+	if !app.ClustersConfigExists() {
+		return usedPorts, nil
+	}
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return nil, err
+	}
+	for _, clusterConfig := range clustersConfig.Clusters {
+		for _, node := range clusterConfig.Nodes {
+			nodeConfig, err := app.LoadClusterNodeConfig(node)
+			if err != nil {
+				continue
+			}
+			if nodeConfig.CloudService == constants.LocalMachineService {
+				usedPorts[nodeConfig.HTTPPort] = true
+				usedPorts[nodeConfig.StakingPort] = true
+			}
+		}
+	}
+	return usedPorts, nil
+}
+
+// getFreeLocalPort asks the OS for a port not in use on the local machine, and not
+// in [usedPorts]. The port is added to [usedPorts]
+func getFreeLocalPort(usedPorts map[uint32]bool) (uint32, error) {
+	for {
+		listener, err := net.Listen("tcp", localNodeIP+":0")
+		if err != nil {
+			return 0, err
+		}
+		port := uint32(listener.Addr().(*net.TCPAddr).Port)
+		if err := listener.Close(); err != nil {
+			return 0, err
+		}
+		if !usedPorts[port] {
+			usedPorts[port] = true
+			return port, nil
+		}
+	}
+}
+
+// getNodePorts returns the API and staking ports of [cloudHostID]. Nodes running on the local
+// machine use the ports assigned at creation, cloud nodes use the avalanchego defaults
+func getNodePorts(cloudHostID string) (uint32, uint32, error) {
+	nodeConfig, err := app.LoadClusterNodeConfig(cloudHostID)
+	if err != nil {
+		return 0, 0, err
+	}
+	httpPort := nodeConfig.HTTPPort
+	if httpPort == 0 {
+		httpPort = constants.AvalanchegoAPIPort
+	}
+	stakingPort := nodeConfig.StakingPort
+	if stakingPort == 0 {
+		stakingPort = constants.AvalanchegoP2PPort
+	}
+	return httpPort, stakingPort, nil
+}
+
+// createLocalNodes sets up the cluster nodes as avalanchego processes of the local machine,
+// each one with its own data dir and ports. Nodes are added to the cluster inventory and
+// config as any other node, so all node commands can be used on them without a cloud account
+func createLocalNodes(network models.Network, clusterName string) error {
+	ansibleHostIDs, err := addLocalNodesToCluster(network, clusterName)
+	if err != nil {
+		return err
+	}
+	avalancheGoVersion, err := getAvalancheGoVersion()
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Installing AvalancheGo and starting bootstrap process on the local machine ...")
+	if err := setupNodes(network, avalancheGoVersion, clusterName, ansibleHostIDs); err != nil {
+		return err
+	}
+	if network.Kind == models.Devnet {
+		if err := setupCreatedDevnet(clusterName, ansibleHostIDs); err != nil {
+			return err
+		}
+	}
+
+	printLocalNodesResults(ansibleHostIDs)
+	ux.Logger.PrintToUser("AvalancheGo installed and node(s) are bootstrapping!")
+	return nil
+}
+
+// addLocalNodesToCluster assigns free ports to [numNodes] new local machine nodes, and registers
+// them in the cluster config and inventory. Returns their ansible host IDs
+func addLocalNodesToCluster(network models.Network, clusterName string) ([]string, error) {
+	if numNodes <= 0 {
+		var err error
+		numNodes, err = app.Prompt.CaptureInt("How many nodes do you want to set up on the local machine?")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if numNodes <= 0 {
+		return nil, errors.New("number of nodes to create must be greater than 0")
+	}
+	usr, err := user.Current()
+	if err != nil {
+		return nil, err
+	}
+	usedPorts, err := getLocalNodesPorts()
+	if err != nil {
+		return nil, err
+	}
+	hosts := []models.Host{}
+	ansibleHostIDs := []string{}
+	for i := 0; i < numNodes; i++ {
+		httpPort, err := getFreeLocalPort(usedPorts)
+		if err != nil {
+			return nil, err
+		}
+		stakingPort, err := getFreeLocalPort(usedPorts)
+		if err != nil {
+			return nil, err
+		}
+		// port based IDs are unique among the nodes of the local machine
+		hostID := fmt.Sprintf("%s-%d", clusterName, httpPort)
+		nodeConfig := models.NodeConfig{
+			NodeID:       hostID,
+			ElasticIP:    localNodeIP,
+			CloudService: constants.LocalMachineService,
+			HTTPPort:     httpPort,
+			StakingPort:  stakingPort,
+		}
+		if err := app.CreateNodeCloudConfigFile(hostID, &nodeConfig); err != nil {
+			return nil, err
+		}
+		if err := addNodeToClustersConfig(network, hostID, clusterName); err != nil {
+			return nil, err
+		}
+		ansibleHostID, err := models.HostCloudIDToAnsibleID(constants.LocalMachineService, hostID)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, models.Host{
+			NodeID:  ansibleHostID,
+			IP:      localNodeIP,
+			SSHUser: usr.Username,
+		})
+		ansibleHostIDs = append(ansibleHostIDs, ansibleHostID)
+	}
+	if err := ansible.AddHostsToAnsibleInventory(app.GetAnsibleInventoryDirPath(clusterName), hosts); err != nil {
+		return nil, err
+	}
+	return ansibleHostIDs, nil
+}
+
+func printLocalNodesResults(ansibleHostIDs []string) {
+	ux.Logger.PrintToUser("======================================")
+	ux.Logger.PrintToUser("AVALANCHE NODE(S) SUCCESSFULLY SET UP!")
+	ux.Logger.PrintToUser("======================================")
+	ux.Logger.PrintToUser("Please wait until the node(s) are successfully bootstrapped to run further commands on the node(s)")
+	ux.Logger.PrintToUser("")
+	ux.Logger.PrintToUser("Here are the details of the set up node(s): ")
+	for _, ansibleHostID := range ansibleHostIDs {
+		_, hostID, _ := models.HostAnsibleIDToCloudID(ansibleHostID)
+		httpPort, stakingPort, _ := getNodePorts(hostID)
+		ux.Logger.PrintToUser("======================================")
+		ux.Logger.PrintToUser(fmt.Sprintf("Node %s details: ", ansibleHostID))
+		ux.Logger.PrintToUser(fmt.Sprintf("API Endpoint: http://%s:%d", localNodeIP, httpPort))
+		ux.Logger.PrintToUser(fmt.Sprintf("Staking Port: %d", stakingPort))
+		ux.Logger.PrintToUser("")
+		ux.Logger.PrintToUser(fmt.Sprintf("staker.crt, staker.key, avalanchego data and logs are stored at %s", app.GetNodeInstanceDirPath(hostID)))
+		ux.Logger.PrintToUser("======================================")
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+const customServerOption = "Choose custom value"
+
+var (
+	useARM64         bool
+	cmdLineNodeType  string
+	cmdLineDiskSize  int
+	cmdLineDiskType  string
+	cmdLineDiskIOPS  int
+	cmdLineOSImage   string
+	awsDiskTypes     = []string{"gp3", "gp2", "io1", "io2"}
+	awsIOPSDiskTypes = []string{"gp3", "io1", "io2"}
+	gcpDiskTypes     = []string{"pd-standard", "pd-balanced", "pd-ssd"}
+	awsInstanceTypes = []string{constants.AWSDefaultInstanceType, "c5.4xlarge", "m5.2xlarge"}
+	awsARM64Types    = []string{constants.AWSDefaultARM64InstanceType, "c7g.4xlarge", "m7g.2xlarge"}
+	gcpInstanceTypes = []string{constants.GCPDefaultInstanceType, "e2-standard-16", "n2-standard-8"}
+	gcpARM64Types    = []string{constants.GCPDefaultARM64InstanceType, "t2a-standard-16"}
+)
+
+// cloudServerConfig is the configuration of the cloud servers created for the nodes of a cluster
+type cloudServerConfig struct {
+	Arch         string
+	InstanceType string
+	DiskSize     int
+	DiskType     string
+	DiskIOPS     int
+	OSImage      string // empty to use the latest Ubuntu 20.04 image for Arch
+}
+
+func (c cloudServerConfig) String() string {
+	osImage := c.OSImage
+	if osImage == "" {
+		osImage = "Ubuntu 20.04"
+	}
+	diskIOPS := ""
+	if c.DiskIOPS > 0 {
+		diskIOPS = fmt.Sprintf(" %d IOPS", c.DiskIOPS)
+	}
+	return fmt.Sprintf("%s %s, %d GB %s%s disk, %s", c.InstanceType, c.Arch, c.DiskSize, c.DiskType, diskIOPS, osImage)
+}
+
+func getDefaultCloudServerConfig(cloudService string, arch string) cloudServerConfig {
+	serverConfig := cloudServerConfig{
+		Arch:     arch,
+		DiskSize: constants.CloudServerStorageSize,
+	}
+	if cloudService == constants.GCPCloudService {
+		serverConfig.DiskType = constants.GCPDefaultDiskType
+		serverConfig.InstanceType = constants.GCPDefaultInstanceType
+		if arch == constants.ArchARM64 {
+			serverConfig.InstanceType = constants.GCPDefaultARM64InstanceType
+		}
+	} else {
+		serverConfig.DiskType = constants.AWSDefaultDiskType
+		serverConfig.InstanceType = constants.AWSDefaultInstanceType
+		if arch == constants.ArchARM64 {
+			serverConfig.InstanceType = constants.AWSDefaultARM64InstanceType
+		}
+	}
+	return serverConfig
+}
+
+// getInstanceTypeOptions returns the instance types offered on the wizard for [cloudService] and [arch]
+func getInstanceTypeOptions(cloudService string, arch string) []string {
+	switch {
+	case cloudService == constants.GCPCloudService && arch == constants.ArchARM64:
+		return gcpARM64Types
+	case cloudService == constants.GCPCloudService:
+		return gcpInstanceTypes
+	case arch == constants.ArchARM64:
+		return awsARM64Types
+	default:
+		return awsInstanceTypes
+	}
+}
+
+func getDiskTypes(cloudService string) []string {
+	if cloudService == constants.GCPCloudService {
+		return gcpDiskTypes
+	}
+	return awsDiskTypes
+}
+
+func validateCloudServerConfig(cloudService string, serverConfig cloudServerConfig) error {
+	if serverConfig.DiskSize <= 0 {
+		return errors.New("disk size must be greater than 0")
+	}
+	if !slices.Contains(getDiskTypes(cloudService), serverConfig.DiskType) {
+		return fmt.Errorf("invalid disk type %s for %s, valid ones are %s", serverConfig.DiskType, cloudService, getDiskTypes(cloudService))
+	}
+	if serverConfig.DiskIOPS < 0 {
+		return errors.New("disk IOPS can't be negative")
+	}
+	if serverConfig.DiskIOPS > 0 && (cloudService != constants.AWSCloudService || !slices.Contains(awsIOPSDiskTypes, serverConfig.DiskType)) {
+		return fmt.Errorf("disk IOPS can only be set for AWS disk types %s", awsIOPSDiskTypes)
+	}
+	if serverConfig.DiskIOPS == 0 && cloudService == constants.AWSCloudService && (serverConfig.DiskType == "io1" || serverConfig.DiskType == "io2") {
+		return fmt.Errorf("disk IOPS must be set for AWS disk type %s", serverConfig.DiskType)
+	}
+	return nil
+}
+
+// captureCustomServerOption prompts the user to select one of [options], or a custom value
+func captureCustomServerOption(promptStr string, options []string) (string, error) {
+	option, err := app.Prompt.CaptureList(promptStr, append(slices.Clone(options), customServerOption))
+	if err != nil {
+		return "", err
+	}
+	if option == customServerOption {
+		return app.Prompt.CaptureString(promptStr)
+	}
+	return option, nil
+}
+
+// promptCloudServerConfig walks the user through the configuration of the cloud servers to create
+// on [cloudService]
+func promptCloudServerConfig(cloudService string) (cloudServerConfig, error) {
+	archOption, err := app.Prompt.CaptureList(
+		"Which CPU architecture do you want your cloud server(s) to use?",
+		[]string{constants.ArchAMD64, fmt.Sprintf("%s (AWS Graviton / GCP Tau T2A)", constants.ArchARM64)},
+	)
+	if err != nil {
+		return cloudServerConfig{}, err
+	}
+	arch := constants.ArchAMD64
+	if archOption != constants.ArchAMD64 {
+		arch = constants.ArchARM64
+	}
+	serverConfig := getDefaultCloudServerConfig(cloudService, arch)
+	serverConfig.InstanceType, err = captureCustomServerOption("Which instance type do you want to use?", getInstanceTypeOptions(cloudService, arch))
+	if err != nil {
+		return cloudServerConfig{}, err
+	}
+	diskSize, err := captureCustomServerOption("What disk size (in GB) do you want to use?", []string{strconv.Itoa(constants.CloudServerStorageSize)})
+	if err != nil {
+		return cloudServerConfig{}, err
+	}
+	serverConfig.DiskSize, err = strconv.Atoi(diskSize)
+	if err != nil {
+		return cloudServerConfig{}, fmt.Errorf("invalid disk size %s: %w", diskSize, err)
+	}
+	serverConfig.DiskType, err = app.Prompt.CaptureList("Which disk type do you want to use?", getDiskTypes(cloudService))
+	if err != nil {
+		return cloudServerConfig{}, err
+	}
+	if cloudService == constants.AWSCloudService && slices.Contains(awsIOPSDiskTypes, serverConfig.DiskType) {
+		serverConfig.DiskIOPS, err = app.Prompt.CaptureInt("How many IOPS do you want to provision for the disk? (0 to use the default)")
+		if err != nil {
+			return cloudServerConfig{}, err
+		}
+	}
+	serverConfig.OSImage, err = app.Prompt.CaptureStringAllowEmpty("Which OS image do you want to use? (leave empty to use the latest Ubuntu 20.04)")
+	if err != nil {
+		return cloudServerConfig{}, err
+	}
+	return serverConfig, nil
+}
+
+// getCloudServerConfig returns the configuration of the cloud servers to create on [cloudService], given
+// by the command line flags. If [promptCustomization], the user is offered to customize the defaults
+func getCloudServerConfig(cloudService string, promptCustomization bool) (cloudServerConfig, error) {
+	arch := constants.ArchAMD64
+	if useARM64 {
+		arch = constants.ArchARM64
+	}
+	serverConfig := getDefaultCloudServerConfig(cloudService, arch)
+	if cmdLineNodeType != "" {
+		serverConfig.InstanceType = cmdLineNodeType
+	}
+	if cmdLineDiskSize != 0 {
+		serverConfig.DiskSize = cmdLineDiskSize
+	}
+	if cmdLineDiskType != "" {
+		serverConfig.DiskType = cmdLineDiskType
+	}
+	serverConfig.DiskIOPS = cmdLineDiskIOPS
+	serverConfig.OSImage = cmdLineOSImage
+	if promptCustomization {
+		customize, err := app.Prompt.CaptureNoYes(fmt.Sprintf("Do you want to customize the cloud server configuration (default is %s)?", serverConfig))
+		if err != nil {
+			return cloudServerConfig{}, err
+		}
+		if customize {
+			serverConfig, err = promptCloudServerConfig(cloudService)
+			if err != nil {
+				return cloudServerConfig{}, err
+			}
+		}
+	}
+	if err := validateCloudServerConfig(cloudService, serverConfig); err != nil {
+		return cloudServerConfig{}, err
+	}
+	return serverConfig, nil
+}
+
+// getNodeArch returns the cpu architecture of [nodeConfig], which is not set for nodes
+// created when only amd64 was supported
+func getNodeArch(nodeConfig models.NodeConfig) string {
+	if nodeConfig.Arch == "" {
+		return constants.ArchAMD64
+	}
+	return nodeConfig.Arch
+}
+
+// getNodeCloudServerConfig returns the configuration of the cloud server of [nodeConfig]. Settings
+// not saved for nodes created by older versions fall back to the defaults of [cloudService]
+func getNodeCloudServerConfig(cloudService string, nodeConfig models.NodeConfig) cloudServerConfig {
+	serverConfig := getDefaultCloudServerConfig(cloudService, getNodeArch(nodeConfig))
+	if nodeConfig.InstanceType != "" {
+		serverConfig.InstanceType = nodeConfig.InstanceType
+	}
+	if nodeConfig.DiskSize != 0 {
+		serverConfig.DiskSize = nodeConfig.DiskSize
+	}
+	if nodeConfig.DiskType != "" {
+		serverConfig.DiskType = nodeConfig.DiskType
+		serverConfig.DiskIOPS = nodeConfig.DiskIOPS
+	}
+	serverConfig.OSImage = nodeConfig.AMI
+	return serverConfig
+}
+
+// serverConfigFlagsChanged checks if any of the cloud server configuration flags of [cmd] is set
+func serverConfigFlagsChanged(cmd *cobra.Command) bool {
+	for _, flag := range []string{"arm64", "node-type", "disk-size", "disk-type", "disk-iops", "os-image"} {
+		if cmd.Flags().Changed(flag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,336 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/terraform"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+// sharedResourceTypes are the terraform resources that can be used by the nodes of several
+// clusters: key pairs, security groups, networks and their firewall rules
+var sharedResourceTypes = []string{
+	"tls_private_key",
+	"local_file",
+	"aws_key_pair",
+	"aws_security_group",
+	"aws_security_group_rule",
+	"google_compute_network",
+	"google_compute_firewall",
+}
+
+func newDestroyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "destroy [clusterName]",
+		Short: "(ALPHA Warning) Destroy all cloud resources of a cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node destroy command deletes all cloud resources created for a cluster,
+as recorded in the terraform state kept for it: instances, elastic IPs or
+static IPs, security groups, firewall rules and key pairs. The terraform plan
+of what will be removed is shown before it is applied.
+
+Instances in the cluster state that are not part of any cluster, like the ones
+left by a failed node create or stopped with node stop, are reported as
+orphaned and destroyed too. Security groups, networks and key pairs still used
+by other clusters are kept.
+
+The terraform state of a cluster is kept at ~/.avalanche-cli/nodes/terraform/<clusterName>,
+outside of the cluster dir, because node stop removes the cluster dir, and the
+instances of stopped clusters must still be destroyable.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         destroyCluster,
+	}
+	cmd.Flags().BoolVar(&authorizeRemove, "authorize-remove", false, "authorize CLI to destroy the planned cloud resources and remove all local files related to the cluster")
+	return cmd
+}
+
+// getClusterTerraformDirs returns the terraform directories of [clusterName], newest first, so
+// nodes added later are destroyed before the resources they may share with the first ones
+func getClusterTerraformDirs(clusterName string) ([]string, error) {
+	clusterTerraformDir := app.GetClusterTerraformDir(clusterName)
+	entries, err := os.ReadDir(clusterTerraformDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	terraformDirs := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			terraformDirs = append(terraformDirs, filepath.Join(clusterTerraformDir, entry.Name()))
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(terraformDirs)))
+	return terraformDirs, nil
+}
+
+// getStateInstance returns the node ID and cloud service of [resource] if it is a cloud instance
+func getStateInstance(resource terraform.StateResource) (string, string, bool) {
+	switch resource.Type {
+	case "aws_instance":
+		return resource.ID(), constants.AWSCloudService, true
+	case "google_compute_instance":
+		return resource.StringValue("name"), constants.GCPCloudService, true
+	}
+	return "", "", false
+}
+
+// getStateInstanceSecurityGroups returns the AWS security groups or the GCP network of [resource]
+func getStateInstanceSecurityGroups(resource terraform.StateResource) []string {
+	securityGroups := []string{}
+	switch resource.Type {
+	case "aws_instance":
+		values, _ := resource.Values["security_groups"].([]interface{})
+		for _, value := range values {
+			if securityGroup, ok := value.(string); ok {
+				securityGroups = append(securityGroups, securityGroup)
+			}
+		}
+	case "google_compute_instance":
+		networkInterfaces, _ := resource.Values["network_interface"].([]interface{})
+		for _, networkInterface := range networkInterfaces {
+			values, _ := networkInterface.(map[string]interface{})
+			if network, ok := values["network"].(string); ok {
+				securityGroups = append(securityGroups, path.Base(network))
+			}
+		}
+	}
+	return securityGroups
+}
+
+func loadClustersConfigIfExists() (models.ClustersConfig, error) {
+	if !app.ClustersConfigExists() {
+		return models.ClustersConfig{}, nil
+	}
+	return app.LoadClustersConfig()
+}
+
+// getOtherClustersSecurityGroups returns the security groups, or GCP networks, used by nodes
+// of clusters other than [clusterName]
+func getOtherClustersSecurityGroups(clustersConfig models.ClustersConfig, clusterName string) map[string]bool {
+	securityGroups := map[string]bool{}
+	for otherClusterName, clusterConfig := range clustersConfig.Clusters {
+		if otherClusterName == clusterName {
+			continue
+		}
+		for _, node := range clusterConfig.Nodes {
+			nodeConfig, err := app.LoadClusterNodeConfig(node)
+			if err != nil || nodeConfig.SecurityGroup == "" {
+				continue
+			}
+			securityGroups[nodeConfig.SecurityGroup] = true
+		}
+	}
+	return securityGroups
+}
+
+// isNodeInAnyCluster checks if [node] is part of any cluster of [clustersConfig]
+func isNodeInAnyCluster(clustersConfig models.ClustersConfig, node string) bool {
+	for _, clusterConfig := range clustersConfig.Clusters {
+		if slices.Contains(clusterConfig.Nodes, node) {
+			return true
+		}
+	}
+	return false
+}
+
+// forgetNodeTerraformState removes from the terraform state of [clusterName] the instance [node],
+// and its static IP, after they were deleted outside of terraform
+func forgetNodeTerraformState(clusterName string, nodeConfig models.NodeConfig) error {
+	terraformDirs, err := getClusterTerraformDirs(clusterName)
+	if err != nil {
+		return err
+	}
+	for _, terraformDir := range terraformDirs {
+		resources, err := terraform.GetStateResources(terraformDir)
+		if err != nil {
+			return err
+		}
+		addresses := []string{}
+		for _, resource := range resources {
+			instanceID, _, isInstance := getStateInstance(resource)
+			switch {
+			case isInstance && instanceID == nodeConfig.NodeID:
+				addresses = append(addresses, resource.Address)
+			case resource.Type == "aws_eip" && resource.StringValue("instance") == nodeConfig.NodeID:
+				addresses = append(addresses, resource.Address)
+			case resource.Type == "google_compute_address" && nodeConfig.ElasticIP != "" && resource.StringValue("address") == nodeConfig.ElasticIP:
+				addresses = append(addresses, resource.Address)
+			}
+		}
+		if err := terraform.RemoveFromState(terraformDir, addresses); err != nil {
+			return fmt.Errorf("failed to remove node %s from terraform state at %s: %w", nodeConfig.NodeID, terraformDir, err)
+		}
+	}
+	return nil
+}
+
+func destroyCluster(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	terraformDirs, err := getClusterTerraformDirs(clusterName)
+	if err != nil {
+		return err
+	}
+	clusterExists := true
+	clusterNodes, err := getClusterNodes(clusterName)
+	monitoringInstance, dedicated, monitoringErr := getClusterMonitoringInstance(clusterName)
+	if monitoringErr != nil {
+		return monitoringErr
+	}
+	if err != nil {
+		// clusters stopped with node stop are not in the clusters config anymore, but their
+		// instances can still be destroyed
+		if len(terraformDirs) == 0 {
+			return err
+		}
+		clusterExists = false
+	}
+	if len(terraformDirs) == 0 {
+		return fmt.Errorf("no terraform state found for cluster %s, use avalanche node stop %s instead", clusterName, clusterName)
+	}
+	if err := terraform.CheckIsInstalled(); err != nil {
+		return err
+	}
+	clustersConfig, err := loadClustersConfigIfExists()
+	if err != nil {
+		return err
+	}
+	otherClustersSecurityGroups := getOtherClustersSecurityGroups(clustersConfig, clusterName)
+	managedNodes := map[string]string{}
+	orphanedInstances := []string{}
+	terraformDirsToDestroy := []string{}
+	for _, terraformDir := range terraformDirs {
+		resources, err := terraform.GetStateResources(terraformDir)
+		if err != nil {
+			return err
+		}
+		sharedResourcesInUse := false
+		for _, resource := range resources {
+			instanceID, cloudService, isInstance := getStateInstance(resource)
+			if !isInstance {
+				continue
+			}
+			switch {
+			case slices.Contains(clusterNodes, instanceID):
+				managedNodes[instanceID] = cloudService
+			case dedicated && instanceID == monitoringInstance:
+				managedNodes[instanceID] = cloudService
+			case !isNodeInAnyCluster(clustersConfig, instanceID):
+				orphanedInstances = append(orphanedInstances, instanceID)
+			}
+			for _, securityGroup := range getStateInstanceSecurityGroups(resource) {
+				if otherClustersSecurityGroups[securityGroup] {
+					sharedResourcesInUse = true
+				}
+			}
+		}
+		// all resources are destroyed, unless some are shared with other clusters. In that case
+		// only the other ones are targeted, so the state is left untouched until the destroy
+		// is confirmed
+		destroyTargets := []string{}
+		if sharedResourcesInUse {
+			keptResources := []string{}
+			for _, resource := range resources {
+				if slices.Contains(sharedResourceTypes, resource.Type) {
+					keptResources = append(keptResources, resource.Address)
+				} else {
+					destroyTargets = append(destroyTargets, resource.Address)
+				}
+			}
+			if len(keptResources) > 0 {
+				ux.Logger.PrintToUser("Keeping %s, used by other clusters", keptResources)
+			}
+			if len(destroyTargets) == 0 {
+				continue
+			}
+		}
+		ux.Logger.PrintToUser("Planning destroy of cloud resources at %s ...", terraformDir)
+		hasChanges, err := terraform.PlanDestroy(terraformDir, destroyTargets)
+		if err != nil {
+			return fmt.Errorf("failed to plan destroy of %s: %w", terraformDir, err)
+		}
+		if hasChanges {
+			terraformDirsToDestroy = append(terraformDirsToDestroy, terraformDir)
+		}
+	}
+	unmanagedNodes := []string{}
+	for _, node := range clusterNodes {
+		if _, ok := managedNodes[node]; !ok {
+			unmanagedNodes = append(unmanagedNodes, node)
+		}
+	}
+	if len(orphanedInstances) > 0 {
+		ux.Logger.PrintToUser("Found orphaned instance(s) %s, not part of any cluster. They will be destroyed", orphanedInstances)
+	}
+	if len(unmanagedNodes) > 0 {
+		ux.Logger.PrintToUser("Node(s) %s of cluster %s are not in its terraform state and will be kept. Use avalanche node remove to delete them", unmanagedNodes, clusterName)
+	}
+	if len(terraformDirsToDestroy) == 0 {
+		ux.Logger.PrintToUser("No cloud resources to destroy for cluster %s", clusterName)
+	}
+	if !authorizeRemove {
+		ux.Logger.PrintToUser("Please note that if your node(s) are validating a Subnet, destroying them could cause Subnet instability and it is irreversible")
+		yes, err := app.Prompt.CaptureYesNo(fmt.Sprintf("Do you want to destroy the cloud resources above, and remove all stored files of cluster %s?", clusterName))
+		if err != nil {
+			return err
+		}
+		if !yes {
+			return errors.New("abort avalanche node destroy command")
+		}
+	}
+	for _, terraformDir := range terraformDirsToDestroy {
+		if err := terraform.ApplyDestroy(terraformDir); err != nil {
+			return fmt.Errorf("failed to destroy cloud resources at %s: %w", terraformDir, err)
+		}
+	}
+	if err := terraform.RemoveDirectory(app.GetClusterTerraformDir(clusterName)); err != nil {
+		return err
+	}
+	if clusterExists {
+		if len(unmanagedNodes) == 0 {
+			if err := removeClustersConfigFiles(clusterName); err != nil {
+				return err
+			}
+		} else {
+			if _, ok := managedNodes[monitoringInstance]; ok {
+				if err := removeClusterMonitoring(clusterName); err != nil {
+					return err
+				}
+			}
+			for node, cloudService := range managedNodes {
+				ansibleHostID, err := models.HostCloudIDToAnsibleID(cloudService, node)
+				if err != nil {
+					return err
+				}
+				if err := ansible.RemoveHostFromAnsibleInventory(app.GetAnsibleInventoryDirPath(clusterName), ansibleHostID); err != nil {
+					return err
+				}
+				if err := removeNodeFromCluster(clusterName, node); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for node := range managedNodes {
+		if err := removeDeletedNodeDirectory(node); err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser("Cloud resources of cluster %s successfully destroyed!", clusterName)
+	return nil
+}
@@ -25,5 +25,7 @@ You can check the updated status by calling avalanche node status <clusterName>`
 	}
 	// node devnet deploy
 	cmd.AddCommand(newDeployCmd())
+	// node devnet reset
+	cmd.AddCommand(newResetCmd())
 	return cmd
 }
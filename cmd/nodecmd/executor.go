@@ -0,0 +1,104 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+)
+
+// hostExecutor runs the node operations on cloud hosts, identified by their ansible host IDs
+// in the cluster inventory. Operations on multiple hosts return the results keyed by host ID
+type hostExecutor interface {
+	SetupNode(hostIDs []string, avalancheGoVersion string, isDevnet bool) error
+	CopyStakingFiles(hostIDs []string, nodesDir string) error
+	SetupDevnet(hostIDs []string, nodesDir string) error
+	SetupBuildEnv(hostIDs []string) error
+	SetupCLIFromSource(hostIDs []string, cliBranch string) error
+	ExportSubnet(hostIDs []string, subnetPath string) error
+	TrackSubnet(hostID string, network models.Network, subnetName string, importPath string) error
+	UpdateSubnet(hostID string, network models.Network, subnetName string, importPath string) error
+	GetVMVersions(hostIDs []string) (map[string]map[string]string, error)
+	GetNodeIDs(hostIDs []string) (map[string]string, error)
+	IsBootstrapped(hostIDs []string) (map[string]bool, error)
+	IsHealthy(hostIDs []string) (map[string]bool, error)
+	GetSubnetSyncStatus(hostIDs []string, blockchainID string) (map[string]string, error)
+	UpgradeAvalancheGo(hostID string, avalancheGoVersion string) error
+	StartNode(hostID string) error
+	StopNode(hostID string) error
+	GetNewSubnetEVMRelease(hostID string, subnetEVMReleaseURL string, subnetEVMArchive string) error
+	UpgradeSubnetEVM(hostID string, subnetEVMBinaryPath string) error
+	GetNodeConfig(hostIDs []string) (map[string][]byte, error)
+	SetNodeConfig(hostID string, nodeConfigPath string) error
+}
+
+var (
+	// nodeExecutor is set by setupNodeExecutor before running operations on a cluster
+	nodeExecutor hostExecutor
+	useAnsible   bool
+)
+
+// setupNodeExecutor prepares the executor for the hosts of [clusterName]. Operations are run
+// natively over ssh, unless --use-ansible is set, or the cluster runs on the local machine
+func setupNodeExecutor(clusterName string) error {
+	if err := updateAnsiblePublicIPs(clusterName); err != nil {
+		return err
+	}
+	isLocal, err := isLocalMachineCluster(clusterName)
+	if err != nil {
+		return err
+	}
+	if isLocal {
+		if useAnsible {
+			return errors.New("ansible can't be used with node/s running on the local machine")
+		}
+		clustersConfig, err := app.LoadClustersConfig()
+		if err != nil {
+			return err
+		}
+		nodeExecutor = &localExecutor{network: clustersConfig.Clusters[clusterName].Network}
+		return nil
+	}
+	if useAnsible {
+		// we need to remove existing ansible directory and its contents in .avalanche-cli dir
+		// before calling every ansible run command just in case there is a change in playbook
+		if err := ansible.CheckIsInstalled(); err != nil {
+			return err
+		}
+		if err := app.SetupAnsibleEnv(); err != nil {
+			return err
+		}
+		if err := ansible.Setup(app.GetAnsibleDir()); err != nil {
+			return err
+		}
+		nodeExecutor = &ansibleExecutor{clusterName: clusterName}
+		return nil
+	}
+	hosts, err := ansible.GetHostMapfromAnsibleInventory(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	nodeExecutor = &sshExecutor{hosts: hosts}
+	return nil
+}
+
+// isLocalMachineCluster checks if the nodes of [clusterName] were created with --local-machine
+func isLocalMachineCluster(clusterName string) (bool, error) {
+	clusterNodes, err := getClusterNodes(clusterName)
+	if err != nil {
+		return false, err
+	}
+	for _, node := range clusterNodes {
+		nodeConfig, err := app.LoadClusterNodeConfig(node)
+		if err != nil {
+			return false, err
+		}
+		if nodeConfig.CloudService != constants.LocalMachineService {
+			return false, nil
+		}
+	}
+	return len(clusterNodes) > 0, nil
+}
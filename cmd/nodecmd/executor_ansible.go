@@ -0,0 +1,313 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"go.uber.org/zap"
+)
+
+// ansibleExecutor runs the node operations with the ansible playbooks, targeting the
+// hosts of the cluster inventory
+type ansibleExecutor struct {
+	clusterName string
+}
+
+func (e *ansibleExecutor) inventoryPath() string {
+	return app.GetAnsibleInventoryDirPath(e.clusterName)
+}
+
+func (e *ansibleExecutor) SetupNode(hostIDs []string, avalancheGoVersion string, isDevnet bool) error {
+	return ansible.RunAnsiblePlaybookSetupNode(
+		app.Conf.GetConfigPath(),
+		app.GetAnsibleDir(),
+		e.inventoryPath(),
+		avalancheGoVersion,
+		fmt.Sprint(isDevnet),
+		strings.Join(hostIDs, ","),
+	)
+}
+
+func (e *ansibleExecutor) CopyStakingFiles(hostIDs []string, nodesDir string) error {
+	return ansible.RunAnsiblePlaybookCopyStakingFiles(app.GetAnsibleDir(), strings.Join(hostIDs, ","), nodesDir, e.inventoryPath())
+}
+
+func (e *ansibleExecutor) SetupDevnet(hostIDs []string, nodesDir string) error {
+	return ansible.RunAnsiblePlaybookSetupDevnet(app.GetAnsibleDir(), strings.Join(hostIDs, ","), nodesDir, e.inventoryPath())
+}
+
+func (e *ansibleExecutor) SetupBuildEnv(hostIDs []string) error {
+	return ansible.RunAnsiblePlaybookSetupBuildEnv(app.GetAnsibleDir(), e.inventoryPath(), strings.Join(hostIDs, ","))
+}
+
+func (e *ansibleExecutor) SetupCLIFromSource(hostIDs []string, cliBranch string) error {
+	return ansible.RunAnsiblePlaybookSetupCLIFromSource(app.GetAnsibleDir(), e.inventoryPath(), cliBranch, strings.Join(hostIDs, ","))
+}
+
+func (e *ansibleExecutor) ExportSubnet(hostIDs []string, subnetPath string) error {
+	return ansible.RunAnsiblePlaybookExportSubnet(app.GetAnsibleDir(), e.inventoryPath(), subnetPath, strings.Join(hostIDs, ","))
+}
+
+func (e *ansibleExecutor) TrackSubnet(hostID string, network models.Network, subnetName string, importPath string) error {
+	return ansible.RunAnsiblePlaybookTrackSubnet(app.GetAnsibleDir(), network, subnetName, importPath, e.inventoryPath(), hostID)
+}
+
+func (e *ansibleExecutor) UpdateSubnet(hostID string, network models.Network, subnetName string, importPath string) error {
+	return ansible.RunAnsiblePlaybookUpdateSubnet(app.GetAnsibleDir(), network, subnetName, importPath, e.inventoryPath(), hostID)
+}
+
+func (e *ansibleExecutor) GetVMVersions(hostIDs []string) (map[string]map[string]string, error) {
+	if err := app.CreateAnsibleStatusDir(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = app.RemoveAnsibleStatusDir()
+	}()
+	// the playbook fails if any host fails, but the output of the other hosts is still usable
+	playbookErr := ansible.RunAnsiblePlaybookCheckAvalancheGoVersion(app.GetAnsibleDir(), app.GetAvalancheGoJSONFile(), e.inventoryPath(), strings.Join(hostIDs, ","))
+	vmVersions := map[string]map[string]string{}
+	failedHosts := 0
+	for _, hostID := range hostIDs {
+		// hosts whose versions can't be obtained don't fail the whole cluster
+		hostVMVersions, err := parseNodeVersionOutput(app.GetAvalancheGoJSONFile() + "." + hostID)
+		if err != nil {
+			app.Log.Warn("failed to get node versions", zap.String("host", hostID), zap.Error(err))
+			hostVMVersions = map[string]string{constants.PlatformKeyName: constants.AvalancheGoVersionUnknown}
+			failedHosts++
+		}
+		vmVersions[hostID] = hostVMVersions
+	}
+	if playbookErr != nil && failedHosts == len(hostIDs) {
+		return nil, playbookErr
+	}
+	return vmVersions, nil
+}
+
+func (e *ansibleExecutor) GetNodeIDs(hostIDs []string) (map[string]string, error) {
+	if err := app.CreateAnsibleStatusDir(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = app.RemoveAnsibleStatusDir()
+	}()
+	if err := ansible.RunAnsiblePlaybookGetNodeID(app.GetAnsibleDir(), app.GetNodeIDJSONFile(), e.inventoryPath(), strings.Join(hostIDs, ",")); err != nil {
+		return nil, err
+	}
+	nodeIDs := map[string]string{}
+	for _, hostID := range hostIDs {
+		nodeID, err := parseNodeIDOutput(app.GetNodeIDJSONFile() + "." + hostID)
+		if err != nil {
+			return nil, err
+		}
+		nodeIDs[hostID] = nodeID
+	}
+	return nodeIDs, nil
+}
+
+func (e *ansibleExecutor) IsBootstrapped(hostIDs []string) (map[string]bool, error) {
+	if err := app.CreateAnsibleStatusDir(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = app.RemoveAnsibleStatusDir()
+	}()
+	if err := ansible.RunAnsiblePlaybookCheckBootstrapped(app.GetAnsibleDir(), app.GetBootstrappedJSONFile(), e.inventoryPath(), strings.Join(hostIDs, ",")); err != nil {
+		return nil, err
+	}
+	isBootstrapped := map[string]bool{}
+	for _, hostID := range hostIDs {
+		hostIsBootstrapped, err := parseBootstrappedOutput(app.GetBootstrappedJSONFile() + "." + hostID)
+		if err != nil {
+			return nil, err
+		}
+		isBootstrapped[hostID] = hostIsBootstrapped
+	}
+	return isBootstrapped, nil
+}
+
+func (e *ansibleExecutor) IsHealthy(hostIDs []string) (map[string]bool, error) {
+	if err := app.CreateAnsibleStatusDir(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = app.RemoveAnsibleStatusDir()
+	}()
+	if err := ansible.RunAnsiblePlaybookCheckHealthy(app.GetAnsibleDir(), app.GetHealthyJSONFile(), e.inventoryPath(), strings.Join(hostIDs, ",")); err != nil {
+		return nil, err
+	}
+	isHealthy := map[string]bool{}
+	for _, hostID := range hostIDs {
+		hostIsHealthy, err := parseHealthyOutput(app.GetHealthyJSONFile() + "." + hostID)
+		if err != nil {
+			return nil, err
+		}
+		isHealthy[hostID] = hostIsHealthy
+	}
+	return isHealthy, nil
+}
+
+func (e *ansibleExecutor) GetSubnetSyncStatus(hostIDs []string, blockchainID string) (map[string]string, error) {
+	if err := app.CreateAnsibleStatusDir(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = app.RemoveAnsibleStatusDir()
+	}()
+	if err := ansible.RunAnsiblePlaybookSubnetSyncStatus(app.GetAnsibleDir(), app.GetSubnetSyncJSONFile(), blockchainID, e.inventoryPath(), strings.Join(hostIDs, ",")); err != nil {
+		return nil, err
+	}
+	syncStatus := map[string]string{}
+	for _, hostID := range hostIDs {
+		hostSyncStatus, err := parseSubnetSyncOutput(app.GetSubnetSyncJSONFile() + "." + hostID)
+		if err != nil {
+			return nil, err
+		}
+		syncStatus[hostID] = hostSyncStatus
+	}
+	return syncStatus, nil
+}
+
+func (e *ansibleExecutor) UpgradeAvalancheGo(hostID string, avalancheGoVersion string) error {
+	return ansible.RunAnsiblePlaybookUpgradeAvalancheGo(app.GetAnsibleDir(), e.inventoryPath(), hostID, avalancheGoVersion)
+}
+
+func (e *ansibleExecutor) StartNode(hostID string) error {
+	return ansible.RunAnsiblePlaybookStartNode(app.GetAnsibleDir(), e.inventoryPath(), hostID)
+}
+
+func (e *ansibleExecutor) StopNode(hostID string) error {
+	return ansible.RunAnsiblePlaybookStopNode(app.GetAnsibleDir(), e.inventoryPath(), hostID)
+}
+
+func (e *ansibleExecutor) GetNewSubnetEVMRelease(hostID string, subnetEVMReleaseURL string, subnetEVMArchive string) error {
+	return ansible.RunAnsiblePlaybookGetNewSubnetEVM(app.GetAnsibleDir(), subnetEVMReleaseURL, subnetEVMArchive, e.inventoryPath(), hostID)
+}
+
+func (e *ansibleExecutor) UpgradeSubnetEVM(hostID string, subnetEVMBinaryPath string) error {
+	return ansible.RunAnsiblePlaybookUpgradeSubnetEVM(app.GetAnsibleDir(), subnetEVMBinaryPath, e.inventoryPath(), hostID)
+}
+
+func (e *ansibleExecutor) GetNodeConfig(hostIDs []string) (map[string][]byte, error) {
+	if err := app.CreateAnsibleStatusDir(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = app.RemoveAnsibleStatusDir()
+	}()
+	if err := ansible.RunAnsiblePlaybookGetNodeConfig(app.GetAnsibleDir(), app.GetNodeConfigJSONFile(), e.inventoryPath(), strings.Join(hostIDs, ",")); err != nil {
+		return nil, err
+	}
+	nodeConfigs := map[string][]byte{}
+	for _, hostID := range hostIDs {
+		nodeConfig, err := os.ReadFile(app.GetNodeConfigJSONFile() + "." + hostID)
+		if errors.Is(err, os.ErrNotExist) {
+			nodeConfig = []byte("{}")
+		} else if err != nil {
+			return nil, err
+		}
+		nodeConfigs[hostID] = nodeConfig
+	}
+	return nodeConfigs, nil
+}
+
+func (e *ansibleExecutor) SetNodeConfig(hostID string, nodeConfigPath string) error {
+	return ansible.RunAnsiblePlaybookSetNodeConfig(app.GetAnsibleDir(), nodeConfigPath, e.inventoryPath(), hostID)
+}
+
+// readAnsibleStatusResult reads the "result" field of the node API response saved by a
+// playbook into [filePath]
+func readAnsibleStatusResult(filePath string) (map[string]interface{}, error) {
+	jsonFile, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer jsonFile.Close()
+	byteValue, err := io.ReadAll(jsonFile)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(byteValue, &result); err != nil {
+		return nil, err
+	}
+	resultInterface, ok := result["result"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unable to parse node API response at %s", filePath)
+	}
+	return resultInterface, nil
+}
+
+func parseHealthyOutput(filePath string) (bool, error) {
+	result, err := readAnsibleStatusResult(filePath)
+	if err != nil {
+		return false, err
+	}
+	isHealthy, ok := result["healthy"].(bool)
+	if !ok {
+		return false, errors.New("unable to parse node healthy status")
+	}
+	return isHealthy, nil
+}
+
+func parseBootstrappedOutput(filePath string) (bool, error) {
+	result, err := readAnsibleStatusResult(filePath)
+	if err != nil {
+		return false, err
+	}
+	isBootstrapped, ok := result["isBootstrapped"].(bool)
+	if !ok {
+		return false, errors.New("unable to parse node bootstrap status")
+	}
+	return isBootstrapped, nil
+}
+
+func parseNodeIDOutput(filePath string) (string, error) {
+	result, err := readAnsibleStatusResult(filePath)
+	if err != nil {
+		return "", err
+	}
+	nodeID, ok := result["nodeID"].(string)
+	if !ok {
+		return "", errors.New("unable to parse node ID")
+	}
+	return nodeID, nil
+}
+
+func parseSubnetSyncOutput(filePath string) (string, error) {
+	result, err := readAnsibleStatusResult(filePath)
+	if err != nil {
+		return "", err
+	}
+	status, ok := result["status"].(string)
+	if !ok {
+		return "", errors.New("unable to parse subnet sync status")
+	}
+	return status, nil
+}
+
+func parseNodeVersionOutput(filePath string) (map[string]string, error) {
+	result, err := readAnsibleStatusResult(filePath)
+	if err != nil {
+		return nil, err
+	}
+	vmVersionsInterface, ok := result["vmVersions"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("unable to parse node vm versions")
+	}
+	vmVersions := map[string]string{}
+	for vmName, vmVersion := range vmVersionsInterface {
+		if vmVersionStr, ok := vmVersion.(string); ok {
+			vmVersions[vmName] = vmVersionStr
+		}
+	}
+	return vmVersions, nil
+}
@@ -0,0 +1,576 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/plugins"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/api/info"
+	"github.com/ava-labs/avalanchego/config"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	psnet "github.com/shirou/gopsutil/net"
+	"github.com/shirou/gopsutil/process"
+)
+
+const (
+	localNodeAvalancheGoBin  = "avalanchego"
+	localNodeRunFileName     = "avalanchego_run.json"
+	localNodeLogFileName     = "avalanchego.log"
+	localNodeDataDir         = "data"
+	localNodeSubnetEVMDir    = "subnet-evm-release"
+	localNodeStopTimeout     = 30 * time.Second
+	localNodeStopCheckPeriod = 500 * time.Millisecond
+	localNodeStartTimeout    = 10 * time.Second
+	localNodeStartRetries    = 3
+)
+
+var errLocalNodePortInUse = errors.New("port already in use")
+
+// localNodeRunInfo is saved at the node dir, to find the avalanchego binary and process
+// of a node running on the local machine
+type localNodeRunInfo struct {
+	AvalancheGoPath string `json:"avalancheGoPath"`
+	Pid             int    `json:"pid"`
+}
+
+// localExecutor runs the node operations on avalanchego processes of the local machine.
+// Each node uses its instance dir as avalanchego home, and the ports set at its node config
+type localExecutor struct {
+	network models.Network
+}
+
+func (e *localExecutor) nodeDir(hostID string) (string, error) {
+	_, cloudHostID, err := models.HostAnsibleIDToCloudID(hostID)
+	if err != nil {
+		return "", err
+	}
+	return app.GetNodeInstanceDirPath(cloudHostID), nil
+}
+
+// runOnLocalHosts runs [op] on all hosts in [hostIDs], collecting the results and the errors
+// by host ID as the ssh executor does
+func runOnLocalHosts[T any](hostIDs []string, op func(hostID string) (T, error)) (map[string]T, error) {
+	results := map[string]T{}
+	hostErrs := ssh.HostErrors{}
+	for _, hostID := range hostIDs {
+		result, err := op(hostID)
+		if err != nil {
+			hostErrs[hostID] = err
+			continue
+		}
+		results[hostID] = result
+	}
+	if len(hostErrs) > 0 {
+		return results, hostErrs
+	}
+	return results, nil
+}
+
+func (e *localExecutor) runOnHosts(hostIDs []string, op func(hostID string) error) error {
+	_, err := runOnLocalHosts(hostIDs, func(hostID string) (struct{}, error) {
+		return struct{}{}, op(hostID)
+	})
+	return err
+}
+
+func (e *localExecutor) loadRunInfo(hostID string) (localNodeRunInfo, error) {
+	runInfo := localNodeRunInfo{}
+	nodeDir, err := e.nodeDir(hostID)
+	if err != nil {
+		return runInfo, err
+	}
+	runInfoBytes, err := os.ReadFile(filepath.Join(nodeDir, localNodeRunFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return runInfo, fmt.Errorf("avalanchego is not installed for node %s: %w", hostID, err)
+		}
+		return runInfo, err
+	}
+	err = json.Unmarshal(runInfoBytes, &runInfo)
+	return runInfo, err
+}
+
+func (e *localExecutor) saveRunInfo(hostID string, runInfo localNodeRunInfo) error {
+	nodeDir, err := e.nodeDir(hostID)
+	if err != nil {
+		return err
+	}
+	runInfoBytes, err := json.MarshalIndent(runInfo, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(nodeDir, localNodeRunFileName), runInfoBytes, constants.WriteReadReadPerms)
+}
+
+// installAvalancheGo downloads [avalancheGoVersion] into the CLI bin dir if needed, and
+// sets it as the binary of the node
+func (e *localExecutor) installAvalancheGo(hostID string, avalancheGoVersion string) error {
+	avalancheGoDir, err := binutils.SetupAvalanchego(app, avalancheGoVersion)
+	if err != nil {
+		return fmt.Errorf("failed to install avalanchego %s: %w", avalancheGoVersion, err)
+	}
+	runInfo, err := e.loadRunInfo(hostID)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	runInfo.AvalancheGoPath = filepath.Join(avalancheGoDir, localNodeAvalancheGoBin)
+	return e.saveRunInfo(hostID, runInfo)
+}
+
+func isLocalProcessRunning(pid int) bool {
+	if pid == 0 {
+		return false
+	}
+	exists, err := process.PidExists(int32(pid))
+	return err == nil && exists
+}
+
+// startNode starts avalanchego for [hostID]. The ports of the node are only known to be free
+// when assigned, so if avalanchego finds them taken on start, new ones are assigned and the
+// start is retried
+func (e *localExecutor) startNode(hostID string) error {
+	_, cloudHostID, err := models.HostAnsibleIDToCloudID(hostID)
+	if err != nil {
+		return err
+	}
+	for retry := 0; ; retry++ {
+		err := e.startNodeProcess(hostID)
+		if !errors.Is(err, errLocalNodePortInUse) || retry == localNodeStartRetries {
+			return err
+		}
+		if err := reassignLocalNodePorts(cloudHostID); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *localExecutor) startNodeProcess(hostID string) error {
+	runInfo, err := e.loadRunInfo(hostID)
+	if err != nil {
+		return err
+	}
+	if isLocalProcessRunning(runInfo.Pid) {
+		return nil
+	}
+	nodeDir, err := e.nodeDir(hostID)
+	if err != nil {
+		return err
+	}
+	_, cloudHostID, err := models.HostAnsibleIDToCloudID(hostID)
+	if err != nil {
+		return err
+	}
+	httpPort, stakingPort, err := getNodePorts(cloudHostID)
+	if err != nil {
+		return err
+	}
+	args := []string{
+		"--" + config.DataDirKey, filepath.Join(nodeDir, localNodeDataDir),
+		"--" + config.PluginDirKey, filepath.Join(nodeDir, constants.PluginDir),
+		"--" + config.HTTPPortKey, fmt.Sprint(httpPort),
+		"--" + config.StakingPortKey, fmt.Sprint(stakingPort),
+		"--" + config.StakingCertPathKey, filepath.Join(nodeDir, constants.StakerCertFileName),
+		"--" + config.StakingTLSKeyPathKey, filepath.Join(nodeDir, constants.StakerKeyFileName),
+		"--" + config.StakingSignerKeyPathKey, filepath.Join(nodeDir, constants.BLSKeyFileName),
+	}
+	if nodeConfFile := filepath.Join(nodeDir, constants.NodeFileName); utils.FileExists(nodeConfFile) {
+		args = append(args, "--"+config.ConfigFileKey, nodeConfFile)
+	}
+	if genesisFile := filepath.Join(nodeDir, constants.GenesisFileName); utils.FileExists(genesisFile) {
+		args = append(args, "--"+config.GenesisFileKey, genesisFile)
+	} else if e.network.Kind != models.Devnet {
+		args = append(args, "--"+config.NetworkNameKey, e.network.NetworkIDFlagValue())
+	}
+	logFile, err := os.OpenFile(filepath.Join(nodeDir, localNodeLogFileName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, constants.WriteReadReadPerms)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	cmd := exec.Command(runInfo.AvalancheGoPath, args...) //nolint: gosec
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	// run on its own process group, so the node is not interrupted together with the CLI
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	logInfo, err := logFile.Stat()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start avalanchego for node %s: %w", hostID, err)
+	}
+	runInfo.Pid = cmd.Process.Pid
+	// reap the process if it exits while the CLI is still running
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	if err := waitForLocalNodeStart(exited, runInfo.Pid, httpPort, stakingPort); err != nil {
+		logBytes, _ := os.ReadFile(logFile.Name())
+		if int64(len(logBytes)) > logInfo.Size() && strings.Contains(string(logBytes[logInfo.Size():]), "address already in use") {
+			return errLocalNodePortInUse
+		}
+		return fmt.Errorf("%w for node %s, see its log at %s", err, hostID, logFile.Name())
+	}
+	return e.saveRunInfo(hostID, runInfo)
+}
+
+// waitForLocalNodeStart waits until the avalanchego process [pid] listens on its ports, failing
+// if it exits before. Nodes not listening after localNodeStartTimeout are left starting
+func waitForLocalNodeStart(exited <-chan struct{}, pid int, httpPort uint32, stakingPort uint32) error {
+	timeout := time.After(localNodeStartTimeout)
+	for {
+		select {
+		case <-exited:
+			return errors.New("avalanchego exited on start")
+		case <-timeout:
+			return nil
+		case <-time.After(localNodeStopCheckPeriod):
+			conns, err := psnet.ConnectionsPid("tcp", int32(pid))
+			if err != nil {
+				continue
+			}
+			listening := map[uint32]bool{}
+			for _, conn := range conns {
+				if conn.Status == "LISTEN" {
+					listening[conn.Laddr.Port] = true
+				}
+			}
+			if listening[httpPort] && listening[stakingPort] {
+				return nil
+			}
+		}
+	}
+}
+
+// reassignLocalNodePorts assigns new free ports to the local machine node [cloudHostID]
+func reassignLocalNodePorts(cloudHostID string) error {
+	nodeConfig, err := app.LoadClusterNodeConfig(cloudHostID)
+	if err != nil {
+		return err
+	}
+	usedPorts, err := getLocalNodesPorts()
+	if err != nil {
+		return err
+	}
+	if nodeConfig.HTTPPort, err = getFreeLocalPort(usedPorts); err != nil {
+		return err
+	}
+	if nodeConfig.StakingPort, err = getFreeLocalPort(usedPorts); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Ports of node %s in use, retrying with API port %d and staking port %d", cloudHostID, nodeConfig.HTTPPort, nodeConfig.StakingPort)
+	return app.CreateNodeCloudConfigFile(cloudHostID, &nodeConfig)
+}
+
+func (e *localExecutor) stopNode(hostID string) error {
+	runInfo, err := e.loadRunInfo(hostID)
+	if err != nil {
+		return err
+	}
+	if isLocalProcessRunning(runInfo.Pid) {
+		proc, err := os.FindProcess(runInfo.Pid)
+		if err != nil {
+			return fmt.Errorf("could not find process with pid %d: %w", runInfo.Pid, err)
+		}
+		if err := proc.Signal(os.Interrupt); err != nil {
+			return fmt.Errorf("failed stopping process with pid %d: %w", runInfo.Pid, err)
+		}
+		for start := time.Now(); isLocalProcessRunning(runInfo.Pid); time.Sleep(localNodeStopCheckPeriod) {
+			if time.Since(start) > localNodeStopTimeout {
+				return fmt.Errorf("timeout waiting for avalanchego of node %s to stop", hostID)
+			}
+		}
+	}
+	runInfo.Pid = 0
+	return e.saveRunInfo(hostID, runInfo)
+}
+
+func (e *localExecutor) restartNode(hostID string) error {
+	if err := e.stopNode(hostID); err != nil {
+		return err
+	}
+	return e.startNode(hostID)
+}
+
+func (e *localExecutor) nodeURI(hostID string) (string, error) {
+	_, cloudHostID, err := models.HostAnsibleIDToCloudID(hostID)
+	if err != nil {
+		return "", err
+	}
+	httpPort, _, err := getNodePorts(cloudHostID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("http://%s:%d", localNodeIP, httpPort), nil
+}
+
+func (e *localExecutor) SetupNode(hostIDs []string, avalancheGoVersion string, isDevnet bool) error {
+	return e.runOnHosts(hostIDs, func(hostID string) error {
+		if err := e.installAvalancheGo(hostID, avalancheGoVersion); err != nil {
+			return err
+		}
+		if isDevnet {
+			// devnet nodes are started after genesis is set up
+			return nil
+		}
+		return e.startNode(hostID)
+	})
+}
+
+// CopyStakingFiles is a no-op, as avalanchego is pointed to the staking files of the node dir
+func (e *localExecutor) CopyStakingFiles([]string, string) error {
+	return nil
+}
+
+// SetupDevnet restarts the nodes from a clean state, with the genesis and config files
+// already written at the node dir
+func (e *localExecutor) SetupDevnet(hostIDs []string, _ string) error {
+	return e.runOnHosts(hostIDs, func(hostID string) error {
+		if err := e.stopNode(hostID); err != nil {
+			return err
+		}
+		nodeDir, err := e.nodeDir(hostID)
+		if err != nil {
+			return err
+		}
+		if err := os.RemoveAll(filepath.Join(nodeDir, localNodeDataDir)); err != nil {
+			return err
+		}
+		return e.startNode(hostID)
+	})
+}
+
+// SetupBuildEnv is a no-op, as custom VMs are built by the CLI itself
+func (e *localExecutor) SetupBuildEnv([]string) error {
+	return nil
+}
+
+// SetupCLIFromSource is a no-op, as nodes are managed by the CLI being run
+func (e *localExecutor) SetupCLIFromSource([]string, string) error {
+	return nil
+}
+
+// ExportSubnet is a no-op, as nodes use the subnet configuration of the CLI being run
+func (e *localExecutor) ExportSubnet([]string, string) error {
+	return nil
+}
+
+// trackSubnet installs the subnet VM into the node plugin dir and adds the subnet to the
+// node config, the same way subnet join does on cloud nodes
+func (e *localExecutor) trackSubnet(hostID string, network models.Network, subnetName string) error {
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return err
+	}
+	subnetID := sc.Networks[network.Name()].SubnetID
+	if subnetID == ids.Empty {
+		return fmt.Errorf("subnet %s is not deployed to %s", subnetName, network.Name())
+	}
+	nodeDir, err := e.nodeDir(hostID)
+	if err != nil {
+		return err
+	}
+	if _, err := plugins.CreatePlugin(app, subnetName, filepath.Join(nodeDir, constants.PluginDir)); err != nil {
+		return err
+	}
+	subnetAvagoConfigFile := ""
+	if app.AvagoNodeConfigExists(subnetName) {
+		subnetAvagoConfigFile = app.GetAvagoNodeConfigPath(subnetName)
+	}
+	if err := plugins.EditConfigFile(
+		app,
+		subnetID.String(),
+		network,
+		filepath.Join(nodeDir, constants.NodeFileName),
+		true,
+		subnetAvagoConfigFile,
+	); err != nil {
+		return err
+	}
+	return e.restartNode(hostID)
+}
+
+func (e *localExecutor) TrackSubnet(hostID string, network models.Network, subnetName string, _ string) error {
+	return e.runOnHosts([]string{hostID}, func(hostID string) error {
+		return e.trackSubnet(hostID, network, subnetName)
+	})
+}
+
+func (e *localExecutor) UpdateSubnet(hostID string, network models.Network, subnetName string, _ string) error {
+	return e.runOnHosts([]string{hostID}, func(hostID string) error {
+		return e.trackSubnet(hostID, network, subnetName)
+	})
+}
+
+func (e *localExecutor) GetVMVersions(hostIDs []string) (map[string]map[string]string, error) {
+	return runOnLocalHosts(hostIDs, func(hostID string) (map[string]string, error) {
+		uri, err := e.nodeURI(hostID)
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		reply, err := info.NewClient(uri).GetNodeVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return reply.VMVersions, nil
+	})
+}
+
+func (e *localExecutor) GetNodeIDs(hostIDs []string) (map[string]string, error) {
+	return runOnLocalHosts(hostIDs, func(hostID string) (string, error) {
+		uri, err := e.nodeURI(hostID)
+		if err != nil {
+			return "", err
+		}
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		nodeID, _, err := info.NewClient(uri).GetNodeID(ctx)
+		if err != nil {
+			return "", err
+		}
+		return nodeID.String(), nil
+	})
+}
+
+func (e *localExecutor) IsBootstrapped(hostIDs []string) (map[string]bool, error) {
+	return runOnLocalHosts(hostIDs, func(hostID string) (bool, error) {
+		uri, err := e.nodeURI(hostID)
+		if err != nil {
+			return false, err
+		}
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		return info.NewClient(uri).IsBootstrapped(ctx, "X")
+	})
+}
+
+func (e *localExecutor) IsHealthy(hostIDs []string) (map[string]bool, error) {
+	return runOnLocalHosts(hostIDs, func(hostID string) (bool, error) {
+		uri, err := e.nodeURI(hostID)
+		if err != nil {
+			return false, err
+		}
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		reply, err := health.NewClient(uri).Health(ctx, nil)
+		if err != nil {
+			return false, err
+		}
+		return reply.Healthy, nil
+	})
+}
+
+func (e *localExecutor) GetSubnetSyncStatus(hostIDs []string, blockchainID string) (map[string]string, error) {
+	return runOnLocalHosts(hostIDs, func(hostID string) (string, error) {
+		uri, err := e.nodeURI(hostID)
+		if err != nil {
+			return "", err
+		}
+		ctx, cancel := utils.GetAPIContext()
+		defer cancel()
+		status, err := platformvm.NewClient(uri).GetBlockchainStatus(ctx, blockchainID)
+		if err != nil {
+			return "", err
+		}
+		return status.String(), nil
+	})
+}
+
+func (e *localExecutor) UpgradeAvalancheGo(hostID string, avalancheGoVersion string) error {
+	return e.runOnHosts([]string{hostID}, func(hostID string) error {
+		if err := e.stopNode(hostID); err != nil {
+			return err
+		}
+		if err := e.installAvalancheGo(hostID, avalancheGoVersion); err != nil {
+			return err
+		}
+		return e.startNode(hostID)
+	})
+}
+
+func (e *localExecutor) StartNode(hostID string) error {
+	return e.runOnHosts([]string{hostID}, e.startNode)
+}
+
+func (e *localExecutor) StopNode(hostID string) error {
+	return e.runOnHosts([]string{hostID}, e.stopNode)
+}
+
+// GetNewSubnetEVMRelease downloads the subnet-evm release archive and extracts it at the
+// node dir, for UpgradeSubnetEVM to pick it up
+func (e *localExecutor) GetNewSubnetEVMRelease(hostID string, subnetEVMReleaseURL string, _ string) error {
+	return e.runOnHosts([]string{hostID}, func(hostID string) error {
+		nodeDir, err := e.nodeDir(hostID)
+		if err != nil {
+			return err
+		}
+		archive, err := app.Downloader.Download(subnetEVMReleaseURL)
+		if err != nil {
+			return err
+		}
+		return binutils.InstallArchive("tar.gz", archive, filepath.Join(nodeDir, localNodeSubnetEVMDir))
+	})
+}
+
+// UpgradeSubnetEVM copies the downloaded subnet-evm release into the node plugin dir, using
+// the VM ID at the end of [subnetEVMBinaryPath]
+func (e *localExecutor) UpgradeSubnetEVM(hostID string, subnetEVMBinaryPath string) error {
+	return e.runOnHosts([]string{hostID}, func(hostID string) error {
+		nodeDir, err := e.nodeDir(hostID)
+		if err != nil {
+			return err
+		}
+		return binutils.CopyFile(
+			filepath.Join(nodeDir, localNodeSubnetEVMDir, constants.SubnetEVMBin),
+			filepath.Join(nodeDir, constants.PluginDir, filepath.Base(subnetEVMBinaryPath)),
+		)
+	})
+}
+
+func (e *localExecutor) GetNodeConfig(hostIDs []string) (map[string][]byte, error) {
+	nodeConfigs := map[string][]byte{}
+	for _, hostID := range hostIDs {
+		nodeDir, err := e.nodeDir(hostID)
+		if err != nil {
+			return nil, err
+		}
+		nodeConfig, err := os.ReadFile(filepath.Join(nodeDir, constants.NodeFileName))
+		if errors.Is(err, os.ErrNotExist) {
+			nodeConfig = []byte("{}")
+		} else if err != nil {
+			return nil, err
+		}
+		nodeConfigs[hostID] = nodeConfig
+	}
+	return nodeConfigs, nil
+}
+
+func (e *localExecutor) SetNodeConfig(hostID string, nodeConfigPath string) error {
+	return e.runOnHosts([]string{hostID}, func(hostID string) error {
+		nodeDir, err := e.nodeDir(hostID)
+		if err != nil {
+			return err
+		}
+		return binutils.CopyFile(nodeConfigPath, filepath.Join(nodeDir, constants.NodeFileName))
+	})
+}
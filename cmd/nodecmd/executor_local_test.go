@@ -0,0 +1,160 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/internal/testutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/config"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// set on the environment to make the test binary run as a fake avalanchego
+	fakeAvalancheGoEnv     = "AVALANCHE_CLI_FAKE_AVALANCHEGO"
+	fakeAvalancheGoVersion = "v1.10.13"
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeAvalancheGoEnv) != "" {
+		runFakeAvalancheGo(os.Args[1:])
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// runFakeAvalancheGo listens on the http and staking ports given by [args], answering healthy
+// to health checks, until interrupted. Fails as avalanchego does if a port is already in use
+func runFakeAvalancheGo(args []string) {
+	ports := map[string]string{}
+	for i := 0; i+1 < len(args); i += 2 {
+		ports[strings.TrimPrefix(args[i], "--")] = args[i+1]
+	}
+	stakingListener, err := net.Listen("tcp", net.JoinHostPort(localNodeIP, ports[config.StakingPortKey]))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer stakingListener.Close()
+	httpListener, err := net.Listen("tcp", net.JoinHostPort(localNodeIP, ports[config.HTTPPortKey]))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ext/health", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":{"checks":{},"healthy":true},"id":1}`))
+	})
+	go func() {
+		_ = http.Serve(httpListener, mux)
+	}()
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+	<-interrupted
+}
+
+// setupLocalExecutorTest sets up a local machine node with fake avalanchego installed, returning
+// its executor and host ID
+func setupLocalExecutorTest(t *testing.T) (*localExecutor, string) {
+	require := require.New(t)
+	app = testutils.SetupTestInTempDir(t)
+	t.Setenv(fakeAvalancheGoEnv, "1")
+
+	testBin, err := os.Executable()
+	require.NoError(err)
+	avalancheGoDir := filepath.Join(app.GetAvalanchegoBinDir(), "avalanchego-"+fakeAvalancheGoVersion)
+	require.NoError(os.MkdirAll(avalancheGoDir, constants.DefaultPerms755))
+	require.NoError(os.Symlink(testBin, filepath.Join(avalancheGoDir, localNodeAvalancheGoBin)))
+
+	usedPorts := map[uint32]bool{}
+	httpPort, err := getFreeLocalPort(usedPorts)
+	require.NoError(err)
+	stakingPort, err := getFreeLocalPort(usedPorts)
+	require.NoError(err)
+	cloudHostID := fmt.Sprintf("test-%d", httpPort)
+	require.NoError(app.CreateNodeCloudConfigFile(cloudHostID, &models.NodeConfig{
+		NodeID:       cloudHostID,
+		ElasticIP:    localNodeIP,
+		CloudService: constants.LocalMachineService,
+		HTTPPort:     httpPort,
+		StakingPort:  stakingPort,
+	}))
+	hostID, err := models.HostCloudIDToAnsibleID(constants.LocalMachineService, cloudHostID)
+	require.NoError(err)
+
+	executor := &localExecutor{network: models.LocalNetwork}
+	t.Cleanup(func() {
+		_ = executor.stopNode(hostID)
+	})
+	return executor, hostID
+}
+
+func TestLocalExecutorSetupNode(t *testing.T) {
+	require := require.New(t)
+	executor, hostID := setupLocalExecutorTest(t)
+
+	require.NoError(executor.SetupNode([]string{hostID}, fakeAvalancheGoVersion, false))
+	runInfo, err := executor.loadRunInfo(hostID)
+	require.NoError(err)
+	require.Equal(localNodeAvalancheGoBin, filepath.Base(runInfo.AvalancheGoPath))
+	require.True(isLocalProcessRunning(runInfo.Pid))
+
+	isHealthy, err := executor.IsHealthy([]string{hostID})
+	require.NoError(err)
+	require.True(isHealthy[hostID])
+
+	require.NoError(executor.StopNode(hostID))
+	require.False(isLocalProcessRunning(runInfo.Pid))
+	_, err = executor.IsHealthy([]string{hostID})
+	require.Error(err)
+}
+
+func TestLocalExecutorSetupDevnetNode(t *testing.T) {
+	require := require.New(t)
+	executor, hostID := setupLocalExecutorTest(t)
+
+	// devnet nodes are only started once the devnet is set up
+	require.NoError(executor.SetupNode([]string{hostID}, fakeAvalancheGoVersion, true))
+	runInfo, err := executor.loadRunInfo(hostID)
+	require.NoError(err)
+	require.Zero(runInfo.Pid)
+
+	require.NoError(executor.SetupDevnet([]string{hostID}, ""))
+	isHealthy, err := executor.IsHealthy([]string{hostID})
+	require.NoError(err)
+	require.True(isHealthy[hostID])
+}
+
+func TestLocalExecutorStartNodePortInUse(t *testing.T) {
+	require := require.New(t)
+	executor, hostID := setupLocalExecutorTest(t)
+	_, cloudHostID, err := models.HostAnsibleIDToCloudID(hostID)
+	require.NoError(err)
+	require.NoError(executor.installAvalancheGo(hostID, fakeAvalancheGoVersion))
+
+	httpPort, _, err := getNodePorts(cloudHostID)
+	require.NoError(err)
+	listener, err := net.Listen("tcp", net.JoinHostPort(localNodeIP, fmt.Sprint(httpPort)))
+	require.NoError(err)
+	defer listener.Close()
+
+	require.NoError(executor.StartNode(hostID))
+	newHTTPPort, _, err := getNodePorts(cloudHostID)
+	require.NoError(err)
+	require.NotEqual(httpPort, newHTTPPort)
+	isHealthy, err := executor.IsHealthy([]string{hostID})
+	require.NoError(err)
+	require.True(isHealthy[hostID])
+}
@@ -0,0 +1,176 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+)
+
+// sshExecutor runs the node operations natively over ssh, in parallel across hosts
+type sshExecutor struct {
+	hosts map[string]models.Host
+}
+
+func (e *sshExecutor) getHosts(hostIDs []string) ([]models.Host, error) {
+	hosts := make([]models.Host, 0, len(hostIDs))
+	for _, hostID := range hostIDs {
+		host, ok := e.hosts[hostID]
+		if !ok {
+			return nil, fmt.Errorf("host %s not found in cluster inventory", hostID)
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+// runOnHosts runs [op] on all hosts in [hostIDs], discarding results
+func (e *sshExecutor) runOnHosts(hostIDs []string, op func(*ssh.Client) error) error {
+	hosts, err := e.getHosts(hostIDs)
+	if err != nil {
+		return err
+	}
+	_, err = ssh.RunOnHosts(hosts, func(c *ssh.Client) (struct{}, error) {
+		return struct{}{}, op(c)
+	})
+	return err
+}
+
+func (e *sshExecutor) SetupNode(hostIDs []string, avalancheGoVersion string, isDevnet bool) error {
+	return e.runOnHosts(hostIDs, func(c *ssh.Client) error {
+		return ssh.RunSSHSetupNode(c, avalancheGoVersion, isDevnet)
+	})
+}
+
+func (e *sshExecutor) CopyStakingFiles(hostIDs []string, nodesDir string) error {
+	return e.runOnHosts(hostIDs, func(c *ssh.Client) error {
+		_, cloudHostID, err := models.HostAnsibleIDToCloudID(c.Host.NodeID)
+		if err != nil {
+			return err
+		}
+		return ssh.RunSSHCopyStakingFiles(c, filepath.Join(nodesDir, cloudHostID))
+	})
+}
+
+func (e *sshExecutor) SetupDevnet(hostIDs []string, nodesDir string) error {
+	return e.runOnHosts(hostIDs, func(c *ssh.Client) error {
+		_, cloudHostID, err := models.HostAnsibleIDToCloudID(c.Host.NodeID)
+		if err != nil {
+			return err
+		}
+		return ssh.RunSSHSetupDevnet(c, filepath.Join(nodesDir, cloudHostID))
+	})
+}
+
+func (e *sshExecutor) SetupBuildEnv(hostIDs []string) error {
+	return e.runOnHosts(hostIDs, ssh.RunSSHSetupBuildEnv)
+}
+
+func (e *sshExecutor) SetupCLIFromSource(hostIDs []string, cliBranch string) error {
+	return e.runOnHosts(hostIDs, func(c *ssh.Client) error {
+		return ssh.RunSSHSetupCLIFromSource(c, cliBranch)
+	})
+}
+
+func (e *sshExecutor) ExportSubnet(hostIDs []string, subnetPath string) error {
+	return e.runOnHosts(hostIDs, func(c *ssh.Client) error {
+		return ssh.RunSSHExportSubnet(c, subnetPath)
+	})
+}
+
+func (e *sshExecutor) TrackSubnet(hostID string, network models.Network, subnetName string, importPath string) error {
+	return e.runOnHosts([]string{hostID}, func(c *ssh.Client) error {
+		return ssh.RunSSHTrackSubnet(c, network, subnetName, importPath)
+	})
+}
+
+func (e *sshExecutor) UpdateSubnet(hostID string, network models.Network, subnetName string, importPath string) error {
+	return e.runOnHosts([]string{hostID}, func(c *ssh.Client) error {
+		return ssh.RunSSHUpdateSubnet(c, network, subnetName, importPath)
+	})
+}
+
+func (e *sshExecutor) GetVMVersions(hostIDs []string) (map[string]map[string]string, error) {
+	hosts, err := e.getHosts(hostIDs)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.RunOnHosts(hosts, ssh.RunSSHGetVMVersions)
+}
+
+func (e *sshExecutor) IsBootstrapped(hostIDs []string) (map[string]bool, error) {
+	hosts, err := e.getHosts(hostIDs)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.RunOnHosts(hosts, ssh.RunSSHCheckBootstrapped)
+}
+
+func (e *sshExecutor) GetNodeIDs(hostIDs []string) (map[string]string, error) {
+	hosts, err := e.getHosts(hostIDs)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.RunOnHosts(hosts, ssh.RunSSHGetNodeID)
+}
+
+func (e *sshExecutor) IsHealthy(hostIDs []string) (map[string]bool, error) {
+	hosts, err := e.getHosts(hostIDs)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.RunOnHosts(hosts, ssh.RunSSHCheckHealthy)
+}
+
+func (e *sshExecutor) GetSubnetSyncStatus(hostIDs []string, blockchainID string) (map[string]string, error) {
+	hosts, err := e.getHosts(hostIDs)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.RunOnHosts(hosts, func(c *ssh.Client) (string, error) {
+		return ssh.RunSSHSubnetSyncStatus(c, blockchainID)
+	})
+}
+
+func (e *sshExecutor) UpgradeAvalancheGo(hostID string, avalancheGoVersion string) error {
+	return e.runOnHosts([]string{hostID}, func(c *ssh.Client) error {
+		return ssh.RunSSHUpgradeAvalancheGo(c, avalancheGoVersion)
+	})
+}
+
+func (e *sshExecutor) StartNode(hostID string) error {
+	return e.runOnHosts([]string{hostID}, ssh.RunSSHStartNode)
+}
+
+func (e *sshExecutor) StopNode(hostID string) error {
+	return e.runOnHosts([]string{hostID}, ssh.RunSSHStopNode)
+}
+
+func (e *sshExecutor) GetNewSubnetEVMRelease(hostID string, subnetEVMReleaseURL string, subnetEVMArchive string) error {
+	return e.runOnHosts([]string{hostID}, func(c *ssh.Client) error {
+		return ssh.RunSSHGetNewSubnetEVMRelease(c, subnetEVMReleaseURL, subnetEVMArchive)
+	})
+}
+
+func (e *sshExecutor) UpgradeSubnetEVM(hostID string, subnetEVMBinaryPath string) error {
+	return e.runOnHosts([]string{hostID}, func(c *ssh.Client) error {
+		return ssh.RunSSHUpgradeSubnetEVM(c, subnetEVMBinaryPath)
+	})
+}
+
+func (e *sshExecutor) GetNodeConfig(hostIDs []string) (map[string][]byte, error) {
+	hosts, err := e.getHosts(hostIDs)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.RunOnHosts(hosts, ssh.RunSSHGetNodeConfig)
+}
+
+func (e *sshExecutor) SetNodeConfig(hostID string, nodeConfigPath string) error {
+	return e.runOnHosts([]string{hostID}, func(c *ssh.Client) error {
+		return ssh.RunSSHSetNodeConfig(c, nodeConfigPath)
+	})
+}
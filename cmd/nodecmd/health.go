@@ -0,0 +1,382 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/cmd/subnetcmd"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// exit codes of node health --check, by check, in the order they are looked at
+// when several checks fail
+const (
+	healthExitNotBootstrapped = 2
+	healthExitNotHealthy      = 3
+	healthExitSubnetNotSynced = 4
+	healthExitLowUptime       = 5
+	healthWebhookTimeout      = 10 * time.Second
+)
+
+var (
+	healthCheck         bool
+	healthSubnets       []string
+	healthMinUptime     float64
+	healthWebhooks      []string
+	healthSlackWebhooks []string
+	healthExitCodes     = map[string]int{
+		models.HealthCheckBootstrapped:    healthExitNotBootstrapped,
+		models.HealthCheckHealthy:         healthExitNotHealthy,
+		models.HealthCheckSubnetSynced:    healthExitSubnetNotSynced,
+		models.HealthCheckValidatorUptime: healthExitLowUptime,
+	}
+)
+
+// healthCheckError is returned by node health --check when checks fail, so the CLI
+// exits with the code of the failed check
+type healthCheckError struct {
+	clusterName string
+	failed      []models.HealthCheckResult
+	exitCode    int
+}
+
+func (e healthCheckError) Error() string {
+	return fmt.Sprintf("%d health check(s) failed for cluster %s", len(e.failed), e.clusterName)
+}
+
+func (e healthCheckError) ExitCode() int {
+	return e.exitCode
+}
+
+func newHealthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "health [clusterName]",
+		Short: "(ALPHA Warning) Run health checks on the nodes of a cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node health command checks that all nodes in a cluster are bootstrapped and
+healthy, that they are syncing the subnets given with --subnet, and that the
+ones validating the Primary Network have an uptime of at least --min-uptime.
+
+With --check, the command is meant to be run unattended, from cron or a systemd
+timer. It exits with a distinct code for the first failing check:
+  2: some node is not bootstrapped
+  3: some node is not healthy
+  4: some node is not syncing a subnet
+  5: some validator has an uptime below --min-uptime
+A check that can't be run, e.g. because the P-Chain API is unreachable, counts
+as failed on all nodes. Any other error exits with code 1. The result is compared with the one of the
+previous --check run, and when some check changes state, a JSON summary is
+posted to the webhooks given with --webhook, and a message to the Slack
+compatible webhooks given with --slack-webhook.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         checkHealth,
+	}
+	cmd.Flags().BoolVar(&healthCheck, "check", false, "exit with the code of the failed check, and alert webhooks on state changes")
+	cmd.Flags().StringSliceVar(&healthSubnets, "subnet", nil, "check the nodes are syncing the given subnet(s)")
+	cmd.Flags().Float64Var(&healthMinUptime, "min-uptime", validatorRewardUptimePerc, "minimum uptime percentage of the validator nodes")
+	cmd.Flags().StringSliceVar(&healthWebhooks, "webhook", nil, "post a JSON summary to the given URL(s) when some check changes state (requires --check)")
+	cmd.Flags().StringSliceVar(&healthSlackWebhooks, "slack-webhook", nil, "post a message to the given Slack webhook URL(s) when some check changes state (requires --check)")
+	return cmd
+}
+
+// getNodesResults returns a result of [check] for each one of [hostIDs], failed for the ones in [failedHostIDs]
+func getNodesResults(check string, hostIDs []string, failedHostIDs []string) ([]models.HealthCheckResult, error) {
+	failed := map[string]bool{}
+	for _, hostID := range failedHostIDs {
+		failed[hostID] = true
+	}
+	results := []models.HealthCheckResult{}
+	for _, hostID := range hostIDs {
+		_, cloudID, err := models.HostAnsibleIDToCloudID(hostID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, models.HealthCheckResult{Check: check, Node: cloudID, OK: !failed[hostID]})
+	}
+	return results, nil
+}
+
+// getFailedNodes returns the nodes of [hostIDs] [check] is not true for. Nodes the check
+// couldn't be run on, as returned in ssh.HostErrors, are counted as failed
+func getFailedNodes(hostIDs []string, check func([]string) (map[string]bool, error)) ([]string, error) {
+	passed, err := check(hostIDs)
+	if err != nil {
+		var hostErrs ssh.HostErrors
+		if !errors.As(err, &hostErrs) {
+			return nil, err
+		}
+	}
+	failed := []string{}
+	for _, hostID := range hostIDs {
+		if !passed[hostID] {
+			failed = append(failed, hostID)
+		}
+	}
+	return failed, nil
+}
+
+// checkSubnetSynced checks that [hostIDs] are syncing or validating [subnetName]
+func checkSubnetSynced(hostIDs []string, subnetName string, network models.Network) ([]models.HealthCheckResult, error) {
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return nil, err
+	}
+	blockchainID := sc.Networks[network.Name()].BlockchainID
+	if blockchainID == ids.Empty {
+		return nil, fmt.Errorf("subnet %s: %w", subnetName, ErrNoBlockchainID)
+	}
+	syncStatus, err := nodeExecutor.GetSubnetSyncStatus(hostIDs, blockchainID.String())
+	if err != nil {
+		// unreachable nodes are reported as not synced
+		var hostErrs ssh.HostErrors
+		if !errors.As(err, &hostErrs) {
+			return nil, err
+		}
+	}
+	results := []models.HealthCheckResult{}
+	for _, hostID := range hostIDs {
+		_, cloudID, err := models.HostAnsibleIDToCloudID(hostID)
+		if err != nil {
+			return nil, err
+		}
+		nodeStatus, ok := syncStatus[hostID]
+		if !ok {
+			nodeStatus = status.Unknown.String()
+		}
+		results = append(results, models.HealthCheckResult{
+			Check:  models.HealthCheckSubnetSynced,
+			Node:   cloudID,
+			Subnet: subnetName,
+			OK:     nodeStatus == status.Syncing.String() || nodeStatus == status.Validating.String(),
+			Detail: nodeStatus,
+		})
+	}
+	return results, nil
+}
+
+// checkValidatorsUptime checks the uptime of the nodes of [hostIDs] that are Primary Network
+// validators, as seen by the P-Chain API of [network]
+func checkValidatorsUptime(hostIDs []string, network models.Network) ([]models.HealthCheckResult, error) {
+	nodeIDs := []ids.NodeID{}
+	cloudIDs := map[ids.NodeID]string{}
+	for _, hostID := range hostIDs {
+		_, cloudID, err := models.HostAnsibleIDToCloudID(hostID)
+		if err != nil {
+			return nil, err
+		}
+		nodeID, err := getNodeID(app.GetNodeInstanceDirPath(cloudID))
+		if err != nil {
+			return nil, err
+		}
+		nodeIDs = append(nodeIDs, nodeID)
+		cloudIDs[nodeID] = cloudID
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	validators, err := platformvm.NewClient(network.Endpoint).GetCurrentValidators(ctx, avagoconstants.PrimaryNetworkID, nodeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the validators from %s: %w", network.Endpoint, err)
+	}
+	results := []models.HealthCheckResult{}
+	for _, validator := range validators {
+		cloudID, ok := cloudIDs[validator.NodeID]
+		if !ok || validator.Uptime == nil {
+			continue
+		}
+		results = append(results, models.HealthCheckResult{
+			Check:  models.HealthCheckValidatorUptime,
+			Node:   cloudID,
+			OK:     float64(*validator.Uptime) >= healthMinUptime,
+			Detail: fmt.Sprintf("uptime %.2f%%", *validator.Uptime),
+		})
+	}
+	return results, nil
+}
+
+// getCheckErrorResult returns the failed result of [check] for the whole cluster, when
+// the check itself fails to run with [err]
+func getCheckErrorResult(check string, subnetName string, err error) models.HealthCheckResult {
+	return models.HealthCheckResult{Check: check, Subnet: subnetName, OK: false, Detail: err.Error()}
+}
+
+// runHealthChecks runs all the health checks on the nodes of [clusterName]. Unreachable nodes
+// fail the checks, and a check that fails to run is recorded as failed for the whole cluster,
+// so it is alerted on and saved as any other failure
+func runHealthChecks(clusterName string, network models.Network) (models.ClusterHealthReport, error) {
+	report := models.ClusterHealthReport{ClusterName: clusterName, Time: time.Now().UTC()}
+	hostIDs, err := getClusterHostIDs(clusterName, "")
+	if err != nil {
+		return report, err
+	}
+	if notBootstrappedNodes, err := getFailedNodes(hostIDs, nodeExecutor.IsBootstrapped); err != nil {
+		report.Results = append(report.Results, getCheckErrorResult(models.HealthCheckBootstrapped, "", err))
+	} else {
+		results, err := getNodesResults(models.HealthCheckBootstrapped, hostIDs, notBootstrappedNodes)
+		if err != nil {
+			return report, err
+		}
+		report.Results = append(report.Results, results...)
+	}
+	if notHealthyNodes, err := getFailedNodes(hostIDs, nodeExecutor.IsHealthy); err != nil {
+		report.Results = append(report.Results, getCheckErrorResult(models.HealthCheckHealthy, "", err))
+	} else {
+		results, err := getNodesResults(models.HealthCheckHealthy, hostIDs, notHealthyNodes)
+		if err != nil {
+			return report, err
+		}
+		report.Results = append(report.Results, results...)
+	}
+	for _, subnetName := range healthSubnets {
+		results, err := checkSubnetSynced(hostIDs, subnetName, network)
+		if err != nil {
+			results = []models.HealthCheckResult{getCheckErrorResult(models.HealthCheckSubnetSynced, subnetName, err)}
+		}
+		report.Results = append(report.Results, results...)
+	}
+	results, err := checkValidatorsUptime(hostIDs, network)
+	if err != nil {
+		results = []models.HealthCheckResult{getCheckErrorResult(models.HealthCheckValidatorUptime, "", err)}
+	}
+	report.Results = append(report.Results, results...)
+	return report, nil
+}
+
+func printHealthReport(report models.ClusterHealthReport) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Check", "Node", "Subnet", "Status", "Detail"})
+	table.SetRowLine(true)
+	for _, result := range report.Results {
+		state := "OK"
+		if !result.OK {
+			state = "FAILED"
+		}
+		table.Append([]string{result.Check, result.Node, result.Subnet, state, result.Detail})
+	}
+	table.Render()
+}
+
+// postWebhook posts [payload] as JSON to [url]
+func postWebhook(url string, payload interface{}) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	client := http.Client{Timeout: healthWebhookTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payloadBytes))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// alertHealthChanges posts the [changes] of [report] to the configured webhooks. Failures to
+// post are only reported, so they don't hide the result of the checks
+func alertHealthChanges(report models.ClusterHealthReport, changes []models.HealthCheckResult) {
+	payload := map[string]interface{}{
+		"cluster": report.ClusterName,
+		"time":    report.Time,
+		"healthy": len(report.Failed()) == 0,
+		"changes": changes,
+		"failed":  report.Failed(),
+	}
+	for _, url := range healthWebhooks {
+		if err := postWebhook(url, payload); err != nil {
+			ux.Logger.PrintToUser("failed to post health changes to webhook: %s", err)
+		}
+	}
+	slackPayload := map[string]string{"text": report.Summary(changes)}
+	for _, url := range healthSlackWebhooks {
+		if err := postWebhook(url, slackPayload); err != nil {
+			ux.Logger.PrintToUser("failed to post health changes to slack webhook: %s", err)
+		}
+	}
+}
+
+// getHealthExitCode returns the exit code of the first failing check of [failed]
+func getHealthExitCode(failed []models.HealthCheckResult) int {
+	for _, check := range []string{
+		models.HealthCheckBootstrapped,
+		models.HealthCheckHealthy,
+		models.HealthCheckSubnetSynced,
+		models.HealthCheckValidatorUptime,
+	} {
+		for _, result := range failed {
+			if result.Check == check {
+				return healthExitCodes[check]
+			}
+		}
+	}
+	return 1
+}
+
+func checkHealth(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if !healthCheck && (len(healthWebhooks) > 0 || len(healthSlackWebhooks) > 0) {
+		return errors.New("webhooks can only be used with --check")
+	}
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	for _, subnetName := range healthSubnets {
+		if _, err := subnetcmd.ValidateSubnetNameAndGetChains([]string{subnetName}); err != nil {
+			return err
+		}
+	}
+	if err := setupNodeExecutor(clusterName); err != nil {
+		return err
+	}
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	network := clustersConfig.Clusters[clusterName].Network
+	report, err := runHealthChecks(clusterName, network)
+	if err != nil {
+		return err
+	}
+	printHealthReport(report)
+	failed := report.Failed()
+	if !healthCheck {
+		if len(failed) == 0 {
+			ux.Logger.PrintToUser("All checks passed for cluster %s", clusterName)
+		}
+		return nil
+	}
+	previousReport, err := app.LoadClusterHealthReport(clusterName)
+	if err != nil {
+		return err
+	}
+	if changes := report.Changes(previousReport); len(changes) > 0 {
+		ux.Logger.PrintToUser(report.Summary(changes))
+		alertHealthChanges(report, changes)
+	}
+	if err := app.WriteClusterHealthReport(clusterName, report); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return healthCheckError{clusterName: clusterName, failed: failed, exitCode: getHealthExitCode(failed)}
+	}
+	return nil
+}
@@ -3,10 +3,7 @@
 package nodecmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
-	"os"
 
 	"github.com/ava-labs/avalanche-cli/pkg/ansible"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
@@ -19,44 +16,14 @@ func checkClusterIsHealthy(clusterName string) ([]string, error) {
 	}
 	notHealthyNodes := []string{}
 	ux.Logger.PrintToUser(fmt.Sprintf("Checking if node(s) in cluster %s are healthy ...", clusterName))
-	if err := app.CreateAnsibleStatusDir(); err != nil {
-		return nil, err
-	}
-	if err := ansible.RunAnsiblePlaybookCheckHealthy(app.GetAnsibleDir(), app.GetHealthyJSONFile(), app.GetAnsibleInventoryDirPath(clusterName), "all"); err != nil {
+	isHealthy, err := nodeExecutor.IsHealthy(ansibleNodeIDs)
+	if err != nil {
 		return nil, err
 	}
 	for _, ansibleNodeID := range ansibleNodeIDs {
-		isHealthy, err := parseHealthyOutput(app.GetHealthyJSONFile() + "." + ansibleNodeID)
-		if err != nil {
-			return nil, err
-		}
-		if !isHealthy {
+		if !isHealthy[ansibleNodeID] {
 			notHealthyNodes = append(notHealthyNodes, ansibleNodeID)
 		}
 	}
-	if err := app.RemoveAnsibleStatusDir(); err != nil {
-		return nil, err
-	}
 	return notHealthyNodes, nil
 }
-
-func parseHealthyOutput(filePath string) (bool, error) {
-	jsonFile, err := os.Open(filePath)
-	if err != nil {
-		return false, err
-	}
-	defer jsonFile.Close()
-	byteValue, _ := io.ReadAll(jsonFile)
-	var result map[string]interface{}
-	if err := json.Unmarshal(byteValue, &result); err != nil {
-		return false, err
-	}
-	isHealthyInterface, ok := result["result"].(map[string]interface{})
-	if ok {
-		isHealthy, ok := isHealthyInterface["healthy"].(bool)
-		if ok {
-			return isHealthy, nil
-		}
-	}
-	return false, fmt.Errorf("unable to parse node healthy status")
-}
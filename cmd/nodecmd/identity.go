@@ -0,0 +1,304 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// nodeIDCheckTimeout is the time to wait for a node to answer with its NodeID after being
+	// (re)started with imported staking files
+	nodeIDCheckTimeout = 2 * time.Minute
+	// stakingBackupDir is the subdir of a node dir where import-identity keeps the replaced
+	// staking files, in a subdir named after their NodeID
+	stakingBackupDir = "staking_backup"
+)
+
+var (
+	stakingDir          string
+	stakingFiles        []string
+	importIdentityForce bool
+	// importedStakingIdentities are the identities given to node create, used instead of
+	// generating new staking files for the created nodes
+	importedStakingIdentities []stakingIdentity
+)
+
+// stakingIdentity is the set of staking files that identify an avalanchego node
+type stakingIdentity struct {
+	NodeID         ids.NodeID
+	CertBytes      []byte
+	KeyBytes       []byte
+	SignerKeyBytes []byte
+}
+
+func newImportIdentityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-identity [clusterName] [nodeID]",
+		Short: "(ALPHA Warning) Replace the staking identity of a node with existing staking files",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node import-identity command replaces the staking cert, key and BLS signer
+key of a node of a cluster with the given ones, so the node keeps the NodeID of
+an existing validator. The node can be given either by its cloud instance ID or
+by its Avalanche NodeID.
+
+The staking files are given either with --staking-dir, a dir containing
+staker.crt, staker.key and signer.key, or with --staking-files. The files are
+stored in the local node dir, uploaded to the node, and the node is restarted.
+The command then checks that the node runs with the expected NodeID. The
+replaced staking files are kept in the staking_backup/<NodeID> subdir of the
+local node dir.
+
+Make sure the validator previously using these files is stopped, as two nodes
+must never run with the same staking identity.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(2),
+		RunE:         importIdentity,
+	}
+	cmd.Flags().StringVar(&stakingDir, "staking-dir", "", "dir containing the staker.crt, staker.key and signer.key files to import")
+	cmd.Flags().StringSliceVar(&stakingFiles, "staking-files", nil, "staker.crt, staker.key and signer.key files to import, in this order")
+	cmd.Flags().BoolVar(&importIdentityForce, "force", false, "don't ask for confirmation before replacing the node identity")
+	return cmd
+}
+
+// loadStakingIdentity loads the staking files at the given paths, checking they are valid
+func loadStakingIdentity(certPath, keyPath, signerKeyPath string) (stakingIdentity, error) {
+	certBytes, err := os.ReadFile(certPath)
+	if err != nil {
+		return stakingIdentity{}, err
+	}
+	keyBytes, err := os.ReadFile(keyPath)
+	if err != nil {
+		return stakingIdentity{}, err
+	}
+	nodeID, err := utils.ToNodeID(certBytes, keyBytes)
+	if err != nil {
+		return stakingIdentity{}, fmt.Errorf("invalid staking cert %s and key %s: %w", certPath, keyPath, err)
+	}
+	signerKeyBytes, err := os.ReadFile(signerKeyPath)
+	if err != nil {
+		return stakingIdentity{}, err
+	}
+	if _, err := bls.SecretKeyFromBytes(signerKeyBytes); err != nil {
+		return stakingIdentity{}, fmt.Errorf("invalid BLS signer key %s: %w", signerKeyPath, err)
+	}
+	return stakingIdentity{
+		NodeID:         nodeID,
+		CertBytes:      certBytes,
+		KeyBytes:       keyBytes,
+		SignerKeyBytes: signerKeyBytes,
+	}, nil
+}
+
+func loadStakingIdentityFromDir(dir string) (stakingIdentity, error) {
+	return loadStakingIdentity(
+		filepath.Join(dir, constants.StakerCertFileName),
+		filepath.Join(dir, constants.StakerKeyFileName),
+		filepath.Join(dir, constants.BLSKeyFileName),
+	)
+}
+
+// loadStakingIdentities loads the identities given by [stakingDir] or [stakingFiles], if any.
+// [stakingDir] either contains the staking files of one node, or a subdir with them for each
+// node, taken in name order
+func loadStakingIdentities(stakingDir string, stakingFiles []string) ([]stakingIdentity, error) {
+	switch {
+	case stakingDir != "" && len(stakingFiles) > 0:
+		return nil, errors.New("could not use both staking dir and staking files options")
+	case len(stakingFiles) > 0:
+		if len(stakingFiles) != 3 {
+			return nil, errors.New("staking files must be given as staker.crt, staker.key and signer.key paths")
+		}
+		identity, err := loadStakingIdentity(stakingFiles[0], stakingFiles[1], stakingFiles[2])
+		if err != nil {
+			return nil, err
+		}
+		return []stakingIdentity{identity}, nil
+	case stakingDir == "":
+		return nil, nil
+	}
+	if utils.FileExists(filepath.Join(stakingDir, constants.StakerCertFileName)) {
+		identity, err := loadStakingIdentityFromDir(stakingDir)
+		if err != nil {
+			return nil, err
+		}
+		return []stakingIdentity{identity}, nil
+	}
+	entries, err := os.ReadDir(stakingDir)
+	if err != nil {
+		return nil, err
+	}
+	identities := []stakingIdentity{}
+	nodeIDs := map[ids.NodeID]bool{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		identity, err := loadStakingIdentityFromDir(filepath.Join(stakingDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if nodeIDs[identity.NodeID] {
+			return nil, fmt.Errorf("staking identity %s found more than once at %s", identity.NodeID, stakingDir)
+		}
+		nodeIDs[identity.NodeID] = true
+		identities = append(identities, identity)
+	}
+	if len(identities) == 0 {
+		return nil, fmt.Errorf("no staking files found at %s", stakingDir)
+	}
+	return identities, nil
+}
+
+// writeStakingIdentity writes the staking files of [identity] into [nodeDir]
+func writeStakingIdentity(identity stakingIdentity, nodeDir string) error {
+	if err := os.MkdirAll(nodeDir, constants.DefaultPerms755); err != nil {
+		return err
+	}
+	for fileName, fileBytes := range map[string][]byte{
+		constants.StakerCertFileName: identity.CertBytes,
+		constants.StakerKeyFileName:  identity.KeyBytes,
+		constants.BLSKeyFileName:     identity.SignerKeyBytes,
+	} {
+		if err := os.WriteFile(filepath.Join(nodeDir, fileName), fileBytes, constants.WriteReadUserOnlyPerms); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backupStakingIdentity copies the staking files of [nodeDir] into its staking backup dir,
+// so they are not lost when replaced. Files of a previous backup of the same NodeID are kept
+func backupStakingIdentity(nodeDir string) error {
+	identity, err := loadStakingIdentityFromDir(nodeDir)
+	if err != nil {
+		return fmt.Errorf("failed to load the current staking files of %s: %w", nodeDir, err)
+	}
+	backupDir := filepath.Join(nodeDir, stakingBackupDir, identity.NodeID.String())
+	if utils.DirectoryExists(backupDir) {
+		return nil
+	}
+	if err := writeStakingIdentity(identity, backupDir); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Staking files of NodeID %s backed up at %s", identity.NodeID, backupDir)
+	return nil
+}
+
+// checkNodeIDs waits for the nodes [ansibleHostIDs] to answer with their NodeID, and checks
+// it is the one of the staking files stored in their node dir
+func checkNodeIDs(ansibleHostIDs []string) error {
+	ux.Logger.PrintToUser("Checking the NodeID of the node(s) ...")
+	deadline := time.Now().Add(nodeIDCheckTimeout)
+	pendingHostIDs := ansibleHostIDs
+	for {
+		// errors are expected while the nodes start, so failing nodes are retried
+		nodeIDs, _ := nodeExecutor.GetNodeIDs(pendingHostIDs)
+		stillPending := []string{}
+		for _, hostID := range pendingHostIDs {
+			runningNodeID, ok := nodeIDs[hostID]
+			if !ok {
+				stillPending = append(stillPending, hostID)
+				continue
+			}
+			_, cloudHostID, err := models.HostAnsibleIDToCloudID(hostID)
+			if err != nil {
+				return err
+			}
+			expectedNodeID, err := getNodeID(app.GetNodeInstanceDirPath(cloudHostID))
+			if err != nil {
+				return err
+			}
+			if runningNodeID != expectedNodeID.String() {
+				return fmt.Errorf("node %s runs with NodeID %s instead of the imported %s", cloudHostID, runningNodeID, expectedNodeID)
+			}
+			ux.Logger.PrintToUser("Node %s runs with NodeID %s", cloudHostID, runningNodeID)
+		}
+		pendingHostIDs = stillPending
+		if len(pendingHostIDs) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("node(s) %s didn't answer with their NodeID after %s", pendingHostIDs, nodeIDCheckTimeout)
+		}
+		time.Sleep(upgradeHealthCheckInterval)
+	}
+}
+
+func importIdentity(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	if stakingDir == "" && len(stakingFiles) == 0 {
+		return errors.New("staking files must be given with either --staking-dir or --staking-files")
+	}
+	identities, err := loadStakingIdentities(stakingDir, stakingFiles)
+	if err != nil {
+		return err
+	}
+	if len(identities) != 1 {
+		return fmt.Errorf("found %d staking identities at %s, expected one", len(identities), stakingDir)
+	}
+	identity := identities[0]
+	node, err := getClusterNode(clusterName, args[1])
+	if err != nil {
+		return err
+	}
+	nodeDir := app.GetNodeInstanceDirPath(node)
+	currentNodeID, err := getNodeID(nodeDir)
+	if err != nil {
+		return err
+	}
+	if currentNodeID == identity.NodeID {
+		ux.Logger.PrintToUser("Node %s already runs with NodeID %s", node, identity.NodeID)
+		return nil
+	}
+	if !importIdentityForce {
+		yes, err := app.Prompt.CaptureYesNo(fmt.Sprintf("The NodeID of node %s will change from %s to %s. Do you want to proceed?",
+			node, currentNodeID, identity.NodeID))
+		if err != nil {
+			return err
+		}
+		if !yes {
+			return errors.New("abort avalanche node import-identity command")
+		}
+	}
+	hostIDs, err := getClusterHostIDs(clusterName, node)
+	if err != nil {
+		return err
+	}
+	if err := setupNodeExecutor(clusterName); err != nil {
+		return err
+	}
+	if err := backupStakingIdentity(nodeDir); err != nil {
+		return err
+	}
+	if err := writeStakingIdentity(identity, nodeDir); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Copying staking keys to node %s ...", node)
+	if err := nodeExecutor.CopyStakingFiles(hostIDs, app.GetNodesDir()); err != nil {
+		return err
+	}
+	if err := nodeExecutor.StopNode(hostIDs[0]); err != nil {
+		return err
+	}
+	if err := nodeExecutor.StartNode(hostIDs[0]); err != nil {
+		return err
+	}
+	return checkNodeIDs(hostIDs)
+}
@@ -44,7 +44,7 @@ func list(_ *cobra.Command, _ []string) error {
 		if err := checkCluster(clusterName); err != nil {
 			return err
 		}
-		if err := setupAnsible(clusterName); err != nil {
+		if err := setupNodeExecutor(clusterName); err != nil {
 			return err
 		}
 		ansibleHostIDs, err := ansible.GetAnsibleHostsFromInventory(app.GetAnsibleInventoryDirPath(clusterName))
@@ -0,0 +1,352 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsNode   string
+	logsChain  string
+	logsSince  time.Duration
+	logsFollow bool
+	logsLines  int
+	logsBundle string
+)
+
+func newLogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs [clusterName]",
+		Short: "(ALPHA Warning) Show or download the avalanchego logs of the cluster nodes",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node logs command prints the avalanchego logs of all nodes in a cluster,
+collected in parallel: main, P, X and C chain logs, and the logs of the Subnet
+chains tracked by the nodes. Each line is prefixed with the node and the log it
+comes from.
+
+By default, the last lines of each log are printed. Use --since to print the
+lines logged within a given time instead, and --follow to keep streaming new
+lines. Use --node to only get the logs of one node, and --chain to only get the
+logs of one chain, given by its Subnet name or as P, X or C.
+
+With --bundle, the complete log files are downloaded into a tar.gz file instead,
+to be shared on support tickets.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         printLogs,
+	}
+	cmd.Flags().StringVar(&logsNode, "node", "", "only get the logs of the given node, by cloud instance ID or Avalanche NodeID")
+	cmd.Flags().StringVar(&logsChain, "chain", "", "only get the logs of the given chain, by Subnet name or as P, X or C")
+	cmd.Flags().DurationVar(&logsSince, "since", 0, "print the lines logged within the given time, e.g. 30m")
+	cmd.Flags().BoolVar(&logsFollow, "follow", false, "keep printing new log lines as they are logged")
+	cmd.Flags().IntVar(&logsLines, "lines", 100, "number of last lines to print of each log")
+	cmd.Flags().StringVar(&logsBundle, "bundle", "", "download the complete log files into the given tar.gz file")
+	return cmd
+}
+
+// getChainLogFile returns the log file name of [chain] on [network], or "" to get all logs
+func getChainLogFile(network models.Network, chain string) (string, error) {
+	switch chain {
+	case "":
+		return "", nil
+	case "P", "X", "C":
+		return chain + ".log", nil
+	}
+	sc, err := app.LoadSidecar(chain)
+	if err != nil {
+		return "", fmt.Errorf("failed to load sidecar of subnet %s: %w", chain, err)
+	}
+	blockchainID := sc.Networks[network.Name()].BlockchainID
+	if blockchainID == ids.Empty {
+		return "", fmt.Errorf("subnet %s is not deployed to %s", chain, network.Name())
+	}
+	return blockchainID.String() + ".log", nil
+}
+
+// rotatedLogFile matches the names lumberjack gives to the avalanchego logs it rotates, e.g.
+// main-2023-10-20T10-00-00.000.log, compressed into a .gz file if log rotation compression is on
+var rotatedLogFile = regexp.MustCompile(`^(.+)-\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2}\.\d{3}\.log(\.gz)?$`)
+
+// getCurrentLogFile returns the name of the log [logFile] belongs to, and whether it is a rotated
+// file of that log
+func getCurrentLogFile(logFile string) (string, bool) {
+	if match := rotatedLogFile.FindStringSubmatch(logFile); match != nil {
+		return match[1] + ".log", true
+	}
+	return logFile, false
+}
+
+// selectLogFiles returns the files of [logFiles] to get, given the file of the selected chain, if any.
+// Rotated log files are only included if [withRotated]
+func selectLogFiles(hostID string, logFiles []string, chainLogFile string, withRotated bool) ([]string, error) {
+	selected := []string{}
+	foundChainLog := false
+	for _, logFile := range logFiles {
+		currentLogFile, rotated := getCurrentLogFile(logFile)
+		if chainLogFile != "" && currentLogFile != chainLogFile {
+			continue
+		}
+		if !rotated {
+			foundChainLog = true
+		} else if !withRotated {
+			continue
+		}
+		selected = append(selected, logFile)
+	}
+	if chainLogFile != "" && !foundChainLog {
+		return nil, fmt.Errorf("no %s log found on host %s, is it tracking the chain?", chainLogFile, hostID)
+	}
+	sort.Strings(selected)
+	return selected, nil
+}
+
+// getLocalNodeLogsDir returns the avalanchego logs dir of a node running on the local machine,
+// which avalanchego sets under its data dir
+func getLocalNodeLogsDir(hostID string) (string, error) {
+	_, cloudHostID, err := models.HostAnsibleIDToCloudID(hostID)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(app.GetNodeInstanceDirPath(cloudHostID), localNodeDataDir, "logs"), nil
+}
+
+// listLocalLogFiles returns the names of the avalanchego log files at [logsDir], rotated
+// (and possibly compressed) logs included
+func listLocalLogFiles(logsDir string) ([]string, error) {
+	logPaths := []string{}
+	for _, pattern := range []string{"*.log", "*.log.gz"} {
+		matches, err := filepath.Glob(filepath.Join(logsDir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		logPaths = append(logPaths, matches...)
+	}
+	if len(logPaths) == 0 {
+		return nil, fmt.Errorf("no avalanchego logs found at %s", logsDir)
+	}
+	return utils.Map(logPaths, filepath.Base), nil
+}
+
+// getLogs gets the logs of [hostID], with [getLocal] if it runs on the local machine, or else
+// with [getRemote] over ssh
+func getLogs(
+	hostID string,
+	hosts map[string]models.Host,
+	getLocal func(logsDir string, logFiles []string) error,
+	getRemote func(c *ssh.Client, logFiles []string) error,
+) error {
+	cloudService, _, err := models.HostAnsibleIDToCloudID(hostID)
+	if err != nil {
+		return err
+	}
+	if cloudService == constants.LocalMachineService {
+		logsDir, err := getLocalNodeLogsDir(hostID)
+		if err != nil {
+			return err
+		}
+		logFiles, err := listLocalLogFiles(logsDir)
+		if err != nil {
+			return err
+		}
+		return getLocal(logsDir, logFiles)
+	}
+	host, ok := hosts[hostID]
+	if !ok {
+		return fmt.Errorf("host %s not found in cluster inventory", hostID)
+	}
+	c, err := ssh.Connect(host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	logFiles, err := ssh.RunSSHListLogFiles(c)
+	if err != nil {
+		return err
+	}
+	return getRemote(c, logFiles)
+}
+
+// runOnLogsHosts runs [op] on all [hostIDs] in parallel, collecting the errors by host ID
+func runOnLogsHosts(hostIDs []string, op func(hostID string) error) error {
+	var (
+		lock     sync.Mutex
+		wg       sync.WaitGroup
+		hostErrs = ssh.HostErrors{}
+	)
+	for _, hostID := range hostIDs {
+		wg.Add(1)
+		go func(hostID string) {
+			defer wg.Done()
+			if err := op(hostID); err != nil {
+				lock.Lock()
+				hostErrs[hostID] = err
+				lock.Unlock()
+			}
+		}(hostID)
+	}
+	wg.Wait()
+	if len(hostErrs) > 0 {
+		return hostErrs
+	}
+	return nil
+}
+
+// streamLogs prints the lines of the logs of [hostIDs] selected by the command flags, each one
+// prefixed with its cloud host ID
+func streamLogs(hostIDs []string, hosts map[string]models.Host, chainLogFile string) error {
+	var outputLock sync.Mutex
+	return runOnLogsHosts(hostIDs, func(hostID string) error {
+		_, cloudHostID, err := models.HostAnsibleIDToCloudID(hostID)
+		if err != nil {
+			return err
+		}
+		output := utils.NewPrefixWriter(os.Stdout, &outputLock, fmt.Sprintf("[%s] ", cloudHostID))
+		defer output.Flush()
+		return getLogs(hostID, hosts,
+			func(logsDir string, logFiles []string) error {
+				logFiles, err := selectLogFiles(hostID, logFiles, chainLogFile, false)
+				if err != nil {
+					return err
+				}
+				script, err := ssh.RenderLogsScript(logsDir, logFiles, logsLines, logsSince, logsFollow)
+				if err != nil {
+					return err
+				}
+				cmd := exec.Command("bash", "-c", string(script)) //nolint:gosec
+				cmd.Stdout = output
+				return cmd.Run()
+			},
+			func(c *ssh.Client, logFiles []string) error {
+				logFiles, err := selectLogFiles(hostID, logFiles, chainLogFile, false)
+				if err != nil {
+					return err
+				}
+				return ssh.RunSSHStreamLogs(c, logFiles, logsLines, logsSince, logsFollow, output)
+			},
+		)
+	})
+}
+
+// bundleLogs downloads the logs of [hostIDs], rotated logs included, into the tar.gz file
+// [bundlePath], under a dir for each cloud host ID. If the logs of some hosts can't be
+// collected, the bundle is still saved with the logs of the others, and an error is returned
+func bundleLogs(hostIDs []string, hosts map[string]models.Host, chainLogFile string, bundlePath string) error {
+	bundleDir, err := os.MkdirTemp("", "avalanchego-logs")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(bundleDir)
+	err = runOnLogsHosts(hostIDs, func(hostID string) error {
+		_, cloudHostID, err := models.HostAnsibleIDToCloudID(hostID)
+		if err != nil {
+			return err
+		}
+		hostBundleDir := filepath.Join(bundleDir, cloudHostID)
+		return getLogs(hostID, hosts,
+			func(logsDir string, logFiles []string) error {
+				logFiles, err := selectLogFiles(hostID, logFiles, chainLogFile, true)
+				if err != nil {
+					return err
+				}
+				if err := os.MkdirAll(hostBundleDir, constants.DefaultPerms755); err != nil {
+					return err
+				}
+				for _, logFile := range logFiles {
+					content, err := os.ReadFile(filepath.Join(logsDir, logFile))
+					if err != nil {
+						return err
+					}
+					if err := os.WriteFile(filepath.Join(hostBundleDir, logFile), content, constants.WriteReadUserOnlyPerms); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			func(c *ssh.Client, logFiles []string) error {
+				logFiles, err := selectLogFiles(hostID, logFiles, chainLogFile, true)
+				if err != nil {
+					return err
+				}
+				for _, logFile := range logFiles {
+					if err := c.Download(constants.CloudNodeLogsPath+logFile, filepath.Join(hostBundleDir, logFile)); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		)
+	})
+	hostErrs := ssh.HostErrors{}
+	if err != nil && !errors.As(err, &hostErrs) {
+		return err
+	}
+	if len(hostErrs) == len(hostIDs) {
+		return fmt.Errorf("failed to get the logs of all nodes, no logs bundle saved: %w", err)
+	}
+	if err := utils.CreateTarGz(bundleDir, bundlePath); err != nil {
+		return fmt.Errorf("failed to create logs bundle %s: %w", bundlePath, err)
+	}
+	if len(hostErrs) > 0 {
+		return fmt.Errorf("logs bundle saved at %s without the logs of some node(s): %w", bundlePath, err)
+	}
+	ux.Logger.PrintToUser("Logs bundle saved at %s", bundlePath)
+	return nil
+}
+
+func printLogs(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	if logsBundle != "" && logsFollow {
+		return errors.New("--follow can't be used together with --bundle")
+	}
+	if logsLines <= 0 {
+		return errors.New("--lines must be greater than 0")
+	}
+	if logsSince < 0 {
+		return errors.New("--since can't be negative")
+	}
+	if err := updateAnsiblePublicIPs(clusterName); err != nil {
+		return err
+	}
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	chainLogFile, err := getChainLogFile(clustersConfig.Clusters[clusterName].Network, logsChain)
+	if err != nil {
+		return err
+	}
+	hosts, err := ansible.GetHostMapfromAnsibleInventory(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	hostIDs, err := getClusterHostIDs(clusterName, logsNode)
+	if err != nil {
+		return err
+	}
+	if logsBundle != "" {
+		return bundleLogs(hostIDs, hosts, chainLogFile, logsBundle)
+	}
+	return streamLogs(hostIDs, hosts, chainLogFile)
+}
@@ -0,0 +1,527 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	awsAPI "github.com/ava-labs/avalanche-cli/pkg/aws"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	gcpAPI "github.com/ava-labs/avalanche-cli/pkg/gcp"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/monitoring"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
+	"github.com/ava-labs/avalanche-cli/pkg/terraform"
+	terraformaws "github.com/ava-labs/avalanche-cli/pkg/terraform/aws"
+	terraformgcp "github.com/ava-labs/avalanche-cli/pkg/terraform/gcp"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+const grafanaAdminUser = "admin"
+
+var monitoringHost string
+
+// monitoringPortRule allows access from [IP] to [Port] on a security group, or GCP network
+type monitoringPortRule struct {
+	IP   string
+	Port int
+}
+
+func newMonitorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "monitor",
+		Short: "(ALPHA Warning) Suite of commands to monitor a cluster with Prometheus and Grafana",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node monitor command suite sets up a Prometheus and Grafana monitoring
+stack for the nodes of a cluster, and gives access to its dashboards.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+	// node monitor setup
+	cmd.AddCommand(newMonitorSetupCmd())
+	// node monitor url
+	cmd.AddCommand(newMonitorURLCmd())
+	return cmd
+}
+
+func newMonitorSetupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "setup [clusterName]",
+		Short: "(ALPHA Warning) Set up Prometheus and Grafana to monitor a cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node monitor setup command installs node_exporter on all nodes of a cluster,
+enables the avalanchego metrics API, and sets up Prometheus and Grafana on a
+monitoring host, with avalanchego and subnet-evm dashboards preloaded.
+
+By default, a dedicated monitoring instance is created on the cloud service and
+region of the cluster. Use --monitoring-host to run Prometheus and Grafana on
+one of the cluster nodes instead, which is required for clusters of existing
+servers. Grafana is only reachable from your current IP address.
+
+Run the command again after adding or removing nodes, to update the Prometheus
+scrape targets.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         setupMonitoring,
+	}
+	cmd.Flags().StringVar(&monitoringHost, "monitoring-host", "", "run Prometheus and Grafana on the given cluster node, by cloud instance ID or Avalanche NodeID")
+	cmd.Flags().BoolVar(&authorizeAccess, "authorize-access", false, "authorize CLI to create the monitoring instance and open its ports")
+	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
+	cmd.Flags().BoolVar(&useStaticIP, "use-static-ip", true, "attach static Public IP on the monitoring instance")
+	return cmd
+}
+
+func newMonitorURLCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "url [clusterName]",
+		Short: "(ALPHA Warning) Print the Grafana dashboards URLs of a cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node monitor url command prints the URLs of the Grafana dashboards set up
+with node monitor setup, and the Grafana admin credentials.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         printMonitoringURL,
+	}
+}
+
+// generateGrafanaAdminPassword returns a random password for the Grafana admin user
+func generateGrafanaAdminPassword() (string, error) {
+	password := make([]byte, 16)
+	if _, err := rand.Read(password); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(password), nil
+}
+
+func getGrafanaAdminPasswordPath(monitoringInstance string) string {
+	return filepath.Join(app.GetNodeInstanceDirPath(monitoringInstance), constants.GrafanaAdminPasswordFileName)
+}
+
+// getGrafanaAdminPassword returns the Grafana admin password saved for [monitoringInstance],
+// generating and saving a new one if there is none
+func getGrafanaAdminPassword(monitoringInstance string) (string, error) {
+	passwordPath := getGrafanaAdminPasswordPath(monitoringInstance)
+	password, err := os.ReadFile(passwordPath)
+	if err == nil {
+		return strings.TrimSpace(string(password)), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	newPassword, err := generateGrafanaAdminPassword()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(passwordPath), constants.DefaultPerms755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(passwordPath, []byte(newPassword), constants.WriteReadUserOnlyPerms); err != nil {
+		return "", err
+	}
+	return newPassword, nil
+}
+
+// getClusterMonitoringInstance returns the monitoring instance of [clusterName], and if it is a
+// dedicated one, not part of the cluster nodes. Returns "" if monitoring is not set up
+func getClusterMonitoringInstance(clusterName string) (string, bool, error) {
+	clustersConfig, err := loadClustersConfigIfExists()
+	if err != nil {
+		return "", false, err
+	}
+	clusterConfig := clustersConfig.Clusters[clusterName]
+	if clusterConfig.MonitoringInstance == "" {
+		return "", false, nil
+	}
+	return clusterConfig.MonitoringInstance, !slices.Contains(clusterConfig.Nodes, clusterConfig.MonitoringInstance), nil
+}
+
+func setClusterMonitoringInstance(clusterName string, monitoringInstance string) error {
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	clusterConfig := clustersConfig.Clusters[clusterName]
+	clusterConfig.MonitoringInstance = monitoringInstance
+	clustersConfig.Clusters[clusterName] = clusterConfig
+	return app.WriteClustersConfigFile(&clustersConfig)
+}
+
+// removeClusterMonitoring forgets the monitoring host of [clusterName], after it was removed
+func removeClusterMonitoring(clusterName string) error {
+	if err := os.RemoveAll(app.GetMonitoringInventoryDirPath(clusterName)); err != nil {
+		return err
+	}
+	return setClusterMonitoringInstance(clusterName, "")
+}
+
+// printMonitoringSetupHint reminds to update the monitoring of [clusterName] after its nodes changed
+func printMonitoringSetupHint(clusterName string) {
+	if monitoringInstance, _, err := getClusterMonitoringInstance(clusterName); err == nil && monitoringInstance != "" {
+		ux.Logger.PrintToUser("Run avalanche node monitor setup %s to update the monitored nodes", clusterName)
+	}
+}
+
+// getMonitoringHost returns the inventory record of the monitoring host [monitoringInstance]
+func getMonitoringHost(clusterName string, monitoringInstance string, dedicated bool) (models.Host, error) {
+	nodeConfig, err := app.LoadClusterNodeConfig(monitoringInstance)
+	if err != nil {
+		return models.Host{}, err
+	}
+	ansibleHostID, err := models.HostCloudIDToAnsibleID(getNodeCloudService(nodeConfig), monitoringInstance)
+	if err != nil {
+		return models.Host{}, err
+	}
+	inventoryPath := app.GetAnsibleInventoryDirPath(clusterName)
+	if dedicated {
+		inventoryPath = app.GetMonitoringInventoryDirPath(clusterName)
+	}
+	hosts, err := ansible.GetHostMapfromAnsibleInventory(inventoryPath)
+	if err != nil {
+		return models.Host{}, err
+	}
+	host, ok := hosts[ansibleHostID]
+	if !ok {
+		return models.Host{}, fmt.Errorf("monitoring host %s not found in inventory %s", ansibleHostID, inventoryPath)
+	}
+	return host, nil
+}
+
+// createMonitoringInstance creates a dedicated cloud instance to run Prometheus and Grafana for
+// [clusterName], on the same cloud service and region of [nodeConfig]. The instance is kept out
+// of the cluster nodes, in its own inventory
+func createMonitoringInstance(clusterName string, network models.Network, nodeConfig models.NodeConfig) (string, error) {
+	cloudService := getNodeCloudService(nodeConfig)
+	numNodes = 1
+	cmdLineRegion = nodeConfig.Region
+	serverConfig := getDefaultCloudServerConfig(cloudService, constants.ArchAMD64)
+	serverConfig.DiskSize = constants.MonitoringServerStorageSize
+	serverConfig.InstanceType = constants.AWSDefaultMonitoringInstanceType
+	if cloudService == constants.GCPCloudService {
+		serverConfig.InstanceType = constants.GCPDefaultMonitoringInstanceType
+	}
+	ux.Logger.PrintToUser("Creating monitoring instance (%s) ...", serverConfig)
+	cloudConfig, publicIPMap, err := createCloudNodes(network, clusterName, cloudService, serverConfig)
+	if err != nil {
+		return "", err
+	}
+	monitoringInstance := cloudConfig.InstanceIDs[0]
+	ansibleHostID, err := models.HostCloudIDToAnsibleID(cloudService, monitoringInstance)
+	if err != nil {
+		return "", err
+	}
+	hosts, err := ansible.GetHostMapfromAnsibleInventory(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return "", err
+	}
+	host, ok := hosts[ansibleHostID]
+	if !ok {
+		return "", fmt.Errorf("monitoring instance %s with IP %s not found in cluster inventory", monitoringInstance, publicIPMap[monitoringInstance])
+	}
+	if err := ansible.RemoveHostFromAnsibleInventory(app.GetAnsibleInventoryDirPath(clusterName), ansibleHostID); err != nil {
+		return "", err
+	}
+	if err := removeNodeFromCluster(clusterName, monitoringInstance); err != nil {
+		return "", err
+	}
+	if err := ansible.AddHostsToAnsibleInventory(app.GetMonitoringInventoryDirPath(clusterName), []models.Host{host}); err != nil {
+		return "", err
+	}
+	return monitoringInstance, nil
+}
+
+// getMonitoringPortRules returns the rules to add to the security groups, or GCP networks, of
+// [nodeConfigs], keyed by cloud service and region, and by security group: access from the user to
+// Grafana on the monitoring host, and from the monitoring host to node_exporter on the nodes
+func getMonitoringPortRules(
+	monitoringNodeConfig models.NodeConfig,
+	monitoringIP string,
+	userIP string,
+	nodeConfigs []models.NodeConfig,
+) map[[2]string]map[string][]monitoringPortRule {
+	rules := map[[2]string]map[string][]monitoringPortRule{}
+	addRule := func(nodeConfig models.NodeConfig, rule monitoringPortRule) {
+		cloudService := getNodeCloudService(nodeConfig)
+		if cloudService != constants.AWSCloudService && cloudService != constants.GCPCloudService {
+			return
+		}
+		key := [2]string{cloudService, nodeConfig.Region}
+		if rules[key] == nil {
+			rules[key] = map[string][]monitoringPortRule{}
+		}
+		if !slices.Contains(rules[key][nodeConfig.SecurityGroup], rule) {
+			rules[key][nodeConfig.SecurityGroup] = append(rules[key][nodeConfig.SecurityGroup], rule)
+		}
+	}
+	addRule(monitoringNodeConfig, monitoringPortRule{IP: userIP, Port: constants.GrafanaPort})
+	for _, nodeConfig := range nodeConfigs {
+		addRule(nodeConfig, monitoringPortRule{IP: monitoringIP, Port: constants.NodeExporterPort})
+	}
+	return rules
+}
+
+// openMonitoringPorts adds the firewall rules needed by the monitoring stack of [clusterName]
+// that are not already in place. Each cloud service and region gets its own terraform state,
+// so node destroy removes the rules together with the cluster
+func openMonitoringPorts(clusterName string, rules map[[2]string]map[string][]monitoringPortRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	if err := terraform.CheckIsInstalled(); err != nil {
+		return err
+	}
+	keys := make([][2]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i][0]+keys[i][1] < keys[j][0]+keys[j][1] })
+	for _, key := range keys {
+		cloudService, region := key[0], key[1]
+		hclFile, rootBody, err := terraform.InitConf()
+		if err != nil {
+			return err
+		}
+		addedRules := 0
+		if cloudService == constants.AWSCloudService {
+			if err := terraformaws.SetCloudCredentials(rootBody, awsProfile, region); err != nil {
+				return err
+			}
+			sess, err := getAWSCloudCredentials(awsProfile, region, constants.CreateAWSNode, authorizeAccess)
+			if err != nil {
+				return err
+			}
+			ec2Svc := ec2.New(sess)
+			for securityGroupName, sgRules := range rules[key] {
+				sgExists, sg, err := awsAPI.CheckSecurityGroupExists(ec2Svc, securityGroupName)
+				if err != nil {
+					return err
+				}
+				if !sgExists {
+					return fmt.Errorf("security group %s not found in region %s", securityGroupName, region)
+				}
+				for _, rule := range sgRules {
+					if awsAPI.CheckUserIPInSg(sg, rule.IP, int64(rule.Port)) {
+						continue
+					}
+					terraformaws.SetSecurityGroupPortRule(rootBody, rule.IP, *sg.GroupId, int64(rule.Port))
+					addedRules++
+				}
+			}
+		} else {
+			gcpClient, projectName, credentialsPath, err := getGCPCloudCredentials()
+			if err != nil {
+				return err
+			}
+			if err := terraformgcp.SetCloudCredentials(rootBody, region, credentialsPath, projectName); err != nil {
+				return err
+			}
+			for networkName, networkRules := range rules[key] {
+				networkRuleAdded := false
+				for _, rule := range networkRules {
+					firewallName := fmt.Sprintf("%s-%s-%d", networkName, strings.ReplaceAll(rule.IP, ".", ""), rule.Port)
+					firewallExists, err := gcpAPI.CheckFirewallExists(gcpClient, projectName, firewallName)
+					if err != nil {
+						return err
+					}
+					if firewallExists {
+						continue
+					}
+					if !networkRuleAdded {
+						terraformgcp.SetExistingNetwork(rootBody, networkName)
+						networkRuleAdded = true
+					}
+					terraformgcp.SetFirewallRule(rootBody, rule.IP+"/32", firewallName, networkName, []string{strconv.Itoa(rule.Port)}, true)
+					addedRules++
+				}
+			}
+		}
+		if addedRules == 0 {
+			continue
+		}
+		ux.Logger.PrintToUser("Opening monitoring ports on %s %s ...", cloudService, region)
+		terraformDir := filepath.Join(app.GetClusterTerraformDir(clusterName), fmt.Sprintf("%s-monitoring-%s", time.Now().UTC().Format("20060102150405"), region))
+		if err := app.CreateTerraformDir(terraformDir); err != nil {
+			return err
+		}
+		if err := terraform.SaveConf(terraformDir, hclFile); err != nil {
+			return err
+		}
+		if err := terraform.Apply(terraformDir); err != nil {
+			return fmt.Errorf("failed to open monitoring ports on %s %s: %w", cloudService, region, err)
+		}
+	}
+	return nil
+}
+
+func setupMonitoring(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	clusterNodes, err := getClusterNodes(clusterName)
+	if err != nil {
+		return err
+	}
+	isLocal, err := isLocalMachineCluster(clusterName)
+	if err != nil {
+		return err
+	}
+	if isLocal {
+		return errors.New("monitoring is not supported for node/s running on the local machine")
+	}
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	network := clustersConfig.Clusters[clusterName].Network
+	nodeConfigs := []models.NodeConfig{}
+	hasExistingHosts := false
+	for _, node := range clusterNodes {
+		nodeConfig, err := app.LoadClusterNodeConfig(node)
+		if err != nil {
+			return err
+		}
+		if nodeConfig.CloudService == constants.ExistingHostsService {
+			hasExistingHosts = true
+		}
+		nodeConfigs = append(nodeConfigs, nodeConfig)
+	}
+	if err := updateAnsiblePublicIPs(clusterName); err != nil {
+		return err
+	}
+
+	monitoringInstance, dedicated, err := getClusterMonitoringInstance(clusterName)
+	if err != nil {
+		return err
+	}
+	switch {
+	case monitoringHost != "":
+		node, err := getClusterNode(clusterName, monitoringHost)
+		if err != nil {
+			return err
+		}
+		if dedicated && monitoringInstance != node {
+			ux.Logger.PrintToUser("Monitoring instance %s of cluster %s is not used anymore, remove it with avalanche node destroy %s or on the cloud console", monitoringInstance, clusterName, clusterName)
+		}
+		monitoringInstance = node
+		dedicated = false
+	case monitoringInstance != "":
+		ux.Logger.PrintToUser("Using monitoring host %s of cluster %s", monitoringInstance, clusterName)
+	case hasExistingHosts:
+		return errors.New("--monitoring-host is required to set up monitoring on a cluster of existing servers")
+	default:
+		monitoringInstance, err = createMonitoringInstance(clusterName, network, nodeConfigs[0])
+		if err != nil {
+			return err
+		}
+		dedicated = true
+	}
+	if err := setClusterMonitoringInstance(clusterName, monitoringInstance); err != nil {
+		return err
+	}
+	host, err := getMonitoringHost(clusterName, monitoringInstance, dedicated)
+	if err != nil {
+		return err
+	}
+	monitoringNodeConfig, err := app.LoadClusterNodeConfig(monitoringInstance)
+	if err != nil {
+		return err
+	}
+
+	userIPAddress, err := getIPAddress()
+	if err != nil {
+		return err
+	}
+	rules := getMonitoringPortRules(monitoringNodeConfig, host.IP, userIPAddress, nodeConfigs)
+	if err := openMonitoringPorts(clusterName, rules); err != nil {
+		return err
+	}
+	if hasExistingHosts {
+		ux.Logger.PrintToUser("Please make sure that port %d of %s is reachable from your IP address, and port %d of the existing servers from %s",
+			constants.GrafanaPort, host.IP, constants.NodeExporterPort, host.IP)
+	}
+
+	clusterHosts, err := ansible.GetHostMapfromAnsibleInventory(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	hosts := []models.Host{}
+	targets := map[string]string{}
+	for hostID, clusterHost := range clusterHosts {
+		hosts = append(hosts, clusterHost)
+		targets[hostID] = clusterHost.IP
+	}
+	ux.Logger.PrintToUser("Installing node_exporter and enabling avalanchego metrics on cluster %s nodes ...", clusterName)
+	if _, err := ssh.RunOnHosts(hosts, func(c *ssh.Client) (struct{}, error) {
+		return struct{}{}, ssh.RunSSHSetupNodeExporter(c)
+	}); err != nil {
+		return err
+	}
+
+	monitoringDir, err := os.MkdirTemp("", constants.MonitoringDir)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(monitoringDir)
+	fileNames, err := monitoring.WriteMonitoringFiles(monitoringDir, targets)
+	if err != nil {
+		return err
+	}
+	grafanaAdminPassword, err := getGrafanaAdminPassword(monitoringInstance)
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Installing Prometheus and Grafana on monitoring host %s ...", host.IP)
+	if _, err := ssh.RunOnHosts([]models.Host{host}, func(c *ssh.Client) (struct{}, error) {
+		return struct{}{}, ssh.RunSSHSetupMonitoring(c, monitoringDir, fileNames, grafanaAdminPassword)
+	}); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Monitoring of cluster %s successfully set up!", clusterName)
+	return printMonitoringURL(nil, args)
+}
+
+func printMonitoringURL(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	monitoringInstance, dedicated, err := getClusterMonitoringInstance(clusterName)
+	if err != nil {
+		return err
+	}
+	if monitoringInstance == "" {
+		return fmt.Errorf("monitoring is not set up for cluster %s, use avalanche node monitor setup %s", clusterName, clusterName)
+	}
+	host, err := getMonitoringHost(clusterName, monitoringInstance, dedicated)
+	if err != nil {
+		return err
+	}
+	password, err := os.ReadFile(getGrafanaAdminPasswordPath(monitoringInstance))
+	if err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Grafana dashboards of cluster %s:", clusterName)
+	for _, uid := range monitoring.DashboardUIDs {
+		ux.Logger.PrintToUser("  http://%s:%d/d/%s", host.IP, constants.GrafanaPort, uid)
+	}
+	ux.Logger.PrintToUser("Grafana user: %s", grafanaAdminUser)
+	ux.Logger.PrintToUser("Grafana password: %s", strings.TrimSpace(string(password)))
+	return nil
+}
@@ -30,6 +30,7 @@ rest of the commands to maintain your node and make your node a Subnet Validator
 		},
 	}
 	app = injectedApp
+	cmd.PersistentFlags().BoolVar(&useAnsible, "use-ansible", false, "run node operations with ansible playbooks instead of the native ssh client")
 	// node create
 	cmd.AddCommand(newCreateCmd())
 	// node validate
@@ -50,5 +51,31 @@ rest of the commands to maintain your node and make your node a Subnet Validator
 	cmd.AddCommand(newUpgradeCmd())
 	// node ssh
 	cmd.AddCommand(newSSHCmd())
+	// node ssh-config
+	cmd.AddCommand(newSSHConfigCmd())
+	// node scp
+	cmd.AddCommand(newSCPCmd())
+	// node add
+	cmd.AddCommand(newAddCmd())
+	// node remove
+	cmd.AddCommand(newRemoveCmd())
+	// node destroy
+	cmd.AddCommand(newDestroyCmd())
+	// node monitor
+	cmd.AddCommand(newMonitorCmd())
+	// node logs
+	cmd.AddCommand(newLogsCmd())
+	// node config
+	cmd.AddCommand(newConfigCmd())
+	// node import-identity
+	cmd.AddCommand(newImportIdentityCmd())
+	// node backup
+	cmd.AddCommand(newBackupCmd())
+	// node restore
+	cmd.AddCommand(newRestoreCmd())
+	// node health
+	cmd.AddCommand(newHealthCmd())
+	// node validators
+	cmd.AddCommand(newValidatorsCmd())
 	return cmd
 }
@@ -0,0 +1,164 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	awsAPI "github.com/ava-labs/avalanche-cli/pkg/aws"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	gcpAPI "github.com/ava-labs/avalanche-cli/pkg/gcp"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+func newRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove [clusterName] [nodeID]",
+		Short: "(ALPHA Warning) Remove a node from a cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node remove command terminates a single node of a cluster, releases its
+static IP, and removes it from the cluster inventory, leaving the other nodes
+untouched. The node can be given either by its cloud instance ID or by its
+Avalanche NodeID.
+
+Nodes set up on existing servers, or on the local machine, only have their
+avalanchego stopped.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(2),
+		RunE:         removeNode,
+	}
+	cmd.Flags().BoolVar(&authorizeAccess, "authorize-access", false, "authorize CLI to release cloud resources")
+	cmd.Flags().BoolVar(&authorizeRemove, "authorize-remove", false, "authorize CLI to remove all local files related to the node")
+	cmd.Flags().StringVar(&awsProfile, "aws-profile", constants.AWSDefaultCredential, "aws profile to use")
+	return cmd
+}
+
+// getClusterNode finds the node of [clusterName] with the given cloud instance ID or
+// Avalanche NodeID
+func getClusterNode(clusterName string, nodeToFind string) (string, error) {
+	clusterNodes, err := getClusterNodes(clusterName)
+	if err != nil {
+		return "", err
+	}
+	if slices.Contains(clusterNodes, nodeToFind) {
+		return nodeToFind, nil
+	}
+	for _, node := range clusterNodes {
+		nodeID, err := getNodeID(app.GetNodeInstanceDirPath(node))
+		if err != nil {
+			continue
+		}
+		if nodeID.String() == nodeToFind {
+			return node, nil
+		}
+	}
+	return "", fmt.Errorf("node %s not found in cluster %s", nodeToFind, clusterName)
+}
+
+func removeNodeFromCluster(clusterName string, node string) error {
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	clusterConfig := clustersConfig.Clusters[clusterName]
+	if i := slices.Index(clusterConfig.Nodes, node); i != -1 {
+		clusterConfig.Nodes = slices.Delete(clusterConfig.Nodes, i, i+1)
+	}
+	clustersConfig.Clusters[clusterName] = clusterConfig
+	return app.WriteClustersConfigFile(&clustersConfig)
+}
+
+func removeNode(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	node, err := getClusterNode(clusterName, args[1])
+	if err != nil {
+		return err
+	}
+	clusterNodes, err := getClusterNodes(clusterName)
+	if err != nil {
+		return err
+	}
+	if len(clusterNodes) == 1 {
+		return fmt.Errorf("node %s is the only node of cluster %s, use avalanche node stop %s instead", node, clusterName, clusterName)
+	}
+	if !authorizeRemove {
+		ux.Logger.PrintToUser("Please note that if your node is validating a Subnet, removing it could cause Subnet instability and it is irreversible")
+		yes, err := app.Prompt.CaptureYesNo(fmt.Sprintf("Running this command will delete all stored files associated with node %s. Do you want to proceed? "+
+			"Stored files can be found at %s", node, app.GetNodeInstanceDirPath(node)))
+		if err != nil {
+			return err
+		}
+		if !yes {
+			return errors.New("abort avalanche node remove command")
+		}
+	}
+	nodeConfig, err := app.LoadClusterNodeConfig(node)
+	if err != nil {
+		return err
+	}
+	cloudService := getNodeCloudService(nodeConfig)
+	ansibleHostID, err := models.HostCloudIDToAnsibleID(cloudService, node)
+	if err != nil {
+		return err
+	}
+	switch cloudService {
+	case constants.ExistingHostsService, constants.LocalMachineService:
+		// servers not created by the CLI are kept, only avalanchego is stopped
+		if err := setupNodeExecutor(clusterName); err != nil {
+			return err
+		}
+		if err := nodeExecutor.StopNode(ansibleHostID); err != nil {
+			return err
+		}
+	case constants.GCPCloudService:
+		gcpClient, gcpProjectName, _, err := getGCPCloudCredentials()
+		if err != nil {
+			return err
+		}
+		if err := gcpAPI.TerminateGCPNode(gcpClient, nodeConfig, gcpProjectName, clusterName); err != nil {
+			return err
+		}
+	default:
+		sess, err := getAWSCloudCredentials(awsProfile, nodeConfig.Region, constants.StopAWSNode, authorizeAccess)
+		if err != nil {
+			return err
+		}
+		if err := awsAPI.TerminateAWSNode(ec2.New(sess), nodeConfig, clusterName); err != nil {
+			return err
+		}
+	}
+	if cloudService == constants.AWSCloudService || cloudService == constants.GCPCloudService {
+		if err := forgetNodeTerraformState(clusterName, nodeConfig); err != nil {
+			return err
+		}
+	}
+	if err := ansible.RemoveHostFromAnsibleInventory(app.GetAnsibleInventoryDirPath(clusterName), ansibleHostID); err != nil {
+		return err
+	}
+	if err := removeNodeFromCluster(clusterName, node); err != nil {
+		return err
+	}
+	monitoringInstance, _, err := getClusterMonitoringInstance(clusterName)
+	if err != nil {
+		return err
+	}
+	if monitoringInstance == node {
+		if err := removeClusterMonitoring(clusterName); err != nil {
+			return err
+		}
+		ux.Logger.PrintToUser("Node %s was the monitoring host of cluster %s, use avalanche node monitor setup %s to set up monitoring again", node, clusterName, clusterName)
+	}
+	if err := removeDeletedNodeDirectory(node); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Node %s successfully removed from cluster %s!", node, clusterName)
+	printMonitoringSetupHint(clusterName)
+	return nil
+}
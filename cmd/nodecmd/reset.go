@@ -0,0 +1,157 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resetRedeploySubnets bool
+	resetForce           bool
+	resetHealthTimeout   time.Duration
+)
+
+func newResetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reset [clusterName]",
+		Short: "(ALPHA Warning) Reset a devnet cluster to a new genesis",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node devnet reset command restarts the devnet of a cluster from scratch,
+keeping its instances, IPs and NodeIDs. It stops avalanchego on every node,
+deletes its db and chain data, generates a new genesis with a new start time,
+using the genesis spec the devnet was created with, and restarts the nodes.
+
+If --redeploy-subnets is set, the subnets that were deployed into the devnet
+and being synced by its nodes are deployed again into the new devnet, and the
+nodes are synced with them. Subnet validators must be added again afterwards.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         resetDevnet,
+	}
+	cmd.Flags().BoolVar(&resetRedeploySubnets, "redeploy-subnets", false, "deploy again the subnets previously deployed into the devnet, and sync the nodes with them")
+	cmd.Flags().BoolVar(&resetForce, "force", false, "don't ask for confirmation before resetting the devnet")
+	cmd.Flags().DurationVar(&resetHealthTimeout, "health-timeout", 10*time.Minute, "time to wait for the nodes to become healthy before redeploying subnets")
+	return cmd
+}
+
+// getDeployedDevnetSubnets returns the subnets known to the CLI that are deployed into the devnet,
+// that is, whose blockchain is being synced or validated by any of [hostIDs]
+func getDeployedDevnetSubnets(hostIDs []string) ([]string, error) {
+	subnetNames, err := app.GetSidecarNames()
+	if err != nil {
+		return nil, err
+	}
+	deployedSubnets := []string{}
+	for _, subnetName := range subnetNames {
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return nil, err
+		}
+		blockchainID := sc.Networks[models.DevnetNetwork.Name()].BlockchainID
+		if blockchainID == ids.Empty {
+			continue
+		}
+		syncStatus, err := nodeExecutor.GetSubnetSyncStatus(hostIDs, blockchainID.String())
+		if err != nil {
+			return nil, err
+		}
+		for _, hostID := range hostIDs {
+			if syncStatus[hostID] == status.Syncing.String() || syncStatus[hostID] == status.Validating.String() {
+				deployedSubnets = append(deployedSubnets, subnetName)
+				break
+			}
+		}
+	}
+	sort.Strings(deployedSubnets)
+	return deployedSubnets, nil
+}
+
+func resetDevnet(cmd *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	if clustersConfig.Clusters[clusterName].Network.Kind != models.Devnet {
+		return fmt.Errorf("node devnet reset command must be applied to devnet clusters")
+	}
+	spec, err := loadClusterGenesisSpec(clusterName)
+	if err != nil {
+		return err
+	}
+	if err := setupNodeExecutor(clusterName); err != nil {
+		return err
+	}
+	hostIDs, err := getClusterHostIDs(clusterName, "")
+	if err != nil {
+		return err
+	}
+	subnetsToRedeploy := []string{}
+	if resetRedeploySubnets {
+		subnetsToRedeploy, err = getDeployedDevnetSubnets(hostIDs)
+		if err != nil {
+			return fmt.Errorf("failed to get the subnets deployed into the devnet: %w", err)
+		}
+		if len(subnetsToRedeploy) == 0 {
+			ux.Logger.PrintToUser("No subnet deployed into the devnet was found")
+		} else {
+			ux.Logger.PrintToUser("Subnets to redeploy after the reset: %s", strings.Join(subnetsToRedeploy, ", "))
+		}
+	}
+	if !resetForce {
+		yes, err := app.Prompt.CaptureYesNo(fmt.Sprintf("All the chain state of the devnet of cluster %s will be lost. Do you want to proceed?", clusterName))
+		if err != nil {
+			return err
+		}
+		if !yes {
+			return errors.New("abort avalanche node devnet reset command")
+		}
+	}
+	ux.Logger.PrintToUser("Resetting devnet of cluster %s ...", clusterName)
+	if err := setupDevnet(clusterName, spec); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Devnet of cluster %s successfully reset!", clusterName)
+	if len(subnetsToRedeploy) == 0 {
+		return nil
+	}
+	ux.Logger.PrintToUser("Waiting for the nodes to be healthy ...")
+	if unhealthyNodes := waitForNodesHealthy(hostIDs, nil, resetHealthTimeout); len(unhealthyNodes) > 0 {
+		return fmt.Errorf("node(s) %s not healthy after %s, subnets %s were not redeployed", unhealthyNodes, resetHealthTimeout, strings.Join(subnetsToRedeploy, ", "))
+	}
+	for _, subnetName := range subnetsToRedeploy {
+		ux.Logger.PrintToUser("Redeploying subnet %s ...", subnetName)
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return err
+		}
+		// the previous deploy info refers to the old devnet, so deploy creates new subnet and blockchain txs
+		delete(sc.Networks, models.DevnetNetwork.Name())
+		if err := app.UpdateSidecar(&sc); err != nil {
+			return err
+		}
+		if err := deploySubnet(cmd, []string{clusterName, subnetName}); err != nil {
+			return fmt.Errorf("failed to redeploy subnet %s: %w", subnetName, err)
+		}
+		if err := syncSubnet(cmd, []string{clusterName, subnetName}); err != nil {
+			return fmt.Errorf("failed to sync nodes with subnet %s: %w", subnetName, err)
+		}
+	}
+	ux.Logger.PrintToUser("Subnets redeployed. Use avalanche node validate subnet to add their validators again")
+	return nil
+}
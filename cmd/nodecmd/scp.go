@@ -0,0 +1,122 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// remotePathPrefix marks the scp paths that refer to the nodes
+const remotePathPrefix = ":"
+
+func newSCPCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scp [clusterName[:nodeID]] [source] [destination]",
+		Short: "(ALPHA Warning) Copy files to or from the nodes of a cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node scp command copies files or dirs to or from all the nodes of a cluster,
+or only the one given after the cluster name, by its cloud instance ID or its
+Avalanche NodeID. Copies to all nodes are run in parallel, with the same ssh
+params as node ssh.
+
+Paths on the nodes are prefixed with ':'. For example:
+  avalanche node scp mycluster ./file.txt :/home/ubuntu/
+  avalanche node scp mycluster:i-0123 :/home/ubuntu/.avalanchego/logs ./logs
+When copying from more than one node, each node's files are copied into a subdir
+of the destination named after the node.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(3),
+		RunE:         scpNodes,
+	}
+	return cmd
+}
+
+// getNodeLocalPath returns the local path of [path] in the node dir of [cloudID], for nodes
+// running on the local machine
+func getNodeLocalPath(cloudID string, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(app.GetNodeInstanceDirPath(cloudID), path)
+}
+
+func scpNodes(_ *cobra.Command, args []string) error {
+	clusterName, node, _ := strings.Cut(args[0], ":")
+	src := args[1]
+	dst := args[2]
+	isDownload := strings.HasPrefix(src, remotePathPrefix)
+	if isDownload == strings.HasPrefix(dst, remotePathPrefix) {
+		return errors.New("exactly one of source and destination must be a path on the nodes, prefixed with ':'")
+	}
+	if isDownload {
+		src = strings.TrimPrefix(src, remotePathPrefix)
+	} else {
+		dst = strings.TrimPrefix(dst, remotePathPrefix)
+	}
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	if err := updateAnsiblePublicIPs(clusterName); err != nil {
+		return err
+	}
+	hostIDs, err := getClusterHostIDs(clusterName, node)
+	if err != nil {
+		return err
+	}
+	ansibleHosts, err := ansible.GetHostMapfromAnsibleInventory(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	nodes := []string{}
+	cmdLines := map[string]string{}
+	cmds := map[string]*exec.Cmd{}
+	for _, hostID := range hostIDs {
+		cloudService, cloudID, err := models.HostAnsibleIDToCloudID(hostID)
+		if err != nil {
+			return err
+		}
+		host := ansibleHosts[hostID]
+		nodeSrc, nodeDst := src, dst
+		if isDownload && len(hostIDs) > 1 {
+			nodeDst = filepath.Join(dst, cloudID)
+			if err := os.MkdirAll(nodeDst, constants.DefaultPerms755); err != nil {
+				return err
+			}
+		}
+		var cmd *exec.Cmd
+		if cloudService == constants.LocalMachineService {
+			// nodes on the local machine don't need ssh, their paths are relative to the node dir
+			if isDownload {
+				nodeSrc = getNodeLocalPath(cloudID, nodeSrc)
+			} else {
+				nodeDst = getNodeLocalPath(cloudID, nodeDst)
+			}
+			cmd = exec.Command("cp", "-r", nodeSrc, nodeDst) //nolint: gosec
+		} else {
+			remoteHost := fmt.Sprintf("%s@%s:", host.SSHUser, host.IP)
+			if isDownload {
+				nodeSrc = remoteHost + nodeSrc
+			} else {
+				nodeDst = remoteHost + nodeDst
+			}
+			cmd = exec.Command("scp", utils.GetSCPCommandArgs(host.SSHPrivateKeyPath, nodeSrc, nodeDst)...) //nolint: gosec
+		}
+		cmd.Env = os.Environ()
+		nodes = append(nodes, cloudID)
+		cmdLines[cloudID] = strings.Join(cmd.Args, " ")
+		cmds[cloudID] = cmd
+	}
+	return printNodeCommandResults(nodes, cmdLines, runNodeCommands(cmds), "")
+}
@@ -3,12 +3,15 @@
 package nodecmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
 
 	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
@@ -22,6 +25,9 @@ func newSSHCmd() *cobra.Command {
 		Long: `(ALPHA Warning) This command is currently in experimental mode.
 
 The node ssh command execute a given command using ssh on all nodes in the cluster.
+The command is run on all nodes in parallel, and its output and exit code are shown
+grouped by node. If it fails on any node, node ssh exits with the exit code of the
+first failing node.
 If no command is given, just prints the ssh cmdLine to be used to connect to each node.
 `,
 		SilenceUsage: true,
@@ -58,12 +64,94 @@ func sshNode(_ *cobra.Command, args []string) error {
 	return sshCluster(args, "")
 }
 
+// nodeCommandResult is the output and exit code of a command run for a node. Err is set when
+// the command couldn't be run
+type nodeCommandResult struct {
+	Output   []byte
+	ExitCode int
+	Err      error
+}
+
+// nodeCommandError is returned when a command failed for some nodes, so the CLI exits with
+// the exit code of the first one
+type nodeCommandError struct {
+	failedNodes []string
+	exitCode    int
+}
+
+func (e nodeCommandError) Error() string {
+	return fmt.Sprintf("command failed on node(s) %s", strings.Join(e.failedNodes, ", "))
+}
+
+func (e nodeCommandError) ExitCode() int {
+	return e.exitCode
+}
+
+// runNodeCommands runs [cmds], keyed by node, in parallel, capturing their combined output
+func runNodeCommands(cmds map[string]*exec.Cmd) map[string]nodeCommandResult {
+	var (
+		lock    sync.Mutex
+		wg      sync.WaitGroup
+		results = map[string]nodeCommandResult{}
+	)
+	for node, cmd := range cmds {
+		wg.Add(1)
+		go func(node string, cmd *exec.Cmd) {
+			defer wg.Done()
+			output, err := cmd.CombinedOutput()
+			result := nodeCommandResult{Output: output}
+			var exitErr *exec.ExitError
+			switch {
+			case errors.As(err, &exitErr):
+				result.ExitCode = exitErr.ExitCode()
+			case err != nil:
+				result.ExitCode = 1
+				result.Err = err
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			results[node] = result
+		}(node, cmd)
+	}
+	wg.Wait()
+	return results
+}
+
+// printNodeCommandResults prints the output and exit code of the commands run for [nodes],
+// grouped by node, and returns a nodeCommandError if any of them failed
+func printNodeCommandResults(nodes []string, cmdLines map[string]string, results map[string]nodeCommandResult, indent string) error {
+	failedNodes := []string{}
+	exitCode := 0
+	for _, node := range nodes {
+		result := results[node]
+		ux.Logger.PrintToUser("%s[%s] %s", indent, node, cmdLines[node])
+		if len(result.Output) > 0 {
+			ux.Logger.PrintToUser("%s", strings.TrimRight(string(result.Output), "\n"))
+		}
+		if result.Err != nil {
+			ux.Logger.PrintToUser("Error: %s", result.Err)
+		}
+		ux.Logger.PrintToUser("%s[%s] exit code: %d", indent, node, result.ExitCode)
+		ux.Logger.PrintToUser("")
+		if result.ExitCode != 0 {
+			if len(failedNodes) == 0 {
+				exitCode = result.ExitCode
+			}
+			failedNodes = append(failedNodes, node)
+		}
+	}
+	if len(failedNodes) > 0 {
+		return nodeCommandError{failedNodes: failedNodes, exitCode: exitCode}
+	}
+	return nil
+}
+
 func sshCluster(args []string, indent string) error {
 	clusterName := args[0]
 	if err := checkCluster(clusterName); err != nil {
 		return err
 	}
-	if err := setupAnsible(clusterName); err != nil {
+	if err := setupNodeExecutor(clusterName); err != nil {
 		return err
 	}
 	ansibleHostIDs, err := ansible.GetAnsibleHostsFromInventory(app.GetAnsibleInventoryDirPath(clusterName))
@@ -74,27 +162,42 @@ func sshCluster(args []string, indent string) error {
 	if err != nil {
 		return err
 	}
+	nodes := []string{}
+	cmdLines := map[string]string{}
+	cmds := map[string]*exec.Cmd{}
 	for _, host := range ansibleHostIDs {
-		_, cloudID, err := models.HostAnsibleIDToCloudID(host)
+		cloudService, cloudID, err := models.HostAnsibleIDToCloudID(host)
 		if err != nil {
 			return err
 		}
-		cmdLine := utils.GetSSHConnectionString(
-			ansibleHosts[host].IP,
-			fmt.Sprintf("%s %s", ansibleHosts[host].SSHPrivateKeyPath, strings.Join(args[1:], " ")),
-		)
-		ux.Logger.PrintToUser("%s[%s] %s", indent, cloudID, cmdLine)
-		if len(args) > 1 {
+		var cmd *exec.Cmd
+		if cloudService == constants.LocalMachineService {
+			// nodes on the local machine don't need ssh, commands are run at the node dir
+			nodeDir := app.GetNodeInstanceDirPath(cloudID)
+			cmdLines[cloudID] = fmt.Sprintf("cd %s", nodeDir)
+			cmd = exec.Command("sh", "-c", strings.Join(args[1:], " ")) //nolint: gosec
+			cmd.Dir = nodeDir
+		} else {
+			cmdLine := utils.GetSSHConnectionString(
+				ansibleHosts[host].SSHUser,
+				ansibleHosts[host].IP,
+				fmt.Sprintf("%s %s", ansibleHosts[host].SSHPrivateKeyPath, strings.Join(args[1:], " ")),
+			)
+			cmdLines[cloudID] = cmdLine
 			splitCmdLine := strings.Split(cmdLine, " ")
-			cmd := exec.Command(splitCmdLine[0], splitCmdLine[1:]...) //nolint: gosec
-			cmd.Env = os.Environ()
-			_, _ = utils.SetupRealtimeCLIOutput(cmd, true, true)
-			err = cmd.Run()
-			if err != nil {
-				ux.Logger.PrintToUser("Error: %s", err)
-			}
-			ux.Logger.PrintToUser("")
+			cmd = exec.Command(splitCmdLine[0], splitCmdLine[1:]...) //nolint: gosec
+		}
+		nodes = append(nodes, cloudID)
+		if len(args) == 1 {
+			ux.Logger.PrintToUser("%s[%s] %s", indent, cloudID, cmdLines[cloudID])
+			continue
 		}
+		cmd.Env = os.Environ()
+		cmds[cloudID] = cmd
 	}
-	return nil
+	if len(args) == 1 {
+		return nil
+	}
+	// commands are run on all nodes at once, and their output is shown grouped by node
+	return printNodeCommandResults(nodes, cmdLines, runNodeCommands(cmds), indent)
 }
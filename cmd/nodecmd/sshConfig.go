@@ -0,0 +1,131 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ava-labs/avalanche-cli/pkg/ansible"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/spf13/cobra"
+)
+
+var sshConfigIncludeFile string
+
+func newSSHConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ssh-config [clusterName]",
+		Short: "(ALPHA Warning) Add ssh config Host entries for the nodes of a cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node ssh-config command writes a ssh config Host entry for each node of a
+cluster, so nodes can be reached with "ssh <instanceID>", "ssh <NodeID>" or by
+any tool using ssh. Each entry is named after both the cloud instance ID and the
+Avalanche NodeID of the node, and uses its public IP, ssh user and key pair.
+
+Host keys are checked against a known hosts file of the cluster, so ssh asks to
+accept the key of each node on first use. This only applies to connections made
+through these entries: other node commands, such as node ssh and node scp, don't
+check host keys.
+
+Entries are merged into ~/.ssh/config, replacing the ones previously written for
+the cluster, so the command can be run again after the IPs of the nodes change.
+With --include-file, entries are written to the given file instead, which is
+included from ~/.ssh/config.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         sshConfig,
+	}
+	cmd.Flags().StringVar(&sshConfigIncludeFile, "include-file", "", "write the entries to this file, and include it from ~/.ssh/config")
+	return cmd
+}
+
+// mergeSSHConfigFile applies [merge] to the content of the ssh config file at [path], creating it if needed
+func mergeSSHConfigFile(path string, merge func(string) string) error {
+	sshConfigBytes, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), constants.UserOnlyWriteReadExecPerms); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(merge(string(sshConfigBytes))), constants.WriteReadUserOnlyPerms)
+}
+
+func sshConfig(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	if err := updateAnsiblePublicIPs(clusterName); err != nil {
+		return err
+	}
+	ansibleHostIDs, err := ansible.GetAnsibleHostsFromInventory(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	ansibleHosts, err := ansible.GetHostMapfromAnsibleInventory(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	knownHostsPath := app.GetClusterSSHKnownHostsPath(clusterName)
+	if err := os.MkdirAll(filepath.Dir(knownHostsPath), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	entries := []string{}
+	aliases := []string{}
+	for _, host := range ansibleHostIDs {
+		cloudService, cloudID, err := models.HostAnsibleIDToCloudID(host)
+		if err != nil {
+			return err
+		}
+		if cloudService == constants.LocalMachineService {
+			continue
+		}
+		hostAliases := []string{cloudID}
+		if nodeID, err := getNodeID(app.GetNodeInstanceDirPath(cloudID)); err == nil {
+			hostAliases = append(hostAliases, nodeID.String())
+		}
+		entries = append(entries, utils.GetSSHConfigHostEntry(
+			hostAliases,
+			ansibleHosts[host].SSHUser,
+			ansibleHosts[host].IP,
+			ansibleHosts[host].SSHPrivateKeyPath,
+			knownHostsPath,
+		))
+		aliases = append(aliases, cloudID)
+	}
+	if len(entries) == 0 {
+		ux.Logger.PrintToUser("Cluster %s has no node reachable by ssh", clusterName)
+		return nil
+	}
+	userSSHConfigPath := utils.UserHomePath(".ssh", "config")
+	entriesPath := userSSHConfigPath
+	if sshConfigIncludeFile != "" {
+		entriesPath, err = filepath.Abs(sshConfigIncludeFile)
+		if err != nil {
+			return err
+		}
+	}
+	if err := mergeSSHConfigFile(entriesPath, func(sshConfig string) string {
+		return utils.MergeSSHConfigBlock(sshConfig, "cluster "+clusterName, strings.Join(entries, ""))
+	}); err != nil {
+		return err
+	}
+	if entriesPath != userSSHConfigPath {
+		if err := mergeSSHConfigFile(userSSHConfigPath, func(sshConfig string) string {
+			return utils.AddSSHConfigInclude(sshConfig, entriesPath)
+		}); err != nil {
+			return err
+		}
+	}
+	ux.Logger.PrintToUser("Wrote ssh config for node(s) %s of cluster %s to %s", strings.Join(aliases, ", "), clusterName, entriesPath)
+	ux.Logger.PrintToUser("Connect to a node with: ssh %s", aliases[0])
+	return nil
+}
@@ -3,6 +3,7 @@
 package nodecmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/ansible"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanchego/ids"
@@ -50,7 +52,7 @@ func statusNode(_ *cobra.Command, args []string) error {
 	if err := checkCluster(clusterName); err != nil {
 		return err
 	}
-	if err := setupAnsible(clusterName); err != nil {
+	if err := setupNodeExecutor(clusterName); err != nil {
 		return err
 	}
 	ansibleHostIDs, err := ansible.GetAnsibleHostsFromInventory(app.GetAnsibleInventoryDirPath(clusterName))
@@ -83,18 +85,16 @@ func statusNode(_ *cobra.Command, args []string) error {
 		return err
 	}
 	avalanchegoVersionForNode := map[string]string{}
-	if err := app.CreateAnsibleStatusDir(); err != nil {
-		return err
-	}
-	defer func() {
-		_ = app.RemoveAnsibleStatusDir()
-	}()
-	if err := ansible.RunAnsiblePlaybookCheckAvalancheGoVersion(app.GetAnsibleDir(), app.GetAvalancheGoJSONFile(), app.GetAnsibleInventoryDirPath(clusterName), "all"); err != nil {
-		return err
+	vmVersions, err := nodeExecutor.GetVMVersions(ansibleHostIDs)
+	if err != nil {
+		var hostErrs ssh.HostErrors
+		if !errors.As(err, &hostErrs) {
+			return err
+		}
 	}
 	for _, host := range ansibleHostIDs {
-		avalancheGoVersion, err := parseAvalancheGoOutput(app.GetAvalancheGoJSONFile() + "." + host)
-		if err != nil {
+		avalancheGoVersion, ok := vmVersions[host][constants.PlatformKeyName]
+		if !ok {
 			avalancheGoVersion = constants.AvalancheGoVersionUnknown
 		}
 		avalanchegoVersionForNode[host] = avalancheGoVersion
@@ -125,21 +125,12 @@ func statusNode(_ *cobra.Command, args []string) error {
 			}
 		}
 		if len(hostsToCheckSyncStatus) != 0 {
-			if err := ansible.RunAnsiblePlaybookSubnetSyncStatus(
-				app.GetAnsibleDir(),
-				app.GetSubnetSyncJSONFile(),
-				blockchainID.String(),
-				app.GetAnsibleInventoryDirPath(clusterName),
-				strings.Join(hostsToCheckSyncStatus, ","),
-			); err != nil {
+			subnetSyncStatus, err := nodeExecutor.GetSubnetSyncStatus(hostsToCheckSyncStatus, blockchainID.String())
+			if err != nil {
 				return err
 			}
 			for _, ansibleHostID := range hostsToCheckSyncStatus {
-				subnetSyncStatus, err := parseSubnetSyncOutput(app.GetSubnetSyncJSONFile() + "." + ansibleHostID)
-				if err != nil {
-					return err
-				}
-				switch subnetSyncStatus {
+				switch subnetSyncStatus[ansibleHostID] {
 				case status.Syncing.String():
 					subnetSyncedNodes = append(subnetSyncedNodes, ansibleHostID)
 				case status.Validating.String():
@@ -32,7 +32,12 @@ func newStopCmd() *cobra.Command {
 
 The node stop command stops a running node in cloud server
 
-Note that a stopped node may still incur cloud server storage fees.`,
+Note that a stopped node may still incur cloud server storage fees. Use
+avalanche node destroy to delete the cloud servers of a cluster.
+
+Nodes set up on existing servers with node create --existing-hosts only have
+avalanchego stopped, the servers themselves are left running. Nodes created with
+--local-machine have their avalanchego processes stopped, and their data removed.`,
 		SilenceUsage: true,
 		Args:         cobra.ExactArgs(1),
 		RunE:         stopNodes,
@@ -63,6 +68,9 @@ func removeDeletedNodeDirectory(clusterName string) error {
 }
 
 func removeClusterInventoryDir(clusterName string) error {
+	if err := os.RemoveAll(app.GetMonitoringInventoryDirPath(clusterName)); err != nil {
+		return err
+	}
 	return os.RemoveAll(app.GetAnsibleInventoryDirPath(clusterName))
 }
 
@@ -87,6 +95,9 @@ func removeClustersConfigFiles(clusterName string) error {
 	if err := removeClusterInventoryDir(clusterName); err != nil {
 		return err
 	}
+	if err := os.RemoveAll(app.GetClusterDirPath(clusterName)); err != nil {
+		return err
+	}
 	return removeNodeFromClustersConfig(clusterName)
 }
 
@@ -102,12 +113,20 @@ func stopNodes(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	monitoringInstance, dedicated, err := getClusterMonitoringInstance(clusterName)
+	if err != nil {
+		return err
+	}
+	if dedicated {
+		clusterNodes = append(clusterNodes, monitoringInstance)
+	}
 	failedNodes := []string{}
 	nodeErrors := []error{}
 	lastRegion := ""
 	var ec2Svc *ec2.EC2
 	var gcpClient *compute.Service
 	var gcpProjectName string
+	nodeExecutorReady := false
 	for _, node := range clusterNodes {
 		nodeConfig, err := app.LoadClusterNodeConfig(node)
 		if err != nil {
@@ -116,7 +135,25 @@ func stopNodes(_ *cobra.Command, args []string) error {
 			nodeErrors = append(nodeErrors, err)
 			continue
 		}
-		if nodeConfig.CloudService == "" || nodeConfig.CloudService == constants.AWSCloudService {
+		if nodeConfig.CloudService == constants.ExistingHostsService || nodeConfig.CloudService == constants.LocalMachineService {
+			// servers not created by the CLI are kept, only avalanchego is stopped. Local machine
+			// nodes keep their avalanchego data at the node dir, so it's deleted with it below
+			if !nodeExecutorReady {
+				if err := setupNodeExecutor(clusterName); err != nil {
+					return err
+				}
+				nodeExecutorReady = true
+			}
+			ansibleHostID, err := models.HostCloudIDToAnsibleID(nodeConfig.CloudService, nodeConfig.NodeID)
+			if err != nil {
+				return err
+			}
+			if err := nodeExecutor.StopNode(ansibleHostID); err != nil {
+				failedNodes = append(failedNodes, node)
+				nodeErrors = append(nodeErrors, err)
+				continue
+			}
+		} else if nodeConfig.CloudService == "" || nodeConfig.CloudService == constants.AWSCloudService {
 			// need to check if it's empty because we didn't set cloud service when only using AWS
 			if nodeConfig.Region != lastRegion {
 				sess, err := getAWSCloudCredentials(awsProfile, nodeConfig.Region, constants.StopAWSNode, authorizeAccess)
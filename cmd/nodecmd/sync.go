@@ -3,10 +3,7 @@
 package nodecmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
-	"os"
 	"strings"
 
 	awsAPI "github.com/ava-labs/avalanche-cli/pkg/aws"
@@ -125,7 +122,7 @@ func syncSubnet(_ *cobra.Command, args []string) error {
 	if err := checkCluster(clusterName); err != nil {
 		return err
 	}
-	if err := setupAnsible(clusterName); err != nil {
+	if err := setupNodeExecutor(clusterName); err != nil {
 		return err
 	}
 	if _, err := subnetcmd.ValidateSubnetNameAndGetChains([]string{subnetName}); err != nil {
@@ -164,7 +161,11 @@ func syncSubnet(_ *cobra.Command, args []string) error {
 		}
 		return fmt.Errorf("the Avalanche Go version of node(s) %s is incompatible with VM RPC version of %s", incompatibleNodes, subnetName)
 	}
-	if err := setupBuildEnv(app.GetAnsibleInventoryDirPath(clusterName), ""); err != nil {
+	hostAliases, err := ansible.GetAnsibleHostsFromInventory(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	if err := setupBuildEnv(hostAliases); err != nil {
 		return err
 	}
 	clustersConfig, err := app.LoadClustersConfig()
@@ -184,31 +185,6 @@ func syncSubnet(_ *cobra.Command, args []string) error {
 	return nil
 }
 
-func parseAvalancheGoOutput(fileName string) (string, error) {
-	jsonFile, err := os.Open(fileName)
-	if err != nil {
-		return "", err
-	}
-	defer jsonFile.Close()
-	byteValue, _ := io.ReadAll(jsonFile)
-
-	var result map[string]interface{}
-	if err = json.Unmarshal(byteValue, &result); err != nil {
-		return "", err
-	}
-	nodeIDInterface, ok := result["result"].(map[string]interface{})
-	if ok {
-		vmVersions, ok := nodeIDInterface["vmVersions"].(map[string]interface{})
-		if ok {
-			avalancheGoVersion, ok := vmVersions["platform"].(string)
-			if ok {
-				return avalancheGoVersion, nil
-			}
-		}
-	}
-	return "", nil
-}
-
 func checkForCompatibleAvagoVersion(configuredRPCVersion int) ([]string, error) {
 	compatibleAvagoVersions, err := vm.GetAvailableAvalancheGoVersions(
 		app, configuredRPCVersion, constants.AvalancheGoCompatibilityURL)
@@ -219,9 +195,6 @@ func checkForCompatibleAvagoVersion(configuredRPCVersion int) ([]string, error)
 }
 
 func checkAvalancheGoVersionCompatible(clusterName, subnetName string) ([]string, error) {
-	if err := app.CreateAnsibleDir(); err != nil {
-		return nil, err
-	}
 	ansibleNodeIDs, err := ansible.GetAnsibleHostsFromInventory(app.GetAnsibleInventoryDirPath(clusterName))
 	if err != nil {
 		return nil, err
@@ -229,17 +202,12 @@ func checkAvalancheGoVersionCompatible(clusterName, subnetName string) ([]string
 	ux.Logger.PrintToUser(fmt.Sprintf("Checking compatibility of avalanche go version in cluster %s with Subnet EVM RPC of subnet %s ...", clusterName, subnetName))
 	compatibleVersions := []string{}
 	incompatibleNodes := []string{}
-	if err := app.CreateAnsibleStatusDir(); err != nil {
-		return nil, err
-	}
-	if err := ansible.RunAnsiblePlaybookCheckAvalancheGoVersion(app.GetAnsibleDir(), app.GetAvalancheGoJSONFile(), app.GetAnsibleInventoryDirPath(clusterName), "all"); err != nil {
+	vmVersions, err := nodeExecutor.GetVMVersions(ansibleNodeIDs)
+	if err != nil {
 		return nil, err
 	}
 	for _, host := range ansibleNodeIDs {
-		avalancheGoVersion, err := parseAvalancheGoOutput(app.GetAvalancheGoJSONFile() + "." + host)
-		if err != nil {
-			return nil, err
-		}
+		avalancheGoVersion := vmVersions[host][constants.PlatformKeyName]
 		sc, err := app.LoadSidecar(subnetName)
 		if err != nil {
 			return nil, err
@@ -252,9 +220,6 @@ func checkAvalancheGoVersionCompatible(clusterName, subnetName string) ([]string
 			incompatibleNodes = append(incompatibleNodes, host)
 		}
 	}
-	if err := app.RemoveAnsibleStatusDir(); err != nil {
-		return nil, err
-	}
 	if len(incompatibleNodes) > 0 {
 		ux.Logger.PrintToUser(fmt.Sprintf("Compatible Avalanche Go versions are %s", strings.Join(compatibleVersions, ", ")))
 	}
@@ -264,24 +229,29 @@ func checkAvalancheGoVersionCompatible(clusterName, subnetName string) ([]string
 // trackSubnet exports deployed subnet in user's local machine to cloud server and calls node to
 // start tracking the specified subnet (similar to avalanche subnet join <subnetName> command)
 func trackSubnet(clusterName, subnetName string, network models.Network) ([]string, error) {
-	subnetPath := "/tmp/" + subnetName + constants.ExportSubnetSuffix
-	if err := subnetcmd.CallExportSubnet(subnetName, subnetPath, network); err != nil {
+	hostAliases, err := ansible.GetAnsibleHostsFromInventory(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
 		return nil, err
 	}
-	if err := ansible.RunAnsiblePlaybookSetupCLIFromSource(app.GetAnsibleDir(), app.GetAnsibleInventoryDirPath(clusterName), constants.SetupCLIFromSourceBranch, "all"); err != nil {
+	return trackSubnetOnHosts(hostAliases, subnetName, network)
+}
+
+// trackSubnetOnHosts makes the nodes of [hostAliases] track the specified subnet
+func trackSubnetOnHosts(hostAliases []string, subnetName string, network models.Network) ([]string, error) {
+	subnetPath := "/tmp/" + subnetName + constants.ExportSubnetSuffix
+	if err := subnetcmd.CallExportSubnet(subnetName, subnetPath, network); err != nil {
 		return nil, err
 	}
-	if err := ansible.RunAnsiblePlaybookExportSubnet(app.GetAnsibleDir(), app.GetAnsibleInventoryDirPath(clusterName), subnetPath, "all"); err != nil {
+	if err := nodeExecutor.SetupCLIFromSource(hostAliases, constants.SetupCLIFromSourceBranch); err != nil {
 		return nil, err
 	}
-	hostAliases, err := ansible.GetAnsibleHostsFromInventory(app.GetAnsibleInventoryDirPath(clusterName))
-	if err != nil {
+	if err := nodeExecutor.ExportSubnet(hostAliases, subnetPath); err != nil {
 		return nil, err
 	}
 	untrackedNodes := []string{}
 	for _, host := range hostAliases {
 		// runs avalanche join subnet command
-		if err = ansible.RunAnsiblePlaybookTrackSubnet(app.GetAnsibleDir(), network, subnetName, subnetPath, app.GetAnsibleInventoryDirPath(clusterName), host); err != nil {
+		if err := nodeExecutor.TrackSubnet(host, network, subnetName, subnetPath); err != nil {
 			untrackedNodes = append(untrackedNodes, host)
 		}
 	}
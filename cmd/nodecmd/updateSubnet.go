@@ -37,7 +37,7 @@ func updateSubnet(_ *cobra.Command, args []string) error {
 	if err := checkCluster(clusterName); err != nil {
 		return err
 	}
-	if err := setupAnsible(clusterName); err != nil {
+	if err := setupNodeExecutor(clusterName); err != nil {
 		return err
 	}
 	if _, err := subnetcmd.ValidateSubnetNameAndGetChains([]string{subnetName}); err != nil {
@@ -69,10 +69,18 @@ func updateSubnet(_ *cobra.Command, args []string) error {
 		}
 		return fmt.Errorf("the Avalanche Go version of node(s) %s is incompatible with VM RPC version of %s", incompatibleNodes, subnetName)
 	}
-	if err := setupBuildEnv(app.GetAnsibleInventoryDirPath(clusterName), ""); err != nil {
+	hostAliases, err := ansible.GetAnsibleHostsFromInventory(app.GetAnsibleInventoryDirPath(clusterName))
+	if err != nil {
+		return err
+	}
+	if err := setupBuildEnv(hostAliases); err != nil {
 		return err
 	}
-	nonUpdatedNodes, err := doUpdateSubnet(clusterName, subnetName, models.FujiNetwork)
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	nonUpdatedNodes, err := doUpdateSubnet(clusterName, subnetName, clustersConfig.Clusters[clusterName].Network)
 	if err != nil {
 		return err
 	}
@@ -91,17 +99,17 @@ func doUpdateSubnet(clusterName, subnetName string, network models.Network) ([]s
 	if err := subnetcmd.CallExportSubnet(subnetName, subnetPath, network); err != nil {
 		return nil, err
 	}
-	if err := ansible.RunAnsiblePlaybookExportSubnet(app.GetAnsibleDir(), app.GetAnsibleInventoryDirPath(clusterName), subnetPath, "all"); err != nil {
-		return nil, err
-	}
 	hostAliases, err := ansible.GetAnsibleHostsFromInventory(app.GetAnsibleInventoryDirPath(clusterName))
 	if err != nil {
 		return nil, err
 	}
+	if err := nodeExecutor.ExportSubnet(hostAliases, subnetPath); err != nil {
+		return nil, err
+	}
 	nonUpdatedNodes := []string{}
 	for _, host := range hostAliases {
 		// runs avalanche update subnet command
-		if err = ansible.RunAnsiblePlaybookUpdateSubnet(app.GetAnsibleDir(), subnetName, subnetPath, app.GetAnsibleInventoryDirPath(clusterName), host); err != nil {
+		if err = nodeExecutor.UpdateSubnet(host, network, subnetName, subnetPath); err != nil {
 			nonUpdatedNodes = append(nonUpdatedNodes, host)
 		}
 	}
@@ -3,28 +3,59 @@
 package nodecmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"os"
+	"runtime"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/ava-labs/avalanche-cli/pkg/ansible"
 	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/ssh"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-cli/pkg/vm"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/slices"
 )
 
 type nodeUpgradeInfo struct {
-	AvalancheGoVersion    string   // avalanche go version to update to on cloud server
-	SubnetEVMVersion      string   // subnet EVM version to update to on cloud server
-	SubnetEVMIDsToUpgrade []string // list of ID of Subnet EVM to be upgraded to subnet EVM version to update to
+	AvalancheGoVersion         string            // avalanche go version to update to on cloud server
+	SubnetEVMVersion           string            // subnet EVM version to update to on cloud server
+	SubnetEVMIDsToUpgrade      []string          // list of ID of Subnet EVM to be upgraded to subnet EVM version to update to
+	PreviousAvalancheGoVersion string            // avalanche go version run before the upgrade, used on rollback
+	PreviousSubnetEVMVersions  map[string]string // subnet EVM version run before the upgrade by Subnet EVM ID, used on rollback
 }
 
+// nodeUpgradeResult is the outcome of the upgrade of a node, shown on the final report
+type nodeUpgradeResult struct {
+	Status string
+	Err    error
+}
+
+const (
+	upgradeStatusUpgraded          = "upgraded"
+	upgradeStatusFailed            = "failed"
+	upgradeStatusRolledBack        = "rolled back"
+	upgradeStatusRollbackFailed    = "rollback failed"
+	upgradeStatusRollbackUnhealthy = "rolled back, not healthy"
+	upgradeStatusSkipped           = "skipped"
+	upgradeHealthCheckInterval     = 10 * time.Second
+)
+
+var (
+	upgradeBatchSize     int
+	upgradeHealthTimeout time.Duration
+	upgradeMaxFailures   int
+	upgradeRollback      bool
+)
+
 func newUpgradeCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "upgrade",
@@ -34,55 +65,368 @@ func newUpgradeCmd() *cobra.Command {
 The node update command suite provides a collection of commands for nodes to update
 their avalanchego or VM version.
 
+Nodes are upgraded in rolling batches of --batch-size nodes. After each batch,
+the command waits up to --health-timeout for the upgraded nodes to be healthy,
+bootstrapped, and syncing their upgraded Subnets. The nodes of the batch that
+failed to upgrade or didn't come back in time are rolled back to their previous
+avalanchego and VM versions (unless --rollback=false), and are then checked to
+be healthy again. The rollout is aborted once --max-failures nodes have failed,
+and a summary report is shown at the end.
+
 You can check the status after upgrade by calling avalanche node status`,
 		SilenceUsage: true,
 		Args:         cobra.ExactArgs(1),
 		RunE:         upgrade,
 	}
+	cmd.Flags().IntVar(&upgradeBatchSize, "batch-size", 1, "number of nodes to upgrade at the same time")
+	cmd.Flags().DurationVar(&upgradeHealthTimeout, "health-timeout", 10*time.Minute, "time to wait for an upgraded batch to become healthy")
+	cmd.Flags().IntVar(&upgradeMaxFailures, "max-failures", 1, "number of failed nodes after which the upgrade is aborted")
+	cmd.Flags().BoolVar(&upgradeRollback, "rollback", true, "roll back a batch to previous versions if it doesn't become healthy")
 
 	return cmd
 }
 
 func upgrade(_ *cobra.Command, args []string) error {
 	clusterName := args[0]
+	if upgradeBatchSize <= 0 {
+		return errors.New("batch size must be greater than 0")
+	}
+	if upgradeMaxFailures <= 0 {
+		return errors.New("max failures must be greater than 0")
+	}
 	if err := checkCluster(clusterName); err != nil {
 		return err
 	}
-	if err := setupAnsible(clusterName); err != nil {
+	if err := setupNodeExecutor(clusterName); err != nil {
 		return err
 	}
 	toUpgradeNodesMap, err := getNodesUpgradeInfo(clusterName)
 	if err != nil {
 		return err
 	}
+	nodesToUpgrade := []string{}
 	for node, upgradeInfo := range toUpgradeNodesMap {
-		if upgradeInfo.AvalancheGoVersion != "" {
-			if err = upgradeAvalancheGo(clusterName, node, upgradeInfo.AvalancheGoVersion); err != nil {
-				return err
+		if upgradeInfo.AvalancheGoVersion != "" || upgradeInfo.SubnetEVMVersion != "" {
+			nodesToUpgrade = append(nodesToUpgrade, node)
+		}
+	}
+	if len(nodesToUpgrade) == 0 {
+		ux.Logger.PrintToUser("All nodes in cluster %s are up to date", clusterName)
+		return nil
+	}
+	sort.Strings(nodesToUpgrade)
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	blockchainIDsByVMID, err := getBlockchainIDsByVMID(clustersConfig.Clusters[clusterName].Network)
+	if err != nil {
+		return err
+	}
+
+	results := map[string]nodeUpgradeResult{}
+	batches := planUpgradeBatches(nodesToUpgrade, upgradeBatchSize)
+	for batchIndex, batch := range batches {
+		if shouldAbortUpgrade(results, upgradeMaxFailures) {
+			for _, node := range batch {
+				results[node] = nodeUpgradeResult{Status: upgradeStatusSkipped}
 			}
+			continue
 		}
-		if upgradeInfo.SubnetEVMVersion != "" {
-			subnetEVMVersionToUpgradeToWoPrefix := strings.TrimPrefix(upgradeInfo.SubnetEVMVersion, "v")
-			subnetEVMArchive := fmt.Sprintf(constants.SubnetEVMArchive, subnetEVMVersionToUpgradeToWoPrefix)
-			subnetEVMReleaseURL := fmt.Sprintf(constants.SubnetEVMReleaseURL, upgradeInfo.SubnetEVMVersion, subnetEVMArchive)
-			if err = getNewSubnetEVMRelease(clusterName, subnetEVMReleaseURL, subnetEVMArchive, node, upgradeInfo.SubnetEVMVersion); err != nil {
-				return err
+		ux.Logger.PrintToUser("Upgrading batch %d/%d: %s", batchIndex+1, len(batches), strings.Join(batch, ", "))
+		upgradeErrs := map[string]error{}
+		upgradedNodes := []string{}
+		for _, node := range batch {
+			if err := upgradeNode(node, toUpgradeNodesMap[node]); err != nil {
+				ux.Logger.PrintToUser("Failed to upgrade node %s: %s", node, err)
+				upgradeErrs[node] = err
+				continue
+			}
+			upgradedNodes = append(upgradedNodes, node)
+		}
+		unhealthyNodes := []string{}
+		if len(upgradedNodes) > 0 {
+			ux.Logger.PrintToUser("Waiting for batch %d/%d to become healthy ...", batchIndex+1, len(batches))
+			blockchainIDs := getUpgradedBlockchainIDs(upgradedNodes, toUpgradeNodesMap, blockchainIDsByVMID)
+			unhealthyNodes = waitForNodesHealthy(upgradedNodes, blockchainIDs, upgradeHealthTimeout)
+		}
+		failedNodes := getFailedUpgradeNodes(batch, upgradeErrs, unhealthyNodes, upgradeHealthTimeout)
+		for _, node := range batch {
+			results[node] = nodeUpgradeResult{Status: upgradeStatusUpgraded}
+			if err, ok := failedNodes[node]; ok {
+				results[node] = nodeUpgradeResult{Status: upgradeStatusFailed, Err: err}
+			}
+		}
+		if len(failedNodes) == 0 || !upgradeRollback {
+			continue
+		}
+		rolledBackNodes := []string{}
+		for _, node := range batch {
+			if _, ok := failedNodes[node]; !ok {
+				continue
 			}
-			if err = stopNode(clusterName, node); err != nil {
+			ux.Logger.PrintToUser("Rolling back node %s ...", node)
+			if err := rollbackNode(node, toUpgradeNodesMap[node]); err != nil {
+				ux.Logger.PrintToUser("Failed to roll back node %s: %s", node, err)
+				results[node] = nodeUpgradeResult{Status: upgradeStatusRollbackFailed, Err: err}
+				continue
+			}
+			rolledBackNodes = append(rolledBackNodes, node)
+		}
+		if len(rolledBackNodes) == 0 {
+			continue
+		}
+		ux.Logger.PrintToUser("Waiting for the rolled back nodes of batch %d/%d to become healthy ...", batchIndex+1, len(batches))
+		blockchainIDs := getUpgradedBlockchainIDs(rolledBackNodes, toUpgradeNodesMap, blockchainIDsByVMID)
+		unhealthyRolledBackNodes := waitForNodesHealthy(rolledBackNodes, blockchainIDs, upgradeHealthTimeout)
+		for _, node := range rolledBackNodes {
+			status := upgradeStatusRolledBack
+			if slices.Contains(unhealthyRolledBackNodes, node) {
+				status = upgradeStatusRollbackUnhealthy
+			}
+			results[node] = nodeUpgradeResult{Status: status, Err: results[node].Err}
+		}
+	}
+	printUpgradeReport(nodesToUpgrade, toUpgradeNodesMap, results)
+	if failures := countUpgradeFailures(results); failures > 0 {
+		return fmt.Errorf("failed to upgrade %d node(s) of cluster %s", failures, clusterName)
+	}
+	return nil
+}
+
+// planUpgradeBatches splits [nodes] into the batches of at most [batchSize] nodes upgraded at the same time
+func planUpgradeBatches(nodes []string, batchSize int) [][]string {
+	batches := [][]string{}
+	for batchStart := 0; batchStart < len(nodes); batchStart += batchSize {
+		batchEnd := batchStart + batchSize
+		if batchEnd > len(nodes) {
+			batchEnd = len(nodes)
+		}
+		batches = append(batches, nodes[batchStart:batchEnd])
+	}
+	return batches
+}
+
+// getFailedUpgradeNodes returns the errors of the nodes of [batch] that failed, either on upgrade,
+// as given by [upgradeErrs], or by not becoming healthy within [healthTimeout], as given by [unhealthyNodes]
+func getFailedUpgradeNodes(batch []string, upgradeErrs map[string]error, unhealthyNodes []string, healthTimeout time.Duration) map[string]error {
+	failedNodes := map[string]error{}
+	for _, node := range batch {
+		if err, ok := upgradeErrs[node]; ok {
+			failedNodes[node] = err
+		} else if slices.Contains(unhealthyNodes, node) {
+			failedNodes[node] = fmt.Errorf("node not healthy after %s", healthTimeout)
+		}
+	}
+	return failedNodes
+}
+
+// countUpgradeFailures returns the number of nodes of [results] that failed to upgrade, rolled
+// back or not
+func countUpgradeFailures(results map[string]nodeUpgradeResult) int {
+	failures := 0
+	for _, result := range results {
+		if result.Status != upgradeStatusUpgraded && result.Status != upgradeStatusSkipped {
+			failures++
+		}
+	}
+	return failures
+}
+
+// shouldAbortUpgrade tells whether the batches left are skipped, given the [results] of the
+// batches already upgraded, once [maxFailures] nodes have failed
+func shouldAbortUpgrade(results map[string]nodeUpgradeResult, maxFailures int) bool {
+	return countUpgradeFailures(results) >= maxFailures
+}
+
+// upgradeNode upgrades avalanchego and subnet EVM binaries of [node] as given by [upgradeInfo]
+func upgradeNode(node string, upgradeInfo nodeUpgradeInfo) error {
+	if upgradeInfo.AvalancheGoVersion != "" {
+		if err := upgradeAvalancheGo(node, upgradeInfo.AvalancheGoVersion); err != nil {
+			return err
+		}
+	}
+	if upgradeInfo.SubnetEVMVersion != "" {
+		subnetEVMVersions := map[string]string{}
+		for _, vmID := range upgradeInfo.SubnetEVMIDsToUpgrade {
+			subnetEVMVersions[vmID] = upgradeInfo.SubnetEVMVersion
+		}
+		if err := installSubnetEVMVersions(node, subnetEVMVersions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollbackNode puts back the avalanchego and subnet EVM versions [node] was running before
+// the upgrade given by [upgradeInfo]
+func rollbackNode(node string, upgradeInfo nodeUpgradeInfo) error {
+	if upgradeInfo.AvalancheGoVersion != "" && upgradeInfo.PreviousAvalancheGoVersion != "" {
+		if err := upgradeAvalancheGo(node, upgradeInfo.PreviousAvalancheGoVersion); err != nil {
+			return err
+		}
+	}
+	if upgradeInfo.SubnetEVMVersion != "" {
+		if err := installSubnetEVMVersions(node, upgradeInfo.PreviousSubnetEVMVersions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// installSubnetEVMVersions downloads and installs on [node] the subnet EVM version given for
+// each subnet EVM ID of [subnetEVMVersions], restarting the node once done
+func installSubnetEVMVersions(node string, subnetEVMVersions map[string]string) error {
+	vmIDsByVersion := map[string][]string{}
+	for vmID, version := range subnetEVMVersions {
+		vmIDsByVersion[version] = append(vmIDsByVersion[version], vmID)
+	}
+	if len(vmIDsByVersion) == 0 {
+		return nil
+	}
+	nodeOS, nodeArch, err := getNodePlatform(node)
+	if err != nil {
+		return err
+	}
+	if err := stopNode(node); err != nil {
+		return err
+	}
+	for version, vmIDs := range vmIDsByVersion {
+		subnetEVMVersionWoPrefix := strings.TrimPrefix(version, "v")
+		subnetEVMArchive := fmt.Sprintf(constants.SubnetEVMArchive, subnetEVMVersionWoPrefix, nodeOS, nodeArch)
+		subnetEVMReleaseURL := fmt.Sprintf(constants.SubnetEVMReleaseURL, version, subnetEVMArchive)
+		if err := getNewSubnetEVMRelease(subnetEVMReleaseURL, subnetEVMArchive, node, version); err != nil {
+			return err
+		}
+		for _, vmID := range vmIDs {
+			subnetEVMBinaryPath := fmt.Sprintf(constants.SubnetEVMBinaryPath, vmID)
+			if err := upgradeSubnetEVM(subnetEVMBinaryPath, node, version); err != nil {
 				return err
 			}
-			for _, vmID := range upgradeInfo.SubnetEVMIDsToUpgrade {
-				subnetEVMBinaryPath := fmt.Sprintf(constants.SubnetEVMBinaryPath, vmID)
-				if err = upgradeSubnetEVM(clusterName, subnetEVMBinaryPath, node, upgradeInfo.SubnetEVMVersion); err != nil {
-					return err
+		}
+	}
+	return startNode(node)
+}
+
+// getNodePlatform returns the OS and cpu architecture of [node], to pick the binaries to install on it.
+// Nodes on the local machine run on the CLI platform, cloud nodes on linux
+func getNodePlatform(node string) (string, string, error) {
+	_, cloudHostID, err := models.HostAnsibleIDToCloudID(node)
+	if err != nil {
+		return "", "", err
+	}
+	nodeConfig, err := app.LoadClusterNodeConfig(cloudHostID)
+	if err != nil {
+		return "", "", err
+	}
+	if nodeConfig.CloudService == constants.LocalMachineService {
+		return runtime.GOOS, runtime.GOARCH, nil
+	}
+	return "linux", getNodeArch(nodeConfig), nil
+}
+
+// getBlockchainIDsByVMID maps the VM IDs of the subnets known to the CLI to the IDs of their
+// blockchains deployed on [network]
+func getBlockchainIDsByVMID(network models.Network) (map[string][]string, error) {
+	blockchainIDsByVMID := map[string][]string{}
+	subnetNames, err := app.GetSidecarNames()
+	if err != nil {
+		return nil, err
+	}
+	for _, subnetName := range subnetNames {
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return nil, err
+		}
+		blockchainID := sc.Networks[network.Name()].BlockchainID
+		if blockchainID == ids.Empty {
+			continue
+		}
+		vmID, err := sc.GetVMID()
+		if err != nil {
+			return nil, err
+		}
+		blockchainIDsByVMID[vmID] = append(blockchainIDsByVMID[vmID], blockchainID.String())
+	}
+	return blockchainIDsByVMID, nil
+}
+
+// getUpgradedBlockchainIDs returns, for each one of [nodes], the blockchains run by the
+// subnet EVMs upgraded on it
+func getUpgradedBlockchainIDs(nodes []string, toUpgradeNodesMap map[string]nodeUpgradeInfo, blockchainIDsByVMID map[string][]string) map[string][]string {
+	blockchainIDs := map[string][]string{}
+	for _, node := range nodes {
+		blockchainIDs[node] = []string{}
+		for _, vmID := range toUpgradeNodesMap[node].SubnetEVMIDsToUpgrade {
+			blockchainIDs[node] = append(blockchainIDs[node], blockchainIDsByVMID[vmID]...)
+		}
+	}
+	return blockchainIDs
+}
+
+// waitForNodesHealthy waits up to [timeout] for [nodes] to be healthy, bootstrapped, and
+// syncing their given blockchains. Returns the nodes that didn't get there in time
+func waitForNodesHealthy(nodes []string, blockchainIDs map[string][]string, timeout time.Duration) []string {
+	pendingNodes := slices.Clone(nodes)
+	deadline := time.Now().Add(timeout)
+	for len(pendingNodes) > 0 {
+		// errors are expected while nodes restart, so nodes failing checks are retried
+		isHealthy, _ := nodeExecutor.IsHealthy(pendingNodes)
+		isBootstrapped, _ := nodeExecutor.IsBootstrapped(pendingNodes)
+		stillPending := []string{}
+		for _, node := range pendingNodes {
+			ready := isHealthy[node] && isBootstrapped[node]
+			for _, blockchainID := range blockchainIDs[node] {
+				if !ready {
+					break
 				}
+				syncStatus, _ := nodeExecutor.GetSubnetSyncStatus([]string{node}, blockchainID)
+				ready = syncStatus[node] == status.Validating.String() || syncStatus[node] == status.Syncing.String()
 			}
-			if err = startNode(clusterName, node); err != nil {
-				return err
+			if !ready {
+				stillPending = append(stillPending, node)
 			}
 		}
+		pendingNodes = stillPending
+		if len(pendingNodes) == 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(upgradeHealthCheckInterval)
 	}
-	return nil
+	return pendingNodes
+}
+
+func printUpgradeReport(nodes []string, toUpgradeNodesMap map[string]nodeUpgradeInfo, results map[string]nodeUpgradeResult) {
+	ux.Logger.PrintToUser("======================================")
+	ux.Logger.PrintToUser("UPGRADE REPORT")
+	ux.Logger.PrintToUser("======================================")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Node", "AvalancheGo", "Subnet EVM", "Status", "Error"})
+	table.SetRowLine(true)
+	for _, node := range nodes {
+		upgradeInfo := toUpgradeNodesMap[node]
+		avalancheGoChange := ""
+		if upgradeInfo.AvalancheGoVersion != "" {
+			avalancheGoChange = fmt.Sprintf("%s -> %s", upgradeInfo.PreviousAvalancheGoVersion, upgradeInfo.AvalancheGoVersion)
+		}
+		subnetEVMChange := ""
+		if upgradeInfo.SubnetEVMVersion != "" {
+			previousVersions := []string{}
+			for _, version := range upgradeInfo.PreviousSubnetEVMVersions {
+				if !slices.Contains(previousVersions, version) {
+					previousVersions = append(previousVersions, version)
+				}
+			}
+			sort.Strings(previousVersions)
+			subnetEVMChange = fmt.Sprintf("%s -> %s", strings.Join(previousVersions, ", "), upgradeInfo.SubnetEVMVersion)
+		}
+		errMsg := ""
+		if results[node].Err != nil {
+			errMsg = results[node].Err.Error()
+		}
+		table.Append([]string{node, avalancheGoChange, subnetEVMChange, results[node].Status, errMsg})
+	}
+	table.Render()
 }
 
 // getNodesUpgradeInfo gets the node versions of all nodes in cluster clusterName and checks which
@@ -115,27 +459,35 @@ func getNodesUpgradeInfo(clusterName string) (map[string]nodeUpgradeInfo, error)
 	failedNodes := []string{}
 	nodeErrors := []error{}
 	nodesToUpgrade := make(map[string]nodeUpgradeInfo)
-	for _, host := range ansibleNodeIDs {
-		if err := app.CreateAnsibleStatusFile(app.GetAvalancheGoJSONFile()); err != nil {
-			failedNodes = append(failedNodes, host)
-			nodeErrors = append(nodeErrors, err)
-			continue
+	nodesVMVersions, err := nodeExecutor.GetVMVersions(ansibleNodeIDs)
+	if err != nil {
+		var hostErrs ssh.HostErrors
+		if !errors.As(err, &hostErrs) {
+			return nil, err
 		}
-		if err := ansible.RunAnsiblePlaybookCheckAvalancheGoVersion(app.GetAnsibleDir(), app.GetAvalancheGoJSONFile(), app.GetAnsibleInventoryDirPath(clusterName), host); err != nil {
-			failedNodes = append(failedNodes, host)
-			nodeErrors = append(nodeErrors, err)
+		for _, host := range ansibleNodeIDs {
+			if hostErr, ok := hostErrs[host]; ok {
+				failedNodes = append(failedNodes, host)
+				nodeErrors = append(nodeErrors, hostErr)
+			}
+		}
+	}
+	for _, host := range ansibleNodeIDs {
+		vmVersions, ok := nodesVMVersions[host]
+		if !ok {
 			continue
 		}
-		vmVersions, err := parseNodeVersionOutput(app.GetAvalancheGoJSONFile() + "." + host)
-		if err != nil {
+		currentAvalancheGoVersion := vmVersions[constants.PlatformKeyName]
+		if currentAvalancheGoVersion == constants.AvalancheGoVersionUnknown {
 			failedNodes = append(failedNodes, host)
-			nodeErrors = append(nodeErrors, err)
+			nodeErrors = append(nodeErrors, errors.New("failed to get the node versions"))
 			continue
 		}
-		currentAvalancheGoVersion := vmVersions[constants.PlatformKeyName]
 		avalancheGoVersionToUpdateTo := latestAvagoVersion
 		nodeUpgradeInfo := nodeUpgradeInfo{}
 		nodeUpgradeInfo.SubnetEVMIDsToUpgrade = []string{}
+		nodeUpgradeInfo.PreviousAvalancheGoVersion = currentAvalancheGoVersion
+		nodeUpgradeInfo.PreviousSubnetEVMVersions = map[string]string{}
 		for vmName, vmVersion := range vmVersions {
 			// when calling info.getNodeVersion, this is what we get
 			// "vmVersions":{"avm":"v1.10.12","evm":"v0.12.5","n8Anw9kErmgk7KHviddYtecCmziLZTphDwfL1V2DfnFjWZXbE":"v0.5.6","platform":"v1.10.12"}},
@@ -146,6 +498,7 @@ func getNodesUpgradeInfo(clusterName string) (map[string]nodeUpgradeInfo, error)
 					ux.Logger.PrintToUser("Upgrading Subnet EVM version for node %s from version %s to version %s", host, vmVersion, latestSubnetEVMVersion)
 					nodeUpgradeInfo.SubnetEVMVersion = latestSubnetEVMVersion
 					nodeUpgradeInfo.SubnetEVMIDsToUpgrade = append(nodeUpgradeInfo.SubnetEVMIDsToUpgrade, vmName)
+					nodeUpgradeInfo.PreviousSubnetEVMVersions[vmName] = vmVersion
 				}
 				// find the highest version of avalanche go that is still compatible with current highest rpc
 				avalancheGoVersionToUpdateTo, err = GetLatestAvagoVersionForRPC(rpcVersion)
@@ -164,11 +517,6 @@ func getNodesUpgradeInfo(clusterName string) (map[string]nodeUpgradeInfo, error)
 			nodeUpgradeInfo.AvalancheGoVersion = avalancheGoVersionToUpdateTo
 		}
 		nodesToUpgrade[host] = nodeUpgradeInfo
-		if err := app.RemoveAnsibleStatusDir(); err != nil {
-			failedNodes = append(failedNodes, host)
-			nodeErrors = append(nodeErrors, err)
-			continue
-		}
 	}
 	if len(failedNodes) > 0 {
 		ux.Logger.PrintToUser("Failed to upgrade nodes: ")
@@ -186,9 +534,9 @@ func checkIfKeyIsStandardVMName(vmName string) bool {
 	return slices.Contains(standardVMNames, vmName)
 }
 
-func upgradeAvalancheGo(clusterName, ansibleNodeID, avaGoVersionToUpdateTo string) error {
+func upgradeAvalancheGo(ansibleNodeID, avaGoVersionToUpdateTo string) error {
 	ux.Logger.PrintToUser("Upgrading Avalanche Go version of node %s to version %s ...", ansibleNodeID, avaGoVersionToUpdateTo)
-	if err := ansible.RunAnsiblePlaybookUpgradeAvalancheGo(app.GetAnsibleDir(), app.GetAnsibleInventoryDirPath(clusterName), ansibleNodeID, avaGoVersionToUpdateTo); err != nil {
+	if err := nodeExecutor.UpgradeAvalancheGo(ansibleNodeID, avaGoVersionToUpdateTo); err != nil {
 		return err
 	}
 	ux.Logger.PrintToUser("Successfully upgraded Avalanche Go version of node %s!", ansibleNodeID)
@@ -196,23 +544,23 @@ func upgradeAvalancheGo(clusterName, ansibleNodeID, avaGoVersionToUpdateTo strin
 	return nil
 }
 
-func stopNode(clusterName, ansibleNodeID string) error {
-	if err := ansible.RunAnsiblePlaybookStopNode(app.GetAnsibleDir(), app.GetAnsibleInventoryDirPath(clusterName), ansibleNodeID); err != nil {
+func stopNode(ansibleNodeID string) error {
+	if err := nodeExecutor.StopNode(ansibleNodeID); err != nil {
 		return err
 	}
 	return nil
 }
 
-func startNode(clusterName, ansibleNodeID string) error {
-	if err := ansible.RunAnsiblePlaybookStartNode(app.GetAnsibleDir(), app.GetAnsibleInventoryDirPath(clusterName), ansibleNodeID); err != nil {
+func startNode(ansibleNodeID string) error {
+	if err := nodeExecutor.StartNode(ansibleNodeID); err != nil {
 		return err
 	}
 	return nil
 }
 
-func upgradeSubnetEVM(clusterName, subnetEVMBinaryPath, ansibleNodeID, subnetEVMVersion string) error {
+func upgradeSubnetEVM(subnetEVMBinaryPath, ansibleNodeID, subnetEVMVersion string) error {
 	ux.Logger.PrintToUser("Upgrading SubnetEVM version of node %s to version %s ...", ansibleNodeID, subnetEVMVersion)
-	if err := ansible.RunAnsiblePlaybookUpgradeSubnetEVM(app.GetAnsibleDir(), subnetEVMBinaryPath, app.GetAnsibleInventoryDirPath(clusterName), ansibleNodeID); err != nil {
+	if err := nodeExecutor.UpgradeSubnetEVM(ansibleNodeID, subnetEVMBinaryPath); err != nil {
 		return err
 	}
 	ux.Logger.PrintToUser("Successfully upgraded SubnetEVM version of node %s!", ansibleNodeID)
@@ -220,36 +568,12 @@ func upgradeSubnetEVM(clusterName, subnetEVMBinaryPath, ansibleNodeID, subnetEVM
 	return nil
 }
 
-func getNewSubnetEVMRelease(clusterName, subnetEVMReleaseURL, subnetEVMArchive, ansibleNodeID, subnetEVMVersion string) error {
+func getNewSubnetEVMRelease(subnetEVMReleaseURL, subnetEVMArchive, ansibleNodeID, subnetEVMVersion string) error {
 	ux.Logger.PrintToUser("Getting new SubnetEVM version %s ...", subnetEVMVersion)
-	if err := ansible.RunAnsiblePlaybookGetNewSubnetEVM(app.GetAnsibleDir(), subnetEVMReleaseURL, subnetEVMArchive, app.GetAnsibleInventoryDirPath(clusterName), ansibleNodeID); err != nil {
+	if err := nodeExecutor.GetNewSubnetEVMRelease(ansibleNodeID, subnetEVMReleaseURL, subnetEVMArchive); err != nil {
 		return err
 	}
 	ux.Logger.PrintToUser("Successfully downloaded SubnetEVM version for node %s!", ansibleNodeID)
 	ux.Logger.PrintToUser("======================================")
 	return nil
 }
-
-func parseNodeVersionOutput(fileName string) (map[string]interface{}, error) {
-	jsonFile, err := os.Open(fileName)
-	if err != nil {
-		return nil, err
-	}
-	defer jsonFile.Close()
-	byteValue, err := io.ReadAll(jsonFile)
-	if err != nil {
-		return nil, err
-	}
-	var result map[string]interface{}
-	if err = json.Unmarshal(byteValue, &result); err != nil {
-		return nil, err
-	}
-	nodeIDInterface, ok := result["result"].(map[string]interface{})
-	if ok {
-		vmVersions, ok := nodeIDInterface["vmVersions"].(map[string]interface{})
-		if ok {
-			return vmVersions, nil
-		}
-	}
-	return nil, nil
-}
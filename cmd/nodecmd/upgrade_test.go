@@ -0,0 +1,154 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanUpgradeBatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		nodes     []string
+		batchSize int
+		expected  [][]string
+	}{
+		{
+			name:      "no nodes",
+			nodes:     []string{},
+			batchSize: 2,
+			expected:  [][]string{},
+		},
+		{
+			name:      "one node per batch",
+			nodes:     []string{"n1", "n2", "n3"},
+			batchSize: 1,
+			expected:  [][]string{{"n1"}, {"n2"}, {"n3"}},
+		},
+		{
+			name:      "last batch partial",
+			nodes:     []string{"n1", "n2", "n3"},
+			batchSize: 2,
+			expected:  [][]string{{"n1", "n2"}, {"n3"}},
+		},
+		{
+			name:      "exact batches",
+			nodes:     []string{"n1", "n2", "n3", "n4"},
+			batchSize: 2,
+			expected:  [][]string{{"n1", "n2"}, {"n3", "n4"}},
+		},
+		{
+			name:      "batch size above node count",
+			nodes:     []string{"n1", "n2"},
+			batchSize: 5,
+			expected:  [][]string{{"n1", "n2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, planUpgradeBatches(tt.nodes, tt.batchSize))
+		})
+	}
+}
+
+func TestGetFailedUpgradeNodes(t *testing.T) {
+	upgradeErr := errors.New("download failed")
+	tests := []struct {
+		name           string
+		upgradeErrs    map[string]error
+		unhealthyNodes []string
+		expected       map[string]error
+	}{
+		{
+			name:           "all upgraded and healthy",
+			upgradeErrs:    map[string]error{},
+			unhealthyNodes: []string{},
+			expected:       map[string]error{},
+		},
+		{
+			name:           "one failed upgrade doesn't fail the rest of the batch",
+			upgradeErrs:    map[string]error{"n1": upgradeErr},
+			unhealthyNodes: []string{},
+			expected:       map[string]error{"n1": upgradeErr},
+		},
+		{
+			name:           "failed upgrade and unhealthy node",
+			upgradeErrs:    map[string]error{"n1": upgradeErr},
+			unhealthyNodes: []string{"n3"},
+			expected:       map[string]error{"n1": upgradeErr, "n3": errors.New("node not healthy after 1m0s")},
+		},
+		{
+			name:           "nodes outside of the batch are ignored",
+			upgradeErrs:    map[string]error{"n4": upgradeErr},
+			unhealthyNodes: []string{"n5"},
+			expected:       map[string]error{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, getFailedUpgradeNodes([]string{"n1", "n2", "n3"}, tt.upgradeErrs, tt.unhealthyNodes, time.Minute))
+		})
+	}
+}
+
+func TestShouldAbortUpgrade(t *testing.T) {
+	tests := []struct {
+		name        string
+		results     map[string]nodeUpgradeResult
+		maxFailures int
+		expected    bool
+	}{
+		{
+			name:        "no results",
+			results:     map[string]nodeUpgradeResult{},
+			maxFailures: 1,
+			expected:    false,
+		},
+		{
+			name: "upgraded and skipped nodes are not failures",
+			results: map[string]nodeUpgradeResult{
+				"n1": {Status: upgradeStatusUpgraded},
+				"n2": {Status: upgradeStatusSkipped},
+			},
+			maxFailures: 1,
+			expected:    false,
+		},
+		{
+			name: "below max failures",
+			results: map[string]nodeUpgradeResult{
+				"n1": {Status: upgradeStatusUpgraded},
+				"n2": {Status: upgradeStatusFailed},
+			},
+			maxFailures: 2,
+			expected:    false,
+		},
+		{
+			name: "at max failures",
+			results: map[string]nodeUpgradeResult{
+				"n1": {Status: upgradeStatusFailed},
+				"n2": {Status: upgradeStatusRolledBack},
+			},
+			maxFailures: 2,
+			expected:    true,
+		},
+		{
+			name: "rolled back nodes count as failures",
+			results: map[string]nodeUpgradeResult{
+				"n1": {Status: upgradeStatusRolledBack},
+				"n2": {Status: upgradeStatusRollbackFailed},
+				"n3": {Status: upgradeStatusRollbackUnhealthy},
+			},
+			maxFailures: 3,
+			expected:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, shouldAbortUpgrade(tt.results, tt.maxFailures))
+		})
+	}
+}
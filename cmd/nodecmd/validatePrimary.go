@@ -3,10 +3,8 @@
 package nodecmd
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"strconv"
 	"time"
@@ -83,27 +81,6 @@ Network.`,
 	return cmd
 }
 
-func parseBootstrappedOutput(filePath string) (bool, error) {
-	jsonFile, err := os.Open(filePath)
-	if err != nil {
-		return false, err
-	}
-	defer jsonFile.Close()
-	byteValue, _ := io.ReadAll(jsonFile)
-	var result map[string]interface{}
-	if err := json.Unmarshal(byteValue, &result); err != nil {
-		return false, err
-	}
-	isBootstrappedInterface, ok := result["result"].(map[string]interface{})
-	if ok {
-		isBootstrapped, ok := isBootstrappedInterface["isBootstrapped"].(bool)
-		if ok {
-			return isBootstrapped, nil
-		}
-	}
-	return false, errors.New("unable to parse node bootstrap status")
-}
-
 func GetMinStakingAmount(network models.Network) (uint64, error) {
 	pClient := platformvm.NewClient(network.Endpoint)
 	ctx, cancel := utils.GetAPIContext()
@@ -301,24 +278,15 @@ func checkClusterIsBootstrapped(clusterName string) ([]string, error) {
 	}
 	notBootstrappedNodes := []string{}
 	ux.Logger.PrintToUser(fmt.Sprintf("Checking if node(s) in cluster %s are bootstrapped to Primary Network ...", clusterName))
-	if err := app.CreateAnsibleStatusDir(); err != nil {
-		return nil, err
-	}
-	if err := ansible.RunAnsiblePlaybookCheckBootstrapped(app.GetAnsibleDir(), app.GetBootstrappedJSONFile(), app.GetAnsibleInventoryDirPath(clusterName), "all"); err != nil {
+	isBootstrapped, err := nodeExecutor.IsBootstrapped(ansibleNodeIDs)
+	if err != nil {
 		return nil, err
 	}
 	for _, ansibleNodeID := range ansibleNodeIDs {
-		isBootstrapped, err := parseBootstrappedOutput(app.GetBootstrappedJSONFile() + "." + ansibleNodeID)
-		if err != nil {
-			return nil, err
-		}
-		if !isBootstrapped {
+		if !isBootstrapped[ansibleNodeID] {
 			notBootstrappedNodes = append(notBootstrappedNodes, ansibleNodeID)
 		}
 	}
-	if err := app.RemoveAnsibleStatusDir(); err != nil {
-		return nil, err
-	}
 	return notBootstrappedNodes, nil
 }
 
@@ -400,7 +368,7 @@ func validatePrimaryNetwork(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	if err := setupAnsible(clusterName); err != nil {
+	if err := setupNodeExecutor(clusterName); err != nil {
 		return err
 	}
 	notBootstrappedNodes, err := checkClusterIsBootstrapped(clusterName)
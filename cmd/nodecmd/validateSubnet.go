@@ -3,11 +3,8 @@
 package nodecmd
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"os"
 	"time"
 
 	"github.com/ava-labs/avalanchego/utils/crypto/keychain"
@@ -60,27 +57,6 @@ You can check the subnet sync status by calling avalanche node status <clusterNa
 	return cmd
 }
 
-func parseSubnetSyncOutput(filePath string) (string, error) {
-	jsonFile, err := os.Open(filePath)
-	if err != nil {
-		return "", err
-	}
-	defer jsonFile.Close()
-	byteValue, _ := io.ReadAll(jsonFile)
-	var result map[string]interface{}
-	if err := json.Unmarshal(byteValue, &result); err != nil {
-		return "", err
-	}
-	statusInterface, ok := result["result"].(map[string]interface{})
-	if ok {
-		status, ok := statusInterface["status"].(string)
-		if ok {
-			return status, nil
-		}
-	}
-	return "", errors.New("unable to parse subnet sync status")
-}
-
 func addNodeAsSubnetValidator(
 	network models.Network,
 	kc keychain.Keychain,
@@ -110,22 +86,13 @@ func addNodeAsSubnetValidator(
 // if getNodeSubnetSyncStatus is called from node validate subnet command, it will fail if
 // node status is not 'syncing'. If getNodeSubnetSyncStatus is called from node status command,
 // it will return true node status is 'syncing'
-func getNodeSubnetSyncStatus(blockchainID, clusterName, ansibleNodeID string) (string, error) {
+func getNodeSubnetSyncStatus(blockchainID, ansibleNodeID string) (string, error) {
 	ux.Logger.PrintToUser("Checking if node %s is synced to subnet ...", ansibleNodeID)
-	if err := app.CreateAnsibleStatusDir(); err != nil {
-		return "", err
-	}
-	if err := ansible.RunAnsiblePlaybookSubnetSyncStatus(app.GetAnsibleDir(), app.GetSubnetSyncJSONFile(), blockchainID, app.GetAnsibleInventoryDirPath(clusterName), ansibleNodeID); err != nil {
-		return "", err
-	}
-	subnetSyncStatus, err := parseSubnetSyncOutput(app.GetSubnetSyncJSONFile() + "." + ansibleNodeID)
+	subnetSyncStatus, err := nodeExecutor.GetSubnetSyncStatus([]string{ansibleNodeID}, blockchainID)
 	if err != nil {
 		return "", err
 	}
-	if err = app.RemoveAnsibleStatusDir(); err != nil {
-		return "", err
-	}
-	return subnetSyncStatus, nil
+	return subnetSyncStatus[ansibleNodeID], nil
 }
 
 func waitForNodeToBePrimaryNetworkValidator(network models.Network, nodeID ids.NodeID) error {
@@ -175,7 +142,7 @@ func validateSubnet(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	if err := setupAnsible(clusterName); err != nil {
+	if err := setupNodeExecutor(clusterName); err != nil {
 		return err
 	}
 	notBootstrappedNodes, err := checkClusterIsBootstrapped(clusterName)
@@ -228,7 +195,7 @@ func validateSubnet(_ *cobra.Command, args []string) error {
 			continue
 		}
 		// we have to check if node is synced to subnet before adding the node as a validator
-		subnetSyncStatus, err := getNodeSubnetSyncStatus(blockchainID.String(), clusterName, ansibleNodeID)
+		subnetSyncStatus, err := getNodeSubnetSyncStatus(blockchainID.String(), ansibleNodeID)
 		if err != nil {
 			ux.Logger.PrintToUser("Failed to get subnet sync status for node %s", ansibleNodeID)
 			failedNodes = append(failedNodes, ansibleNodeID)
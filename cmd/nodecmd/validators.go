@@ -0,0 +1,299 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/cmd/subnetcmd"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/ids"
+	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// validatorRewardUptimePerc is the minimum uptime percentage a Primary Network validator
+	// needs to be rewarded
+	validatorRewardUptimePerc = 80
+	primaryNetworkName        = "Primary Network"
+)
+
+var (
+	validatorsSubnets      []string
+	validatorsUptimeMargin float64
+)
+
+func newValidatorsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validators",
+		Short: "(ALPHA Warning) Suite of commands for the validators of a cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node validators command suite provides a collection of commands related to
+the nodes of a cluster that validate the Primary Network or Subnets.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := cmd.Help()
+			if err != nil {
+				fmt.Println(err)
+			}
+		},
+	}
+	// node validators report
+	cmd.AddCommand(newValidatorsReportCmd())
+	return cmd
+}
+
+func newValidatorsReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report [clusterName]",
+		Short: "(ALPHA Warning) Report the uptime and rewards of the validators of a cluster",
+		Long: `(ALPHA Warning) This command is currently in experimental mode.
+
+The node validators report command shows, for each node of a cluster validating
+the Primary Network or a Subnet deployed on the cluster network, its uptime and
+connected status, stake, end time of the validation, delegations and potential
+reward. Uptime and connected status are the ones seen by the P-Chain API node
+of the network. Delegations and potential reward are only available for the
+Primary Network and elastic subnets, whose amounts are shown in base units of
+the subnet token.
+
+Subnets are taken from the ones deployed to the cluster network, unless given
+with --subnet. The command warns about Primary Network validators whose uptime
+is below the 80% needed to be rewarded, or within --uptime-margin of it.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         validatorsReport,
+	}
+	cmd.Flags().StringSliceVar(&validatorsSubnets, "subnet", nil, "only report the validators of the given subnet(s), besides the Primary Network ones")
+	cmd.Flags().Float64Var(&validatorsUptimeMargin, "uptime-margin", 5, "warn about validators whose uptime percentage is within this margin of the reward threshold")
+	return cmd
+}
+
+// clusterValidator is a validator of a subnet, or of the Primary Network, run by a node of a cluster
+type clusterValidator struct {
+	Node       string
+	SubnetName string
+	Validator  platformvm.ClientPermissionlessValidator
+}
+
+// getClusterSubnets returns the IDs of the subnets of [subnetNames] deployed to [network],
+// or of all the subnets deployed to it if none is given
+func getClusterSubnets(subnetNames []string, network models.Network) (map[string]ids.ID, error) {
+	checkDeployed := len(subnetNames) > 0
+	if !checkDeployed {
+		var err error
+		subnetNames, err = app.GetSidecarNames()
+		if err != nil {
+			return nil, err
+		}
+	}
+	subnetIDs := map[string]ids.ID{}
+	for _, subnetName := range subnetNames {
+		sc, err := app.LoadSidecar(subnetName)
+		if err != nil {
+			return nil, err
+		}
+		subnetID := sc.Networks[network.Name()].SubnetID
+		if subnetID == ids.Empty {
+			if checkDeployed {
+				return nil, fmt.Errorf("subnet %s is not deployed to %s", subnetName, network.Name())
+			}
+			continue
+		}
+		subnetIDs[subnetName] = subnetID
+	}
+	return subnetIDs, nil
+}
+
+// getClusterValidators returns the current validators of the Primary Network and of [subnetIDs]
+// that are nodes of [clusterName]
+func getClusterValidators(clusterName string, network models.Network, subnetIDs map[string]ids.ID) ([]clusterValidator, error) {
+	hostIDs, err := getClusterHostIDs(clusterName, "")
+	if err != nil {
+		return nil, err
+	}
+	nodeIDs := []ids.NodeID{}
+	nodes := map[ids.NodeID]string{}
+	for _, hostID := range hostIDs {
+		_, cloudID, err := models.HostAnsibleIDToCloudID(hostID)
+		if err != nil {
+			return nil, err
+		}
+		nodeID, err := getNodeID(app.GetNodeInstanceDirPath(cloudID))
+		if err != nil {
+			return nil, err
+		}
+		nodeIDs = append(nodeIDs, nodeID)
+		nodes[nodeID] = cloudID
+	}
+	subnetNames := []string{}
+	for subnetName := range subnetIDs {
+		subnetNames = append(subnetNames, subnetName)
+	}
+	sort.Strings(subnetNames)
+	pClient := platformvm.NewClient(network.Endpoint)
+	validators := []clusterValidator{}
+	for _, subnetName := range append([]string{primaryNetworkName}, subnetNames...) {
+		subnetID := avagoconstants.PrimaryNetworkID
+		if subnetName != primaryNetworkName {
+			subnetID = subnetIDs[subnetName]
+		}
+		ctx, cancel := utils.GetAPIContext()
+		subnetValidators, err := pClient.GetCurrentValidators(ctx, subnetID, nodeIDs)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the validators of %s from %s: %w", subnetName, network.Endpoint, err)
+		}
+		for _, validator := range subnetValidators {
+			node, ok := nodes[validator.NodeID]
+			if !ok {
+				continue
+			}
+			validators = append(validators, clusterValidator{Node: node, SubnetName: subnetName, Validator: validator})
+		}
+	}
+	return validators, nil
+}
+
+func formatAvax(nAvax uint64) string {
+	return fmt.Sprintf("%.9f", float64(nAvax)/float64(units.Avax))
+}
+
+// formatValidatorAmount formats a stake or reward [amount] of [subnetName], in AVAX for the
+// Primary Network and in base units of the subnet token otherwise
+func formatValidatorAmount(subnetName string, amount uint64) string {
+	if subnetName == primaryNetworkName {
+		return formatAvax(amount) + " AVAX"
+	}
+	return fmt.Sprintf("%d", amount)
+}
+
+func formatValidatorConnected(v clusterValidator) string {
+	if v.Validator.Connected == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%t", *v.Validator.Connected)
+}
+
+func formatValidatorUptime(v clusterValidator) string {
+	if v.Validator.Uptime == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.2f%%", *v.Validator.Uptime)
+}
+
+// formatValidatorDelegations formats the delegations of [v], which are only set by the API
+// for Primary Network and elastic subnet validators
+func formatValidatorDelegations(v clusterValidator) string {
+	if v.Validator.DelegatorCount == nil || v.Validator.DelegatorWeight == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%d (%s)", *v.Validator.DelegatorCount, formatValidatorAmount(v.SubnetName, *v.Validator.DelegatorWeight))
+}
+
+// formatValidatorPotentialReward formats the potential reward of [v], which is only set by the API
+// for Primary Network and elastic subnet validators
+func formatValidatorPotentialReward(v clusterValidator) string {
+	if v.Validator.PotentialReward == nil {
+		return "n/a"
+	}
+	return formatValidatorAmount(v.SubnetName, *v.Validator.PotentialReward)
+}
+
+func printValidatorsReport(validators []clusterValidator) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Node", "NodeID", "Subnet", "Connected", "Uptime", "Stake", "End Time", "Delegations", "Potential Reward"})
+	table.SetRowLine(true)
+	for _, v := range validators {
+		endTime := time.Unix(int64(v.Validator.EndTime), 0)
+		endTimeStr := fmt.Sprintf("%s (in %s)", endTime.UTC().Format(time.RFC3339), time.Until(endTime).Round(time.Minute))
+		table.Append([]string{
+			v.Node,
+			v.Validator.NodeID.String(),
+			v.SubnetName,
+			formatValidatorConnected(v),
+			formatValidatorUptime(v),
+			formatValidatorAmount(v.SubnetName, v.Validator.Weight),
+			endTimeStr,
+			formatValidatorDelegations(v),
+			formatValidatorPotentialReward(v),
+		})
+	}
+	table.Render()
+}
+
+// getValidatorsAtRisk returns warnings about the Primary Network [validators] that are not connected,
+// or whose uptime is below the reward threshold, or within [uptimeMargin] of it
+func getValidatorsAtRisk(validators []clusterValidator, uptimeMargin float64) []string {
+	warnings := []string{}
+	for _, v := range validators {
+		if v.SubnetName != primaryNetworkName {
+			continue
+		}
+		if v.Validator.Connected != nil && !*v.Validator.Connected {
+			warnings = append(warnings, fmt.Sprintf("validator %s on node %s is not connected", v.Validator.NodeID, v.Node))
+		}
+		if v.Validator.Uptime == nil {
+			continue
+		}
+		uptime := float64(*v.Validator.Uptime)
+		switch {
+		case uptime < validatorRewardUptimePerc:
+			warnings = append(warnings, fmt.Sprintf("validator %s on node %s has an uptime of %.2f%%, below the %d%% needed to be rewarded",
+				v.Validator.NodeID, v.Node, uptime, validatorRewardUptimePerc))
+		case uptime < validatorRewardUptimePerc+uptimeMargin:
+			warnings = append(warnings, fmt.Sprintf("validator %s on node %s has an uptime of %.2f%%, close to the %d%% needed to be rewarded",
+				v.Validator.NodeID, v.Node, uptime, validatorRewardUptimePerc))
+		}
+	}
+	return warnings
+}
+
+// warnValidatorsAtRisk warns about the Primary Network [validators] that are not connected,
+// or whose uptime is below, or close to, the reward threshold
+func warnValidatorsAtRisk(validators []clusterValidator) {
+	for _, warning := range getValidatorsAtRisk(validators, validatorsUptimeMargin) {
+		ux.Logger.PrintToUser("WARNING: %s", warning)
+	}
+}
+
+func validatorsReport(_ *cobra.Command, args []string) error {
+	clusterName := args[0]
+	if err := checkCluster(clusterName); err != nil {
+		return err
+	}
+	for _, subnetName := range validatorsSubnets {
+		if _, err := subnetcmd.ValidateSubnetNameAndGetChains([]string{subnetName}); err != nil {
+			return err
+		}
+	}
+	clustersConfig, err := app.LoadClustersConfig()
+	if err != nil {
+		return err
+	}
+	network := clustersConfig.Clusters[clusterName].Network
+	subnetIDs, err := getClusterSubnets(validatorsSubnets, network)
+	if err != nil {
+		return err
+	}
+	validators, err := getClusterValidators(clusterName, network, subnetIDs)
+	if err != nil {
+		return err
+	}
+	if len(validators) == 0 {
+		ux.Logger.PrintToUser("No node of cluster %s is a validator on %s", clusterName, network.Name())
+		return nil
+	}
+	printValidatorsReport(validators)
+	warnValidatorsAtRisk(validators)
+	return nil
+}
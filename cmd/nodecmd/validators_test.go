@@ -0,0 +1,264 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package nodecmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/internal/testutils"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/platformvm"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClusterValidator(subnetName string, connected *bool, uptime *float32) clusterValidator {
+	return clusterValidator{
+		Node:       "node1",
+		SubnetName: subnetName,
+		Validator: platformvm.ClientPermissionlessValidator{
+			ClientStaker: platformvm.ClientStaker{NodeID: ids.EmptyNodeID},
+			Connected:    connected,
+			Uptime:       uptime,
+		},
+	}
+}
+
+func TestGetValidatorsAtRisk(t *testing.T) {
+	connected, disconnected := true, false
+	uptime := func(perc float32) *float32 {
+		return &perc
+	}
+	nodeID := ids.EmptyNodeID
+	below := func(perc float64) string {
+		return fmt.Sprintf("validator %s on node node1 has an uptime of %.2f%%, below the 80%% needed to be rewarded", nodeID, perc)
+	}
+	closeTo := func(perc float64) string {
+		return fmt.Sprintf("validator %s on node node1 has an uptime of %.2f%%, close to the 80%% needed to be rewarded", nodeID, perc)
+	}
+	notConnected := fmt.Sprintf("validator %s on node node1 is not connected", nodeID)
+	tests := []struct {
+		name       string
+		validators []clusterValidator
+		margin     float64
+		expected   []string
+	}{
+		{
+			name:       "no validators",
+			validators: []clusterValidator{},
+			margin:     5,
+			expected:   []string{},
+		},
+		{
+			name:       "above the margin",
+			validators: []clusterValidator{newTestClusterValidator(primaryNetworkName, &connected, uptime(90))},
+			margin:     5,
+			expected:   []string{},
+		},
+		{
+			name:       "at the margin",
+			validators: []clusterValidator{newTestClusterValidator(primaryNetworkName, &connected, uptime(85))},
+			margin:     5,
+			expected:   []string{},
+		},
+		{
+			name:       "within the margin",
+			validators: []clusterValidator{newTestClusterValidator(primaryNetworkName, &connected, uptime(84.5))},
+			margin:     5,
+			expected:   []string{closeTo(84.5)},
+		},
+		{
+			name:       "at the threshold",
+			validators: []clusterValidator{newTestClusterValidator(primaryNetworkName, &connected, uptime(80))},
+			margin:     5,
+			expected:   []string{closeTo(80)},
+		},
+		{
+			name:       "at the threshold without margin",
+			validators: []clusterValidator{newTestClusterValidator(primaryNetworkName, &connected, uptime(80))},
+			margin:     0,
+			expected:   []string{},
+		},
+		{
+			name:       "below the threshold",
+			validators: []clusterValidator{newTestClusterValidator(primaryNetworkName, &connected, uptime(79.5))},
+			margin:     5,
+			expected:   []string{below(79.5)},
+		},
+		{
+			name:       "not connected and below the threshold",
+			validators: []clusterValidator{newTestClusterValidator(primaryNetworkName, &disconnected, uptime(50))},
+			margin:     5,
+			expected:   []string{notConnected, below(50)},
+		},
+		{
+			name:       "not connected without uptime",
+			validators: []clusterValidator{newTestClusterValidator(primaryNetworkName, &disconnected, nil)},
+			margin:     5,
+			expected:   []string{notConnected},
+		},
+		{
+			name:       "unknown connected status and uptime",
+			validators: []clusterValidator{newTestClusterValidator(primaryNetworkName, nil, nil)},
+			margin:     5,
+			expected:   []string{},
+		},
+		{
+			name:       "subnet validators are ignored",
+			validators: []clusterValidator{newTestClusterValidator("subnet1", &disconnected, uptime(50))},
+			margin:     5,
+			expected:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, getValidatorsAtRisk(tt.validators, tt.margin))
+		})
+	}
+}
+
+func TestGetClusterSubnets(t *testing.T) {
+	app = testutils.SetupTestInTempDir(t)
+	deployedID := ids.GenerateTestID()
+	otherNetworkID := ids.GenerateTestID()
+	require.NoError(t, app.CreateSidecar(&models.Sidecar{
+		Name:     "deployed",
+		Networks: map[string]models.NetworkData{models.FujiNetwork.Name(): {SubnetID: deployedID}},
+	}))
+	require.NoError(t, app.CreateSidecar(&models.Sidecar{
+		Name:     "otherNetwork",
+		Networks: map[string]models.NetworkData{models.MainnetNetwork.Name(): {SubnetID: otherNetworkID}},
+	}))
+	require.NoError(t, app.CreateSidecar(&models.Sidecar{Name: "notDeployed"}))
+
+	tests := []struct {
+		name        string
+		subnetNames []string
+		expected    map[string]ids.ID
+		expectedErr string
+	}{
+		{
+			name:        "all subnets deployed to the network",
+			subnetNames: nil,
+			expected:    map[string]ids.ID{"deployed": deployedID},
+		},
+		{
+			name:        "given deployed subnet",
+			subnetNames: []string{"deployed"},
+			expected:    map[string]ids.ID{"deployed": deployedID},
+		},
+		{
+			name:        "given subnet deployed to another network",
+			subnetNames: []string{"deployed", "otherNetwork"},
+			expectedErr: "subnet otherNetwork is not deployed to Fuji",
+		},
+		{
+			name:        "given subnet not deployed",
+			subnetNames: []string{"notDeployed"},
+			expectedErr: "subnet notDeployed is not deployed to Fuji",
+		},
+		{
+			name:        "given subnet doesn't exist",
+			subnetNames: []string{"unknown"},
+			expectedErr: "no such file or directory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subnetIDs, err := getClusterSubnets(tt.subnetNames, models.FujiNetwork)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, subnetIDs)
+		})
+	}
+}
+
+func TestFormatValidator(t *testing.T) {
+	connected := true
+	uptime := float32(99.456)
+	delegatorCount := uint64(2)
+	delegatorWeight := 25 * units.Avax
+	potentialReward := units.Avax / 2
+	tests := []struct {
+		name                    string
+		subnetName              string
+		connected               *bool
+		uptime                  *float32
+		delegatorCount          *uint64
+		delegatorWeight         *uint64
+		potentialReward         *uint64
+		expectedConnected       string
+		expectedUptime          string
+		expectedStake           string
+		expectedDelegations     string
+		expectedPotentialReward string
+	}{
+		{
+			name:                    "primary network validator",
+			subnetName:              primaryNetworkName,
+			connected:               &connected,
+			uptime:                  &uptime,
+			delegatorCount:          &delegatorCount,
+			delegatorWeight:         &delegatorWeight,
+			potentialReward:         &potentialReward,
+			expectedConnected:       "true",
+			expectedUptime:          "99.46%",
+			expectedStake:           "2000.000000000 AVAX",
+			expectedDelegations:     "2 (25.000000000 AVAX)",
+			expectedPotentialReward: "0.500000000 AVAX",
+		},
+		{
+			name:                    "elastic subnet validator",
+			subnetName:              "elastic",
+			connected:               &connected,
+			uptime:                  &uptime,
+			delegatorCount:          &delegatorCount,
+			delegatorWeight:         &delegatorWeight,
+			potentialReward:         &potentialReward,
+			expectedConnected:       "true",
+			expectedUptime:          "99.46%",
+			expectedStake:           "2000000000000",
+			expectedDelegations:     "2 (25000000000)",
+			expectedPotentialReward: "500000000",
+		},
+		{
+			name:                    "permissioned subnet validator",
+			subnetName:              "permissioned",
+			expectedConnected:       "n/a",
+			expectedUptime:          "n/a",
+			expectedStake:           "2000000000000",
+			expectedDelegations:     "n/a",
+			expectedPotentialReward: "n/a",
+		},
+		{
+			name:                    "delegator count without weight",
+			subnetName:              primaryNetworkName,
+			delegatorCount:          &delegatorCount,
+			expectedConnected:       "n/a",
+			expectedUptime:          "n/a",
+			expectedStake:           "2000.000000000 AVAX",
+			expectedDelegations:     "n/a",
+			expectedPotentialReward: "n/a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			v := newTestClusterValidator(tt.subnetName, tt.connected, tt.uptime)
+			v.Validator.Weight = 2000 * units.Avax
+			v.Validator.DelegatorCount = tt.delegatorCount
+			v.Validator.DelegatorWeight = tt.delegatorWeight
+			v.Validator.PotentialReward = tt.potentialReward
+			require.Equal(tt.expectedConnected, formatValidatorConnected(v))
+			require.Equal(tt.expectedUptime, formatValidatorUptime(v))
+			require.Equal(tt.expectedStake, formatValidatorAmount(v.SubnetName, v.Validator.Weight))
+			require.Equal(tt.expectedDelegations, formatValidatorDelegations(v))
+			require.Equal(tt.expectedPotentialReward, formatValidatorPotentialReward(v))
+		})
+	}
+}
@@ -133,7 +133,7 @@ func addValidator(_ *cobra.Command, _ []string) error {
 		if err != nil {
 			return err
 		}
-		network = models.NetworkFromString(networkStr)
+		network = app.GetNetworkFromString(networkStr)
 	}
 
 	if len(ledgerAddresses) > 0 {
@@ -163,7 +163,7 @@ func addValidator(_ *cobra.Command, _ []string) error {
 
 	// used in E2E to simulate public network execution paths on a local network
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	}
 
 	if nodeIDStr == "" {
@@ -26,6 +26,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/internal/migrations"
 	"github.com/ava-labs/avalanche-cli/pkg/apmintegration"
 	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
 	"github.com/ava-labs/avalanche-cli/pkg/config"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/metrics"
@@ -45,6 +46,8 @@ var (
 	Version   = ""
 	cfgFile   string
 	skipCheck bool
+
+	localNetworkName string
 )
 
 func NewRootCmd() *cobra.Command {
@@ -68,6 +71,7 @@ in with avalanche subnet create myNewSubnet.`,
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.avalanche-cli/config.json)")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "ERROR", "log level for the application")
 	rootCmd.PersistentFlags().BoolVar(&skipCheck, constants.SkipUpdateFlag, false, "skip check for new versions")
+	rootCmd.PersistentFlags().StringVar(&localNetworkName, constants.LocalNetworkFlag, "", "name of the local network instance to operate on (default instance if not given)")
 
 	// add sub commands
 	rootCmd.AddCommand(subnetcmd.NewCmd(app))
@@ -103,6 +107,10 @@ func createApp(cmd *cobra.Command, _ []string) error {
 	}
 	cf := config.New()
 	app.Setup(baseDir, log, cf, prompts.NewPrompter(), application.NewDownloader())
+	if err := app.SetLocalNetwork(localNetworkName); err != nil {
+		return err
+	}
+	binutils.SetGRPCPortsOffset(app.GetLocalNetworkPortsOffset())
 
 	// Setup APM, skip if running a hidden command
 	if !cmd.Hidden {
@@ -326,6 +334,11 @@ func Execute() {
 	rootCmd := NewRootCmd()
 	err := rootCmd.Execute()
 	if err != nil {
+		// commands meant to be used from scripts may exit with their own codes
+		var exitCodeErr interface{ ExitCode() int }
+		if errors.As(err, &exitCodeErr) {
+			os.Exit(exitCodeErr.ExitCode())
+		}
 		os.Exit(1)
 	}
 }
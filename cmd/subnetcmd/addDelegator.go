@@ -98,7 +98,7 @@ func addPermissionlessDelegator(_ *cobra.Command, args []string) error {
 	}
 	subnetID := sc.Networks[network.Name()].SubnetID
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		subnetID = sc.Networks[models.Local.String()].SubnetID
+		subnetID = sc.Networks[app.GetLocalNetwork().Name()].SubnetID
 	}
 	if subnetID == ids.Empty {
 		return errNoSubnetID
@@ -134,7 +134,7 @@ func addPermissionlessDelegator(_ *cobra.Command, args []string) error {
 
 	// used in E2E to simulate public network execution paths on a local network
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	}
 
 	// get keychain accessor
@@ -184,7 +184,7 @@ func handleAddPermissionlessDelegatorLocal(subnetName string, network models.Net
 	testKey := genesis.EWOQKey
 	keyChain := secp256k1fx.NewKeychain(testKey)
 	subnetID := sc.Networks[network.Name()].SubnetID
-	txID, err := subnet.IssueAddPermissionlessDelegatorTx(keyChain, subnetID, nodeID, stakedTokenAmount, assetID, uint64(start.Unix()), uint64(endTime.Unix()))
+	txID, err := subnet.IssueAddPermissionlessDelegatorTx(app.GetLocalNetwork(), keyChain, subnetID, nodeID, stakedTokenAmount, assetID, uint64(start.Unix()), uint64(endTime.Unix()))
 	if err != nil {
 		return err
 	}
@@ -84,6 +84,9 @@ func addValidator(_ *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if err := SetDevnetNetworkID(&network); err != nil {
+		return err
+	}
 	kc, err := GetKeychainFromCmdLineFlags(
 		constants.PayTxsFeesMsg,
 		network,
@@ -123,7 +126,7 @@ func CallAddValidator(
 
 	// used in E2E to simulate public network execution paths on a local network
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	}
 
 	_, err = ValidateSubnetNameAndGetChains([]string{subnetName})
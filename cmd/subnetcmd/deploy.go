@@ -78,10 +78,11 @@ At the end of the call, the command prints the RPC URL you can use to interact w
 
 Avalanche-CLI only supports deploying an individual Subnet once per network. Subsequent
 attempts to deploy the same Subnet to the same network (local, Fuji, Mainnet) aren't
-allowed. If you'd like to redeploy a Subnet locally for testing, you must first call
-avalanche network clean to reset all deployed chain state. Subsequent local deploys
-redeploy the chain with fresh state. You can deploy the same Subnet to multiple networks,
-so you can take your locally tested Subnet and deploy it on Fuji or Mainnet.`,
+allowed. If you'd like to redeploy a Subnet locally for testing, for example after
+changing its genesis, call avalanche subnet redeploy --local, or first call avalanche network
+clean to reset all deployed chain state. Subsequent local deploys redeploy the chain with fresh
+state. You can deploy the same Subnet to multiple networks, so you can take your locally
+tested Subnet and deploy it on Fuji or Mainnet.`,
 		SilenceUsage:      true,
 		RunE:              deploySubnet,
 		PersistentPostRun: handlePostRun,
@@ -300,6 +301,9 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if err := SetDevnetNetworkID(&network); err != nil {
+		return err
+	}
 
 	if network.Kind == models.Mainnet || os.Getenv(constants.SimulatePublicNetwork) != "" {
 		err = handleMainnetChainID(chain)
@@ -356,7 +360,7 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 		}
 
 		// check if selected version matches what is currently running
-		nc := localnetworkinterface.NewStatusChecker()
+		nc := localnetworkinterface.NewStatusChecker(app.GetLocalNetwork())
 		userProvidedAvagoVersion, err = CheckForInvalidDeployAndGetAvagoVersion(nc, sidecar.RPCVersion)
 		if err != nil {
 			return err
@@ -394,7 +398,7 @@ func deploySubnet(cmd *cobra.Command, args []string) error {
 
 	// used in E2E to simulate public network execution paths on a local network
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	}
 
 	createSubnet := true
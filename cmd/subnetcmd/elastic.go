@@ -82,7 +82,7 @@ mechanics will work.`,
 }
 
 func checkIfSubnetIsElasticOnLocal(sc models.Sidecar) bool {
-	if _, ok := sc.ElasticSubnet[models.Local.String()]; ok {
+	if _, ok := sc.ElasticSubnet[app.GetLocalNetwork().Name()]; ok {
 		return true
 	}
 	return false
@@ -194,7 +194,7 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 	case deployMainnet:
 		network = models.MainnetNetwork
 	case transformLocal:
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	}
 
 	if network.Kind == models.Undefined {
@@ -204,7 +204,7 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 		}
 		switch networkToUpgrade {
 		case localDeployment:
-			network = models.LocalNetwork
+			network = app.GetLocalNetwork()
 		case fujiDeployment:
 			network = models.FujiNetwork
 		default:
@@ -228,7 +228,7 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 
 	subnetID := sc.Networks[network.Name()].SubnetID
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		subnetID = sc.Networks[models.Local.String()].SubnetID
+		subnetID = sc.Networks[app.GetLocalNetwork().Name()].SubnetID
 	}
 	if subnetID == ids.Empty {
 		return errNoSubnetID
@@ -299,7 +299,7 @@ func transformElasticSubnet(cmd *cobra.Command, args []string) error {
 	}
 	// used in E2E to simulate public network execution paths on a local network
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	}
 
 	// get keychain accessor
@@ -425,7 +425,7 @@ func transformElasticSubnetLocal(sc models.Sidecar, subnetName string, tokenName
 		return fmt.Errorf("%s is already an elastic subnet", subnetName)
 	}
 	var err error
-	subnetID := sc.Networks[models.Local.String()].SubnetID
+	subnetID := sc.Networks[app.GetLocalNetwork().Name()].SubnetID
 	if subnetID == ids.Empty {
 		return errNoSubnetID
 	}
@@ -445,7 +445,7 @@ func transformElasticSubnetLocal(sc models.Sidecar, subnetName string, tokenName
 	go ux.PrintWait(cancel)
 	testKey := genesis.EWOQKey
 	keyChain := secp256k1fx.NewKeychain(testKey)
-	txID, assetID, err := subnet.IssueTransformSubnetTx(elasticSubnetConfig, keyChain, subnetID, tokenName, tokenSymbol, elasticSubnetConfig.MaxSupply)
+	txID, assetID, err := subnet.IssueTransformSubnetTx(app.GetLocalNetwork(), elasticSubnetConfig, keyChain, subnetID, tokenName, tokenSymbol, elasticSubnetConfig.MaxSupply)
 	close(cancel)
 	if err != nil {
 		return err
@@ -457,7 +457,7 @@ func transformElasticSubnetLocal(sc models.Sidecar, subnetName string, tokenName
 	if err = app.CreateElasticSubnetConfig(subnetName, &elasticSubnetConfig); err != nil {
 		return err
 	}
-	if err = app.UpdateSidecarElasticSubnet(&sc, models.LocalNetwork, subnetID, assetID, txID, tokenName, tokenSymbol); err != nil {
+	if err = app.UpdateSidecarElasticSubnet(&sc, app.GetLocalNetwork(), subnetID, assetID, txID, tokenName, tokenSymbol); err != nil {
 		return fmt.Errorf("elastic subnet transformation was successful, but failed to update sidecar: %w", err)
 	}
 
@@ -495,7 +495,7 @@ func promptNetworkElastic(sc models.Sidecar, prompt string) (string, error) {
 	var networkOptions []string
 	for network := range sc.Networks {
 		switch network {
-		case models.Local.String():
+		case app.GetLocalNetwork().Name():
 			networkOptions = append(networkOptions, localDeployment)
 		case models.Fuji.String():
 			networkOptions = append(networkOptions, fujiDeployment)
@@ -519,7 +519,7 @@ func getNetworkOptions(sc models.Sidecar) []string {
 	var networkOptions []string
 	for network := range sc.Networks {
 		switch network {
-		case models.Local.String():
+		case app.GetLocalNetwork().Name():
 			networkOptions = append(networkOptions, localDeployment)
 		case models.Fuji.String():
 			networkOptions = append(networkOptions, fujiDeployment)
@@ -586,7 +586,7 @@ func getTokenSymbol() (string, error) {
 }
 
 func checkAllLocalNodesAreCurrentValidators(subnetID ids.ID) error {
-	api := constants.LocalAPIEndpoint
+	api := app.GetLocalNetwork().Endpoint
 	pClient := platformvm.NewClient(api)
 
 	ctx := context.Background()
@@ -613,12 +613,12 @@ func checkAllLocalNodesAreCurrentValidators(subnetID ids.ID) error {
 }
 
 func transformValidatorsToPermissionlessLocal(sc models.Sidecar, subnetID ids.ID, subnetName string) error {
-	stakedTokenAmount, err := promptStakeAmount(subnetName, true, models.LocalNetwork)
+	stakedTokenAmount, err := promptStakeAmount(subnetName, true, app.GetLocalNetwork())
 	if err != nil {
 		return err
 	}
 
-	validators, err := subnet.GetSubnetValidators(subnetID)
+	validators, err := subnet.GetSubnetValidators(app.GetLocalNetwork(), subnetID)
 	if err != nil {
 		return err
 	}
@@ -656,18 +656,18 @@ func handleRemoveAndAddValidators(sc models.Sidecar, subnetID ids.ID, validator
 	endTime := startTime.Add(genesis.MainnetParams.MinStakeDuration)
 	testKey := genesis.EWOQKey
 	keyChain := secp256k1fx.NewKeychain(testKey)
-	_, err := subnet.IssueRemoveSubnetValidatorTx(keyChain, subnetID, validator)
+	_, err := subnet.IssueRemoveSubnetValidatorTx(app.GetLocalNetwork(), keyChain, subnetID, validator)
 	if err != nil {
 		return err
 	}
 	ux.Logger.PrintToUser(fmt.Sprintf("Validator %s removed", validator.String()))
-	assetID := sc.ElasticSubnet[models.Local.String()].AssetID
-	txID, err := subnet.IssueAddPermissionlessValidatorTx(keyChain, subnetID, validator, stakedAmount, assetID, uint64(startTime.Unix()), uint64(endTime.Unix()))
+	assetID := sc.ElasticSubnet[app.GetLocalNetwork().Name()].AssetID
+	txID, err := subnet.IssueAddPermissionlessValidatorTx(app.GetLocalNetwork(), keyChain, subnetID, validator, stakedAmount, assetID, uint64(startTime.Unix()), uint64(endTime.Unix()))
 	if err != nil {
 		return err
 	}
 	ux.Logger.PrintToUser(fmt.Sprintf("%s successfully joined elastic subnet as permissionless validator!", validator.String()))
-	if err = app.UpdateSidecarPermissionlessValidator(&sc, models.LocalNetwork, validator.String(), txID); err != nil {
+	if err = app.UpdateSidecarPermissionlessValidator(&sc, app.GetLocalNetwork(), validator.String(), txID); err != nil {
 		return fmt.Errorf("joining permissionless subnet was successful, but failed to update sidecar: %w", err)
 	}
 	return nil
@@ -81,7 +81,7 @@ func exportSubnet(_ *cobra.Command, args []string) error {
 	network := models.UndefinedNetwork
 	switch {
 	case deployLocal:
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	case deployDevnet:
 		network = models.DevnetNetwork
 	case deployTestnet:
@@ -97,7 +97,7 @@ func exportSubnet(_ *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
-		network = models.NetworkFromString(networkStr)
+		network = app.GetNetworkFromString(networkStr)
 	}
 
 	subnetName := args[0]
@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
 	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanchego/api/info"
 	"github.com/ava-labs/avalanchego/utils/crypto/keychain"
 	"github.com/ava-labs/avalanchego/utils/crypto/ledger"
 	"github.com/ava-labs/avalanchego/utils/formatting/address"
@@ -40,6 +41,22 @@ func fillNetworkDetails(network *models.Network) error {
 	return nil
 }
 
+// SetDevnetNetworkID sets the ID of the Devnet [network] to the one its endpoint runs with, as
+// Devnets can be created with a custom network ID
+func SetDevnetNetworkID(network *models.Network) error {
+	if network.Kind != models.Devnet || network.Endpoint == "" {
+		return nil
+	}
+	ctx, cancel := utils.GetAPIContext()
+	defer cancel()
+	networkID, err := info.NewClient(network.Endpoint).GetNetworkID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the network ID of the Devnet at %s: %w", network.Endpoint, err)
+	}
+	network.ID = networkID
+	return nil
+}
+
 func GetNetworkFromCmdLineFlags(
 	useLocal bool,
 	useDevnet bool,
@@ -52,7 +69,7 @@ func GetNetworkFromCmdLineFlags(
 	network := models.UndefinedNetwork
 	switch {
 	case useLocal:
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	case useDevnet:
 		network = models.DevnetNetwork
 	case useFuji:
@@ -74,7 +91,7 @@ func GetNetworkFromCmdLineFlags(
 		if err != nil {
 			return models.UndefinedNetwork, err
 		}
-		network = models.NetworkFromString(networkStr)
+		network = app.GetNetworkFromString(networkStr)
 		if err := fillNetworkDetails(&network); err != nil {
 			return models.UndefinedNetwork, err
 		}
@@ -154,7 +171,7 @@ func GetKeychainFromCmdLineFlags(
 
 	// will use default local keychain if simulating public network opeations on local
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	}
 
 	// get keychain accessor
@@ -90,7 +90,7 @@ func importRunningSubnet(*cobra.Command, []string) error {
 		if err != nil {
 			return err
 		}
-		network = models.NetworkFromString(networkStr)
+		network = app.GetNetworkFromString(networkStr)
 	}
 
 	if genesisFilePath == "" {
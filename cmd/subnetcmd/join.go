@@ -119,7 +119,7 @@ func joinCmd(_ *cobra.Command, args []string) error {
 	network := models.UndefinedNetwork
 	switch {
 	case deployLocal:
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	case deployDevnet:
 		network = models.DevnetNetwork
 	case deployTestnet:
@@ -136,7 +136,7 @@ func joinCmd(_ *cobra.Command, args []string) error {
 			}
 			switch selectedNetwork {
 			case localDeployment:
-				network = models.LocalNetwork
+				network = app.GetLocalNetwork()
 			case fujiDeployment:
 				network = models.FujiNetwork
 			case mainnetDeployment:
@@ -150,7 +150,7 @@ func joinCmd(_ *cobra.Command, args []string) error {
 			if err != nil {
 				return err
 			}
-			network = models.NetworkFromString(networkStr)
+			network = app.GetNetworkFromString(networkStr)
 		}
 	}
 
@@ -160,7 +160,7 @@ func joinCmd(_ *cobra.Command, args []string) error {
 
 	// used in E2E to simulate public network execution paths on a local network
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	}
 
 	subnetID := sc.Networks[network.Name()].SubnetID
@@ -391,7 +391,7 @@ func handleValidatorJoinElasticSubnet(sc models.Sidecar, network models.Network,
 
 	subnetID := sc.Networks[network.Name()].SubnetID
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		subnetID = sc.Networks[models.Local.String()].SubnetID
+		subnetID = sc.Networks[app.GetLocalNetwork().Name()].SubnetID
 	}
 	if subnetID == ids.Empty {
 		return errNoSubnetID
@@ -429,7 +429,7 @@ func handleValidatorJoinElasticSubnet(sc models.Sidecar, network models.Network,
 	}
 	// used in E2E to simulate public network execution paths on a local network
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	}
 
 	// get keychain accessor
@@ -487,16 +487,16 @@ func handleValidatorJoinElasticSubnetLocal(sc models.Sidecar, network models.Net
 	if !checkIfSubnetIsElasticOnLocal(sc) {
 		return fmt.Errorf("%s is not an elastic subnet", subnetName)
 	}
-	assetID := sc.ElasticSubnet[models.Local.String()].AssetID
+	assetID := sc.ElasticSubnet[app.GetLocalNetwork().Name()].AssetID
 	testKey := genesis.EWOQKey
 	keyChain := secp256k1fx.NewKeychain(testKey)
-	subnetID := sc.Networks[models.Local.String()].SubnetID
-	txID, err := subnet.IssueAddPermissionlessValidatorTx(keyChain, subnetID, nodeID, stakedTokenAmount, assetID, uint64(start.Unix()), uint64(endTime.Unix()))
+	subnetID := sc.Networks[app.GetLocalNetwork().Name()].SubnetID
+	txID, err := subnet.IssueAddPermissionlessValidatorTx(app.GetLocalNetwork(), keyChain, subnetID, nodeID, stakedTokenAmount, assetID, uint64(start.Unix()), uint64(endTime.Unix()))
 	if err != nil {
 		return err
 	}
 	printAddPermissionlessValOutput(txID, nodeID, network, start, endTime, stakedTokenAmount)
-	if err = app.UpdateSidecarPermissionlessValidator(&sc, models.LocalNetwork, nodeID.String(), txID); err != nil {
+	if err = app.UpdateSidecarPermissionlessValidator(&sc, app.GetLocalNetwork(), nodeID.String(), txID); err != nil {
 		return fmt.Errorf("joining permissionless subnet was successful, but failed to update sidecar: %w", err)
 	}
 	return nil
@@ -579,7 +579,7 @@ func promptNodeIDToAdd(subnetID ids.ID, isValidator bool, network models.Network
 			return ids.EmptyNodeID, err
 		}
 		// Get NodeIDs of all validators on the subnet
-		validators, err := subnet.GetSubnetValidators(subnetID)
+		validators, err := subnet.GetSubnetValidators(app.GetLocalNetwork(), subnetID)
 		if err != nil {
 			return ids.EmptyNodeID, err
 		}
@@ -637,7 +637,7 @@ func promptStakeAmount(subnetName string, isValidator bool, network models.Netwo
 		if err != nil {
 			return 0, err
 		}
-		pClient := platformvm.NewClient(constants.LocalAPIEndpoint)
+		pClient := platformvm.NewClient(app.GetLocalNetwork().Endpoint)
 		walletBalance, err := getAssetBalance(pClient, ewoqPChainAddr, esc.AssetID)
 		if err != nil {
 			return 0, err
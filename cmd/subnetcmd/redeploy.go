@@ -0,0 +1,149 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanche-cli/pkg/binutils"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/subnet"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/local"
+	"github.com/ava-labs/avalanche-network-runner/server"
+	anrutils "github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var redeployLocal bool
+
+// avalanche subnet redeploy
+func newRedeployCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "redeploy [subnetName]",
+		Short: "Redeploys a subnet configuration to the local network",
+		Long: `The subnet redeploy command removes a Subnet from the local network and deploys it again
+with its current configuration, for example after changing its genesis.
+
+Blockchains can't be removed from a running network, so the local network is stopped into its
+snapshot, and the nodes are set to stop running the blockchain of the given Subnet. Its VM
+plugin and deploy info are removed, while the other Subnets keep their IDs and state, and
+are restored when the network is restarted by the new deploy.
+
+The removed blockchain is still registered on the P-Chain, and Subnets sharing their local
+Subnet ID with another one can't be redeployed alone.`,
+		SilenceUsage: true,
+		RunE:         redeploySubnet,
+		Args:         cobra.ExactArgs(1),
+	}
+	cmd.Flags().BoolVarP(&redeployLocal, "local", "l", false, "redeploy to the local network")
+	cmd.Flags().StringVar(&userProvidedAvagoVersion, "avalanchego-version", "latest", "use this version of avalanchego (ex: v1.17.12)")
+	return cmd
+}
+
+func redeploySubnet(cmd *cobra.Command, args []string) error {
+	if !redeployLocal {
+		return errors.New("only local redeploys are supported, please use --local")
+	}
+	subnetName := args[0]
+	if _, err := RemoveLocalSubnet(subnetName); err != nil {
+		return err
+	}
+	ux.Logger.PrintToUser("Redeploying %s", subnetName)
+	return CallDeploy(cmd, subnetName, true, false, false, false, "", "", false, false, false)
+}
+
+// stopLocalNetwork stops the local network, if running, saving its state into the default
+// snapshot, and kills the gRPC server. Returns whether the network was running
+func stopLocalNetwork() (bool, error) {
+	isRunning, err := binutils.NewProcessChecker().IsServerProcessRunning(app)
+	if err != nil || !isRunning {
+		return false, err
+	}
+	cli, err := binutils.NewGRPCClient(binutils.WithAvoidRPCVersionCheck(true))
+	if err != nil {
+		return false, err
+	}
+	defer cli.Close()
+	ctx, cancel := utils.GetANRContext()
+	defer cancel()
+	wasRunning := true
+	if _, err := cli.RemoveSnapshot(ctx, constants.DefaultSnapshotName); err != nil {
+		switch {
+		case server.IsServerError(err, server.ErrNotBootstrapped):
+			wasRunning = false
+		case !server.IsServerError(err, local.ErrSnapshotNotFound):
+			return false, fmt.Errorf("failed stopping network with a snapshot: %w", err)
+		}
+	}
+	if wasRunning {
+		if _, err := cli.SaveSnapshot(ctx, constants.DefaultSnapshotName); err != nil {
+			return false, fmt.Errorf("failed stopping network with a snapshot: %w", err)
+		}
+	}
+	if err := binutils.KillgRPCServerProcess(app); err != nil {
+		app.Log.Warn("failed killing server process", zap.Error(err))
+	}
+	return wasRunning, nil
+}
+
+// RemoveLocalSubnet removes [subnetName] from the local network. The network is stopped into
+// the default snapshot, which is then edited so the nodes stop running the blockchain of
+// [subnetName]. Its VM plugin and local deploy info are removed, while the other subnets keep
+// their deploy info and state, and are restored when the network starts again. Returns
+// whether the network was running
+func RemoveLocalSubnet(subnetName string) (bool, error) {
+	network := app.GetLocalNetwork()
+	sc, err := app.LoadSidecar(subnetName)
+	if err != nil {
+		return false, err
+	}
+	deployInfo, ok := sc.Networks[network.Name()]
+	if !ok {
+		return false, fmt.Errorf("subnet %s is not deployed to the local network", subnetName)
+	}
+	deployedSubnets, err := subnet.GetLocallyDeployedSubnetsFromFile(app)
+	if err != nil {
+		return false, err
+	}
+	for _, deployedSubnet := range deployedSubnets {
+		if deployedSubnet == subnetName {
+			continue
+		}
+		deployedSc, err := app.LoadSidecar(deployedSubnet)
+		if err != nil {
+			return false, err
+		}
+		if deployedSc.Networks[network.Name()].SubnetID == deployInfo.SubnetID {
+			return false, fmt.Errorf("subnet %s shares its local subnet ID %s with %s, so it can't be removed alone. Use network clean instead", subnetName, deployInfo.SubnetID, deployedSubnet)
+		}
+	}
+
+	ux.Logger.PrintToUser("Stopping local network to remove %s...", subnetName)
+	wasRunning, err := stopLocalNetwork()
+	if err != nil {
+		return false, err
+	}
+	if err := subnet.RemoveSnapshotSubnet(app.GetSnapshotsDir(), constants.DefaultSnapshotName, deployInfo.SubnetID, deployInfo.BlockchainID); err != nil {
+		return wasRunning, fmt.Errorf("failed removing %s from the network snapshot: %w", subnetName, err)
+	}
+	vmID, err := anrutils.VMID(subnetName)
+	if err != nil {
+		return wasRunning, fmt.Errorf("failed to create VM ID from %s: %w", subnetName, err)
+	}
+	if err := os.Remove(filepath.Join(app.GetPluginsDir(), vmID.String())); err != nil && !os.IsNotExist(err) {
+		return wasRunning, fmt.Errorf("failed removing VM plugin of %s: %w", subnetName, err)
+	}
+	delete(sc.Networks, network.Name())
+	delete(sc.ElasticSubnet, network.Name())
+	if err := app.UpdateSidecar(&sc); err != nil {
+		return wasRunning, err
+	}
+	ux.Logger.PrintToUser("Subnet %s removed from the local network", subnetName)
+	return wasRunning, nil
+}
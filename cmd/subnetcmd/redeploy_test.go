@@ -0,0 +1,85 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package subnetcmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/application"
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/ux"
+	"github.com/ava-labs/avalanche-network-runner/network"
+	"github.com/ava-labs/avalanche-network-runner/network/node"
+	avagoconfig "github.com/ava-labs/avalanchego/config"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveLocalSubnet(t *testing.T) {
+	require := require.New(t)
+	previousApp := app
+	t.Cleanup(func() {
+		app = previousApp
+	})
+	app = application.New()
+	app.Setup(t.TempDir(), logging.NoLog{}, nil, prompts.NewPrompter(), nil)
+	ux.NewUserLog(logging.NoLog{}, io.Discard)
+
+	localNetworkName := app.GetLocalNetwork().Name()
+	deployInfos := map[string]models.NetworkData{
+		"first":  {SubnetID: ids.GenerateTestID(), BlockchainID: ids.GenerateTestID()},
+		"second": {SubnetID: ids.GenerateTestID(), BlockchainID: ids.GenerateTestID()},
+	}
+	for subnetName, deployInfo := range deployInfos {
+		require.NoError(app.CreateSidecar(&models.Sidecar{
+			Name:     subnetName,
+			VM:       models.SubnetEvm,
+			Networks: map[string]models.NetworkData{localNetworkName: deployInfo},
+		}))
+	}
+	trackSubnets := deployInfos["first"].SubnetID.String() + "," + deployInfos["second"].SubnetID.String()
+	networkConfig := network.Config{
+		NodeConfigs: []node.Config{
+			{Name: "node1", Flags: map[string]interface{}{avagoconfig.TrackSubnetsKey: trackSubnets}},
+		},
+	}
+	networkConfigBytes, err := json.Marshal(networkConfig)
+	require.NoError(err)
+	networkConfigPath := filepath.Join(app.GetSnapshotsDir(), "anr-snapshot-"+constants.DefaultSnapshotName, "network.json")
+	require.NoError(os.MkdirAll(filepath.Dir(networkConfigPath), constants.DefaultPerms755))
+	require.NoError(os.WriteFile(networkConfigPath, networkConfigBytes, constants.WriteReadReadPerms))
+
+	wasRunning, err := RemoveLocalSubnet("first")
+	require.NoError(err)
+	require.False(wasRunning)
+
+	sc, err := app.LoadSidecar("first")
+	require.NoError(err)
+	require.NotContains(sc.Networks, localNetworkName)
+	// the other subnet keeps its deploy info, and is still run by the nodes
+	sc, err = app.LoadSidecar("second")
+	require.NoError(err)
+	require.Equal(deployInfos["second"].BlockchainID, sc.Networks[localNetworkName].BlockchainID)
+	networkConfigBytes, err = os.ReadFile(networkConfigPath)
+	require.NoError(err)
+	require.NoError(json.Unmarshal(networkConfigBytes, &networkConfig))
+	require.Equal(deployInfos["second"].SubnetID.String(), networkConfig.NodeConfigs[0].Flags[avagoconfig.TrackSubnetsKey])
+
+	_, err = RemoveLocalSubnet("first")
+	require.ErrorContains(err, "not deployed")
+
+	// without a snapshot there is nothing to edit
+	require.NoError(os.RemoveAll(filepath.Dir(networkConfigPath)))
+	_, err = RemoveLocalSubnet("second")
+	require.NoError(err)
+	sc, err = app.LoadSidecar("second")
+	require.NoError(err)
+	require.NotContains(sc.Networks, localNetworkName)
+}
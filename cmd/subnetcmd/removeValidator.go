@@ -59,7 +59,7 @@ func removeValidator(_ *cobra.Command, args []string) error {
 	case deployMainnet:
 		network = models.MainnetNetwork
 	case deployLocal:
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	}
 
 	if network.Kind == models.Undefined {
@@ -70,7 +70,7 @@ func removeValidator(_ *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
-		network = models.NetworkFromString(networkStr)
+		network = app.GetNetworkFromString(networkStr)
 	}
 
 	if outputTxPath != "" {
@@ -114,7 +114,7 @@ func removeValidator(_ *cobra.Command, args []string) error {
 
 	// used in E2E to simulate public network execution paths on a local network
 	if os.Getenv(constants.SimulatePublicNetwork) != "" {
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	}
 
 	// get keychain accesor
@@ -211,13 +211,13 @@ func removeFromLocal(subnetName string) error {
 		return err
 	}
 
-	subnetID := sc.Networks[models.Local.String()].SubnetID
+	subnetID := sc.Networks[app.GetLocalNetwork().Name()].SubnetID
 	if subnetID == ids.Empty {
 		return errNoSubnetID
 	}
 
 	// Get NodeIDs of all validators on the subnet
-	validators, err := subnet.GetSubnetValidators(subnetID)
+	validators, err := subnet.GetSubnetValidators(app.GetLocalNetwork(), subnetID)
 	if err != nil {
 		return err
 	}
@@ -243,7 +243,7 @@ func removeFromLocal(subnetName string) error {
 
 	testKey := genesis.EWOQKey
 	keyChain := secp256k1fx.NewKeychain(testKey)
-	_, err = subnet.IssueRemoveSubnetValidatorTx(keyChain, subnetID, nodeID)
+	_, err = subnet.IssueRemoveSubnetValidatorTx(app.GetLocalNetwork(), keyChain, subnetID, nodeID)
 	if err != nil {
 		return err
 	}
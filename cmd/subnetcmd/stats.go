@@ -61,7 +61,7 @@ func stats(_ *cobra.Command, args []string) error {
 		if !(networkStr == models.Fuji.String() || networkStr == models.Mainnet.String()) {
 			return errors.New("unsupported network")
 		}
-		network = models.NetworkFromString(networkStr)
+		network = app.GetNetworkFromString(networkStr)
 	}
 
 	chains, err := ValidateSubnetNameAndGetChains(args)
@@ -279,10 +279,10 @@ func findAPIEndpoint(network models.Network) (platformvm.Client, info.Client) {
 
 	// first try local node
 	ctx := context.Background()
-	c := platformvm.NewClient(constants.LocalAPIEndpoint)
+	c := platformvm.NewClient(app.GetLocalNetwork().Endpoint)
 	_, err := c.GetHeight(ctx)
 	if err == nil {
-		i = info.NewClient(constants.LocalAPIEndpoint)
+		i = info.NewClient(app.GetLocalNetwork().Endpoint)
 		// try calling it to make sure it actually worked
 		_, _, err := i.GetNodeID(ctx)
 		if err == nil {
@@ -296,7 +296,7 @@ func findAPIEndpoint(network models.Network) (platformvm.Client, info.Client) {
 	_, err = c.GetHeight(ctx)
 	if err == nil {
 		// also try to get a local client
-		i = info.NewClient(constants.LocalAPIEndpoint)
+		i = info.NewClient(app.GetLocalNetwork().Endpoint)
 	}
 	return c, i
 }
@@ -38,6 +38,8 @@ manage your Subnet configurations and live deployments.`,
 	cmd.AddCommand(newDeleteCmd())
 	// subnet deploy
 	cmd.AddCommand(newDeployCmd())
+	// subnet redeploy
+	cmd.AddCommand(newRedeployCmd())
 	// subnet describe
 	cmd.AddCommand(newDescribeCmd())
 	// subnet list
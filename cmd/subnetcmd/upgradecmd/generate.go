@@ -365,7 +365,7 @@ func getCClient(apiEndpoint string, blockchainID string) (ethclient.Client, erro
 }
 
 func ensureAdminsHaveBalanceLocalNetwork(admins []common.Address, blockchainID string) error {
-	cClient, err := getCClient(constants.LocalAPIEndpoint, blockchainID)
+	cClient, err := getCClient(app.GetLocalNetwork().Endpoint, blockchainID)
 	if err != nil {
 		return err
 	}
@@ -402,7 +402,7 @@ func ensureAdminsHaveBalance(admins []common.Address, subnetName string) error {
 	switch sc.VM {
 	case models.SubnetEvm:
 		// Currently only checking if admins have balance for subnets deployed in Local Network
-		if networkData, ok := sc.Networks["Local Network"]; ok {
+		if networkData, ok := sc.Networks[app.GetLocalNetwork().Name()]; ok {
 			blockchainID := networkData.BlockchainID.String()
 			err = ensureAdminsHaveBalanceLocalNetwork(admins, blockchainID)
 			if err != nil {
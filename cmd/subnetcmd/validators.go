@@ -50,7 +50,7 @@ func printValidators(_ *cobra.Command, args []string) error {
 	network := models.UndefinedNetwork
 	switch {
 	case validatorsLocal:
-		network = models.LocalNetwork
+		network = app.GetLocalNetwork()
 	case validatorsTestnet:
 		network = models.FujiNetwork
 	case validatorsMainnet:
@@ -66,7 +66,7 @@ func printValidators(_ *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
-		network = models.NetworkFromString(networkStr)
+		network = app.GetNetworkFromString(networkStr)
 	}
 
 	// get the subnetID
@@ -90,7 +90,7 @@ func printValidators(_ *cobra.Command, args []string) error {
 }
 
 func printLocalValidators(subnetID ids.ID) error {
-	validators, err := subnet.GetSubnetValidators(subnetID)
+	validators, err := subnet.GetSubnetValidators(app.GetLocalNetwork(), subnetID)
 	if err != nil {
 		return err
 	}
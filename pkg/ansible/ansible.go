@@ -58,6 +58,26 @@ func CreateAnsibleHostInventory(inventoryDirPath, certFilePath, cloudService str
 	return nil
 }
 
+// AddHostsToAnsibleInventory appends [hosts] to the inventory file, keeping their own
+// ssh user and private key, as used for servers not created by the CLI
+func AddHostsToAnsibleInventory(inventoryDirPath string, hosts []models.Host) error {
+	if err := os.MkdirAll(inventoryDirPath, os.ModePerm); err != nil {
+		return err
+	}
+	inventoryHostsFilePath := filepath.Join(inventoryDirPath, constants.AnsibleHostInventoryFileName)
+	inventoryFile, err := os.OpenFile(inventoryHostsFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, constants.WriteReadReadPerms)
+	if err != nil {
+		return err
+	}
+	defer inventoryFile.Close()
+	for _, host := range hosts {
+		if _, err = inventoryFile.WriteString(host.GetAnsibleInventoryRecord() + "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func Setup(ansibleDir string) error {
 	err := WriteCfgFile(ansibleDir)
 	if err != nil {
@@ -222,6 +242,21 @@ func RunAnsiblePlaybookExportSubnet(ansibleDir, inventoryPath, subnetPath, ansib
 	return cmdErr
 }
 
+// getNetworkFlag returns the avalanche CLI flag that selects [network]
+func getNetworkFlag(network models.Network) string {
+	switch network.Kind {
+	case models.Local:
+		return "--local"
+	case models.Devnet:
+		return "--devnet"
+	case models.Fuji:
+		return "--fuji"
+	case models.Mainnet:
+		return "--mainnet"
+	}
+	return ""
+}
+
 // RunAnsiblePlaybookTrackSubnet runs avalanche subnet join <subnetName> in cloud server
 // targets a specific host ansibleHostID in ansible inventory file
 func RunAnsiblePlaybookTrackSubnet(
@@ -232,18 +267,7 @@ func RunAnsiblePlaybookTrackSubnet(
 	inventoryPath string,
 	ansibleHostID string,
 ) error {
-	networkFlag := ""
-	switch network.Kind {
-	case models.Local:
-		networkFlag = "--local"
-	case models.Devnet:
-		networkFlag = "--devnet"
-	case models.Fuji:
-		networkFlag = "--fuji"
-	case models.Mainnet:
-		networkFlag = "--mainnet"
-	}
-	playbookInputs := "target=" + ansibleHostID + " subnetExportFileName=" + importPath + " subnetName=" + subnetName + " networkFlag=" + networkFlag
+	playbookInputs := "target=" + ansibleHostID + " subnetExportFileName=" + importPath + " subnetName=" + subnetName + " networkFlag=" + getNetworkFlag(network)
 	cmd := exec.Command(constants.AnsiblePlaybook, constants.TrackSubnetPlaybook, constants.AnsibleInventoryFlag, inventoryPath, constants.AnsibleExtraVarsFlag, playbookInputs, constants.AnsibleExtraArgsIdentitiesOnlyFlag) //nolint:gosec
 	cmd.Dir = ansibleDir
 	stdoutBuffer, stderrBuffer := utils.SetupRealtimeCLIOutput(cmd, true, true)
@@ -257,9 +281,9 @@ func RunAnsiblePlaybookTrackSubnet(
 	return cmdErr
 }
 
-// RunAnsiblePlaybookUpdateSubnet runs avalanche subnet join <subnetName> in cloud server using update subnet info
-func RunAnsiblePlaybookUpdateSubnet(ansibleDir, subnetName, importPath, inventoryPath, ansibleHostID string) error {
-	playbookInputs := "target=" + ansibleHostID + " subnetExportFileName=" + importPath + " subnetName=" + subnetName
+// RunAnsiblePlaybookUpdateSubnet runs avalanche subnet join <subnetName> on [network] in cloud server using update subnet info
+func RunAnsiblePlaybookUpdateSubnet(ansibleDir string, network models.Network, subnetName, importPath, inventoryPath, ansibleHostID string) error {
+	playbookInputs := "target=" + ansibleHostID + " subnetExportFileName=" + importPath + " subnetName=" + subnetName + " networkFlag=" + getNetworkFlag(network)
 	cmd := exec.Command(constants.AnsiblePlaybook, constants.UpdateSubnetPlaybook, constants.AnsibleInventoryFlag, inventoryPath, constants.AnsibleExtraVarsFlag, playbookInputs, constants.AnsibleExtraArgsIdentitiesOnlyFlag) //nolint:gosec
 	cmd.Dir = ansibleDir
 	stdoutBuffer, stderrBuffer := utils.SetupRealtimeCLIOutput(cmd, true, true)
@@ -345,6 +369,23 @@ func RunAnsiblePlaybookCheckAvalancheGoVersion(ansibleDir, avalancheGoPath, inve
 	return cmdErr
 }
 
+// RunAnsiblePlaybookGetNodeID gets the NodeID the node is running with
+// targets a specific host ansibleHostID in ansible inventory file
+func RunAnsiblePlaybookGetNodeID(ansibleDir, nodeIDPath, inventoryPath, ansibleHostID string) error {
+	playbookInputs := "target=" + ansibleHostID + " nodeIDJsonPath=" + nodeIDPath
+	cmd := exec.Command(constants.AnsiblePlaybook, constants.GetNodeIDPlaybook, constants.AnsibleInventoryFlag, inventoryPath, constants.AnsibleExtraVarsFlag, playbookInputs, constants.AnsibleExtraArgsIdentitiesOnlyFlag) //nolint:gosec
+	cmd.Dir = ansibleDir
+	stdoutBuffer, stderrBuffer := utils.SetupRealtimeCLIOutput(cmd, false, false)
+	cmdErr := cmd.Run()
+	if err := displayErrMsg(stdoutBuffer); err != nil {
+		return err
+	}
+	if err := displayErrMsg(stderrBuffer); err != nil {
+		return err
+	}
+	return cmdErr
+}
+
 // RunAnsiblePlaybookCheckBootstrapped checks if node is bootstrapped to primary network
 // targets a specific host ansibleHostID in ansible inventory file
 func RunAnsiblePlaybookCheckBootstrapped(ansibleDir, isBootstrappedPath, inventoryPath, ansibleHostID string) error {
@@ -437,6 +478,30 @@ func CheckIsInstalled() error {
 	return nil
 }
 
+// RemoveHostFromAnsibleInventory removes [ansibleHostID] from the inventory file, keeping
+// the records of all other hosts
+func RemoveHostFromAnsibleInventory(inventoryDirPath string, ansibleHostID string) error {
+	inventory, err := GetInventoryFromAnsibleInventoryFile(inventoryDirPath)
+	if err != nil {
+		return err
+	}
+	inventoryHostsFilePath := filepath.Join(inventoryDirPath, constants.AnsibleHostInventoryFileName)
+	inventoryFile, err := os.Create(inventoryHostsFilePath)
+	if err != nil {
+		return err
+	}
+	defer inventoryFile.Close()
+	for _, host := range inventory {
+		if host.NodeID == ansibleHostID {
+			continue
+		}
+		if _, err = inventoryFile.WriteString(host.GetAnsibleInventoryRecord() + "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // UpdateInventoryHostPublicIP first maps existing ansible inventory host file content
 // then it deletes the inventory file and regenerates a new ansible inventory file where it will fetch public IP
 // of nodes without elastic IP and update its value in the new ansible inventory file
@@ -555,3 +620,38 @@ func RunAnsiblePlaybookGetNewSubnetEVM(ansibleDir, subnetEVMReleaseURL, subnetEV
 	}
 	return cmdErr
 }
+
+// RunAnsiblePlaybookGetNodeConfig fetches the avalanchego config file of the nodes into nodeConfigJSONPath
+// suffixed by the host ID, skipping nodes without it
+// targets specific hosts ansibleHostIDs in ansible inventory file
+func RunAnsiblePlaybookGetNodeConfig(ansibleDir, nodeConfigJSONPath, inventoryPath, ansibleHostIDs string) error {
+	playbookInputs := "target=" + ansibleHostIDs + " nodeConfigJsonPath=" + nodeConfigJSONPath
+	cmd := exec.Command(constants.AnsiblePlaybook, constants.GetNodeConfigPlaybook, constants.AnsibleInventoryFlag, inventoryPath, constants.AnsibleExtraVarsFlag, playbookInputs, constants.AnsibleExtraArgsIdentitiesOnlyFlag) //nolint:gosec
+	cmd.Dir = ansibleDir
+	stdoutBuffer, stderrBuffer := utils.SetupRealtimeCLIOutput(cmd, false, false)
+	cmdErr := cmd.Run()
+	if err := displayErrMsg(stdoutBuffer); err != nil {
+		return err
+	}
+	if err := displayErrMsg(stderrBuffer); err != nil {
+		return err
+	}
+	return cmdErr
+}
+
+// RunAnsiblePlaybookSetNodeConfig copies nodeConfigPath as the avalanchego config file of the node
+// targets a specific host ansibleHostID in ansible inventory file
+func RunAnsiblePlaybookSetNodeConfig(ansibleDir, nodeConfigPath, inventoryPath, ansibleHostID string) error {
+	playbookInputs := "target=" + ansibleHostID + " nodeConfigPath=" + nodeConfigPath
+	cmd := exec.Command(constants.AnsiblePlaybook, constants.SetNodeConfigPlaybook, constants.AnsibleInventoryFlag, inventoryPath, constants.AnsibleExtraVarsFlag, playbookInputs, constants.AnsibleExtraArgsIdentitiesOnlyFlag) //nolint:gosec
+	cmd.Dir = ansibleDir
+	stdoutBuffer, stderrBuffer := utils.SetupRealtimeCLIOutput(cmd, true, true)
+	cmdErr := cmd.Run()
+	if err := displayErrMsg(stdoutBuffer); err != nil {
+		return err
+	}
+	if err := displayErrMsg(stderrBuffer); err != nil {
+		return err
+	}
+	return cmdErr
+}
@@ -4,6 +4,7 @@ package application
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -26,6 +27,9 @@ type Avalanche struct {
 	Apm        *apm.APM
 	ApmDir     string
 	Downloader Downloader
+
+	localNetworkName  string
+	localNetworkIndex int
 }
 
 func New() *Avalanche {
@@ -45,7 +49,7 @@ func (app *Avalanche) GetRunFile() string {
 }
 
 func (app *Avalanche) GetSnapshotsDir() string {
-	return filepath.Join(app.baseDir, constants.SnapshotsDirName)
+	return filepath.Join(app.GetLocalNetworkDir(), constants.SnapshotsDirName)
 }
 
 func (app *Avalanche) GetBaseDir() string {
@@ -65,7 +69,7 @@ func (app *Avalanche) GetReposDir() string {
 }
 
 func (app *Avalanche) GetRunDir() string {
-	return filepath.Join(app.baseDir, constants.RunDir)
+	return filepath.Join(app.GetLocalNetworkDir(), constants.RunDir)
 }
 
 func (app *Avalanche) GetCustomVMDir() string {
@@ -143,22 +147,10 @@ func (app *Avalanche) CreateAnsibleDir() error {
 	return nil
 }
 
-func (app *Avalanche) CreateTerraformDir() error {
-	nodesDir := app.GetNodesDir()
-	if _, err := os.Stat(nodesDir); os.IsNotExist(err) {
-		err = os.Mkdir(nodesDir, constants.DefaultPerms755)
-		if err != nil {
-			return err
-		}
-	}
-	nodeTerraformDir := app.GetTerraformDir()
-	if _, err := os.Stat(nodeTerraformDir); os.IsNotExist(err) {
-		err = os.Mkdir(nodeTerraformDir, constants.DefaultPerms755)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+// CreateTerraformDir creates the terraform directory [terraformDir] of a cluster, where the
+// terraform configuration and state of the cluster cloud resources are kept
+func (app *Avalanche) CreateTerraformDir(terraformDir string) error {
+	return os.MkdirAll(terraformDir, constants.DefaultPerms755)
 }
 
 func (app *Avalanche) CreateAnsibleInventoryDir() error {
@@ -187,8 +179,15 @@ func (app *Avalanche) GetTerraformDir() string {
 	return filepath.Join(app.GetNodesDir(), constants.TerraformDir)
 }
 
-func (app *Avalanche) GetTempCertPath(certName string) string {
-	return filepath.Join(app.GetTerraformDir(), certName)
+// GetClusterTerraformDir returns the directory where the terraform state of the cloud resources
+// of [clusterName] is kept, one subdirectory for each set of nodes created for the cluster. It is
+// not under the cluster dir, which node stop removes, so stopped clusters can still be destroyed
+func (app *Avalanche) GetClusterTerraformDir(clusterName string) string {
+	return filepath.Join(app.GetTerraformDir(), clusterName)
+}
+
+func (app *Avalanche) GetTempCertPath(terraformDir, certName string) string {
+	return filepath.Join(terraformDir, certName)
 }
 
 func (app *Avalanche) GetClustersConfigPath() string {
@@ -731,10 +730,93 @@ func (app *Avalanche) GetAnsibleInventoryDirPath(clusterName string) string {
 	return filepath.Join(app.GetNodesDir(), constants.AnsibleInventoryDir, clusterName)
 }
 
+// GetMonitoringInventoryDirPath returns the inventory dir of the server running Prometheus and
+// Grafana for [clusterName], kept apart from the cluster inventory as it is not a node
+func (app *Avalanche) GetMonitoringInventoryDirPath(clusterName string) string {
+	return filepath.Join(app.GetNodesDir(), constants.MonitoringInventoryDir, clusterName)
+}
+
+// GetClusterDirPath returns the dir where the local settings of [clusterName] are kept
+func (app *Avalanche) GetClusterDirPath(clusterName string) string {
+	return filepath.Join(app.GetNodesDir(), constants.ClustersDir, clusterName)
+}
+
+// GetClusterGenesisSpecPath returns the path of the genesis spec the Devnet of [clusterName]
+// was created with
+func (app *Avalanche) GetClusterGenesisSpecPath(clusterName string) string {
+	return filepath.Join(app.GetClusterDirPath(clusterName), constants.ClusterGenesisSpecFileName)
+}
+
+// GetClusterSSHKnownHostsPath returns the path of the ssh known hosts file used for the nodes
+// of [clusterName], kept apart from the user one so host keys of reused IPs don't clash
+func (app *Avalanche) GetClusterSSHKnownHostsPath(clusterName string) string {
+	return filepath.Join(app.GetClusterDirPath(clusterName), constants.ClusterSSHKnownHostsFileName)
+}
+
+// LoadClusterHealthReport loads the last report of node health --check for [clusterName],
+// which is empty if the checks were never run
+func (app *Avalanche) LoadClusterHealthReport(clusterName string) (models.ClusterHealthReport, error) {
+	reportPath := filepath.Join(app.GetClusterDirPath(clusterName), constants.ClusterHealthReportFileName)
+	report := models.ClusterHealthReport{}
+	jsonBytes, err := os.ReadFile(reportPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return report, nil
+		}
+		return report, err
+	}
+	err = json.Unmarshal(jsonBytes, &report)
+	return report, err
+}
+
+func (app *Avalanche) WriteClusterHealthReport(clusterName string, report models.ClusterHealthReport) error {
+	reportPath := filepath.Join(app.GetClusterDirPath(clusterName), constants.ClusterHealthReportFileName)
+	if err := os.MkdirAll(filepath.Dir(reportPath), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	reportBytes, err := json.MarshalIndent(report, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(reportPath, reportBytes, constants.WriteReadReadPerms)
+}
+
+// LoadClusterAvalancheGoConfig loads the avalanchego config desired for the nodes of [clusterName],
+// which is empty if none was set
+func (app *Avalanche) LoadClusterAvalancheGoConfig(clusterName string) (models.ClusterAvalancheGoConfig, error) {
+	configPath := filepath.Join(app.GetClusterDirPath(clusterName), constants.ClusterAvalancheGoConfigFileName)
+	config := models.ClusterAvalancheGoConfig{}
+	jsonBytes, err := os.ReadFile(configPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return config, nil
+		}
+		return config, err
+	}
+	err = json.Unmarshal(jsonBytes, &config)
+	return config, err
+}
+
+func (app *Avalanche) WriteClusterAvalancheGoConfig(clusterName string, config models.ClusterAvalancheGoConfig) error {
+	configPath := filepath.Join(app.GetClusterDirPath(clusterName), constants.ClusterAvalancheGoConfigFileName)
+	if err := os.MkdirAll(filepath.Dir(configPath), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	configBytes, err := json.MarshalIndent(config, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, configBytes, constants.WriteReadReadPerms)
+}
+
 func (app *Avalanche) GetAnsibleStatusDir() string {
 	return filepath.Join(app.GetAnsibleDir(), constants.AnsibleStatusDir)
 }
 
+func (app *Avalanche) GetNodeIDJSONFile() string {
+	return filepath.Join(app.GetAnsibleStatusDir(), constants.NodeIDJSONFile)
+}
+
 func (app *Avalanche) GetBootstrappedJSONFile() string {
 	return filepath.Join(app.GetAnsibleStatusDir(), constants.IsBootstrappedJSONFile)
 }
@@ -747,6 +829,10 @@ func (app *Avalanche) GetAvalancheGoJSONFile() string {
 	return filepath.Join(app.GetAnsibleStatusDir(), constants.AvalancheGoVersionJSONFile)
 }
 
+func (app *Avalanche) GetNodeConfigJSONFile() string {
+	return filepath.Join(app.GetAnsibleStatusDir(), constants.NodeConfigJSONFile)
+}
+
 func (app *Avalanche) GetSubnetSyncJSONFile() string {
 	return filepath.Join(app.GetAnsibleStatusDir(), constants.SubnetSyncJSONFile)
 }
@@ -275,6 +275,38 @@ func Test_genesisExists(t *testing.T) {
 	require.NoError(err)
 }
 
+func TestSetLocalNetwork(t *testing.T) {
+	require := require.New(t)
+	ap := newTestApp(t)
+	require.NoError(ap.SetLocalNetwork(""))
+	require.Equal(models.LocalNetwork, ap.GetLocalNetwork())
+	require.Equal(0, ap.GetLocalNetworkPortsOffset())
+	require.Equal(filepath.Join(ap.baseDir, constants.RunDir), ap.GetRunDir())
+
+	require.ErrorIs(ap.SetLocalNetwork("bad name"), ErrInvalidLocalNetworkName)
+
+	require.NoError(ap.SetLocalNetwork("first"))
+	require.Equal(constants.LocalNetworkPortsOffset, ap.GetLocalNetworkPortsOffset())
+	require.Equal(filepath.Join(ap.baseDir, constants.LocalNetworksDir, "first", constants.RunDir), ap.GetRunDir())
+	require.DirExists(ap.GetSnapshotsDir())
+	require.Equal("http://127.0.0.1:9750", ap.GetLocalNetwork().Endpoint)
+	require.Equal("Local Network first", ap.GetLocalNetwork().Name())
+	require.Equal(ap.GetLocalNetwork(), ap.GetNetworkFromString(models.Local.String()))
+	require.Equal(models.FujiNetwork, ap.GetNetworkFromString(models.Fuji.String()))
+	require.Equal(constants.LocalAPIEndpoint, models.LocalNetwork.Endpoint)
+
+	require.NoError(ap.SetLocalNetwork("second"))
+	require.Equal(2*constants.LocalNetworkPortsOffset, ap.GetLocalNetworkPortsOffset())
+
+	// indices are kept across executions
+	require.NoError(ap.SetLocalNetwork("first"))
+	require.Equal(constants.LocalNetworkPortsOffset, ap.GetLocalNetworkPortsOffset())
+
+	names, err := ap.GetLocalNetworkNames()
+	require.NoError(err)
+	require.Equal([]string{"first", "second"}, names)
+}
+
 func newTestApp(t *testing.T) *Avalanche {
 	tempDir := t.TempDir()
 	return &Avalanche{
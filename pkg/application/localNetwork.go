@@ -0,0 +1,190 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+)
+
+var (
+	localNetworkNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+	ErrInvalidLocalNetworkName = errors.New("local network name must only contain letters, digits, '-' and '_'")
+)
+
+// localNetworkInstance is persisted for each named local network, so that it
+// keeps using the same ports across executions
+type localNetworkInstance struct {
+	Index int `json:"index"`
+}
+
+// SetLocalNetwork selects the named local network instance targeted by all local
+// operations. Each instance has its own run dir, snapshots dir, backend ports and
+// node ports. An empty name selects the default instance.
+func (app *Avalanche) SetLocalNetwork(name string) error {
+	if name == "" {
+		return nil
+	}
+	if !localNetworkNameRegexp.MatchString(name) {
+		return ErrInvalidLocalNetworkName
+	}
+	index, err := app.loadOrAllocateLocalNetworkIndex(name)
+	if err != nil {
+		return err
+	}
+	app.localNetworkName = name
+	app.localNetworkIndex = index
+	if err := os.MkdirAll(app.GetRunDir(), os.ModePerm); err != nil {
+		return err
+	}
+	return os.MkdirAll(app.GetSnapshotsDir(), os.ModePerm)
+}
+
+// GetLocalNetwork returns the selected local network instance, whose endpoint uses the
+// ports of the instance
+func (app *Avalanche) GetLocalNetwork() models.Network {
+	if app.localNetworkName == "" {
+		return models.LocalNetwork
+	}
+	endpoint := fmt.Sprintf("http://127.0.0.1:%d", constants.AvalanchegoAPIPort+app.GetLocalNetworkPortsOffset())
+	return models.NewLocalNetwork(app.localNetworkName, endpoint)
+}
+
+// GetNetworkFromString returns the network named [s], as offered in network prompts. The
+// Local one is the selected local network instance
+func (app *Avalanche) GetNetworkFromString(s string) models.Network {
+	network := models.NetworkFromString(s)
+	if network.Kind == models.Local {
+		return app.GetLocalNetwork()
+	}
+	return network
+}
+
+// GetLocalNetworkName returns the name of the selected local network instance,
+// or an empty string for the default one
+func (app *Avalanche) GetLocalNetworkName() string {
+	return app.localNetworkName
+}
+
+// GetLocalNetworkPortsOffset returns the offset to apply to the default node and
+// backend ports for the selected local network instance
+func (app *Avalanche) GetLocalNetworkPortsOffset() int {
+	return app.localNetworkIndex * constants.LocalNetworkPortsOffset
+}
+
+// GetLocalNetworkDir returns the dir holding the runs and snapshots of the selected
+// local network instance
+func (app *Avalanche) GetLocalNetworkDir() string {
+	if app.localNetworkName == "" {
+		return app.baseDir
+	}
+	return filepath.Join(app.baseDir, constants.LocalNetworksDir, app.localNetworkName)
+}
+
+// GetLocalNetworkNames returns the names of all named local network instances
+func (app *Avalanche) GetLocalNetworkNames() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(app.baseDir, constants.LocalNetworksDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// SetDeterministicLocalNetwork records whether the selected local network has been
+// started in deterministic mode, so that later deploys keep it reproducible
+func (app *Avalanche) SetDeterministicLocalNetwork(deterministic bool) error {
+	markerPath := filepath.Join(app.GetRunDir(), constants.DeterministicNetworkFile)
+	if !deterministic {
+		if err := os.Remove(markerPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(app.GetRunDir(), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(markerPath, nil, constants.WriteReadReadPerms)
+}
+
+// IsDeterministicLocalNetwork returns true if the selected local network has been
+// started in deterministic mode
+func (app *Avalanche) IsDeterministicLocalNetwork() bool {
+	_, err := os.Stat(filepath.Join(app.GetRunDir(), constants.DeterministicNetworkFile))
+	return err == nil
+}
+
+func (app *Avalanche) loadLocalNetworkInstance(name string) (*localNetworkInstance, error) {
+	instancePath := filepath.Join(app.baseDir, constants.LocalNetworksDir, name, constants.LocalNetworkInstanceFile)
+	instanceBytes, err := os.ReadFile(instancePath)
+	if err != nil {
+		return nil, err
+	}
+	instance := localNetworkInstance{}
+	if err := json.Unmarshal(instanceBytes, &instance); err != nil {
+		return nil, fmt.Errorf("failed to parse local network instance file %s: %w", instancePath, err)
+	}
+	return &instance, nil
+}
+
+// loadOrAllocateLocalNetworkIndex returns the index of the named local network,
+// allocating the lowest index not used by other instances if it is new. Index 0
+// is reserved for the default instance
+func (app *Avalanche) loadOrAllocateLocalNetworkIndex(name string) (int, error) {
+	instance, err := app.loadLocalNetworkInstance(name)
+	if err == nil {
+		return instance.Index, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+	names, err := app.GetLocalNetworkNames()
+	if err != nil {
+		return 0, err
+	}
+	usedIndices := map[int]struct{}{}
+	for _, otherName := range names {
+		otherInstance, err := app.loadLocalNetworkInstance(otherName)
+		if err != nil {
+			continue
+		}
+		usedIndices[otherInstance.Index] = struct{}{}
+	}
+	index := 1
+	for {
+		if _, ok := usedIndices[index]; !ok {
+			break
+		}
+		index++
+	}
+	instanceDir := filepath.Join(app.baseDir, constants.LocalNetworksDir, name)
+	if err := os.MkdirAll(instanceDir, os.ModePerm); err != nil {
+		return 0, err
+	}
+	instanceBytes, err := json.MarshalIndent(localNetworkInstance{Index: index}, "", "    ")
+	if err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(filepath.Join(instanceDir, constants.LocalNetworkInstanceFile), instanceBytes, constants.WriteReadReadPerms); err != nil {
+		return 0, err
+	}
+	return index, nil
+}
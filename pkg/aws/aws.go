@@ -42,8 +42,9 @@ func CheckKeyPairExists(ec2Svc *ec2.EC2, kpName string) (bool, error) {
 	return true, nil
 }
 
-func GetUbuntuAMIID(ec2Svc *ec2.EC2) (string, error) {
-	descriptionFilterValue := "Canonical, Ubuntu, 20.04 LTS, amd64*"
+// GetUbuntuAMIID returns the latest Ubuntu 20.04 image for the cpu architecture [arch] (amd64 / arm64)
+func GetUbuntuAMIID(ec2Svc *ec2.EC2, arch string) (string, error) {
+	descriptionFilterValue := fmt.Sprintf("Canonical, Ubuntu, 20.04 LTS, %s*", arch)
 	imageInput := &ec2.DescribeImagesInput{
 		Filters: []*ec2.Filter{
 			{Name: aws.String("root-device-type"), Values: []*string{aws.String("ebs")}},
@@ -173,24 +174,44 @@ func StopInstance(ec2Svc *ec2.EC2, instanceID, publicIP string, releasePublicIP
 		return err
 	}
 	if releasePublicIP {
-		describeAddressInput := &ec2.DescribeAddressesInput{
-			Filters: []*ec2.Filter{
-				{Name: aws.String("public-ip"), Values: []*string{aws.String(publicIP)}},
-			},
-		}
-		addressOutput, err := ec2Svc.DescribeAddresses(describeAddressInput)
-		if err != nil {
-			return err
-		}
-		if len(addressOutput.Addresses) == 0 {
-			return ErrNoAddressFound
-		}
-		releaseAddressInput := &ec2.ReleaseAddressInput{
-			AllocationId: aws.String(*addressOutput.Addresses[0].AllocationId),
-		}
-		if _, err = ec2Svc.ReleaseAddress(releaseAddressInput); err != nil {
-			return err
-		}
+		return releaseElasticIP(ec2Svc, publicIP)
 	}
 	return nil
 }
+
+func releaseElasticIP(ec2Svc *ec2.EC2, publicIP string) error {
+	describeAddressInput := &ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("public-ip"), Values: []*string{aws.String(publicIP)}},
+		},
+	}
+	addressOutput, err := ec2Svc.DescribeAddresses(describeAddressInput)
+	if err != nil {
+		return err
+	}
+	if len(addressOutput.Addresses) == 0 {
+		return ErrNoAddressFound
+	}
+	releaseAddressInput := &ec2.ReleaseAddressInput{
+		AllocationId: aws.String(*addressOutput.Addresses[0].AllocationId),
+	}
+	_, err = ec2Svc.ReleaseAddress(releaseAddressInput)
+	return err
+}
+
+// TerminateAWSNode terminates the instance of [nodeConfig], and releases its elastic IP
+// once the instance is gone
+func TerminateAWSNode(ec2Svc *ec2.EC2, nodeConfig models.NodeConfig, clusterName string) error {
+	ux.Logger.PrintToUser(fmt.Sprintf("Terminating node instance %s in cluster %s...", nodeConfig.NodeID, clusterName))
+	instanceIDs := []*string{aws.String(nodeConfig.NodeID)}
+	if _, err := ec2Svc.TerminateInstances(&ec2.TerminateInstancesInput{InstanceIds: instanceIDs}); err != nil {
+		return err
+	}
+	if err := ec2Svc.WaitUntilInstanceTerminated(&ec2.DescribeInstancesInput{InstanceIds: instanceIDs}); err != nil {
+		return err
+	}
+	if nodeConfig.ElasticIP == "" {
+		return nil
+	}
+	return releaseElasticIP(ec2Svc, nodeConfig.ElasticIP)
+}
@@ -62,6 +62,24 @@ func WithAvoidRPCVersionCheck(avoidRPCVersionCheck bool) GRPCClientOpOption {
 	}
 }
 
+// gRPCPortsOffset is applied to the default backend ports, so that each local
+// network instance has its own backend
+var gRPCPortsOffset int
+
+// SetGRPCPortsOffset makes gRPC clients and servers use the backend ports of
+// the local network instance with the given ports offset
+func SetGRPCPortsOffset(offset int) {
+	gRPCPortsOffset = offset
+}
+
+func getGRPCServerEndpoint() string {
+	return fmt.Sprintf(":%d", gRPCServerPort+gRPCPortsOffset)
+}
+
+func getGRPCGatewayEndpoint() string {
+	return fmt.Sprintf(":%d", gRPCGatewayPort+gRPCPortsOffset)
+}
+
 // NewGRPCClient hides away the details (params) of creating a gRPC server connection
 func NewGRPCClient(opts ...GRPCClientOpOption) (client.Client, error) {
 	op := GRPCClientOp{}
@@ -79,7 +97,7 @@ func NewGRPCClient(opts ...GRPCClientOpOption) (client.Client, error) {
 		return nil, err
 	}
 	client, err := client.New(client.Config{
-		Endpoint:    gRPCServerEndpoint,
+		Endpoint:    getGRPCServerEndpoint(),
 		DialTimeout: gRPCDialTimeout,
 	}, log)
 	if errors.Is(err, context.DeadlineExceeded) {
@@ -116,8 +134,8 @@ func NewGRPCServer(snapshotsDir string) (server.Server, error) {
 		return nil, err
 	}
 	return server.New(server.Config{
-		Port:                gRPCServerEndpoint,
-		GwPort:              gRPCGatewayEndpoint,
+		Port:                getGRPCServerEndpoint(),
+		GwPort:              getGRPCGatewayEndpoint(),
 		DialTimeout:         gRPCDialTimeout,
 		SnapshotsDir:        snapshotsDir,
 		RedirectNodesOutput: false,
@@ -194,6 +212,9 @@ func StartServerProcess(app *application.Avalanche) error {
 	thisBin := reexec.Self()
 
 	args := []string{constants.BackendCmd}
+	if localNetworkName := app.GetLocalNetworkName(); localNetworkName != "" {
+		args = append(args, "--"+constants.LocalNetworkFlag, localNetworkName)
+	}
 	cmd := exec.Command(thisBin, args...)
 
 	outputDirPrefix := path.Join(app.GetRunDir(), "server")
@@ -21,14 +21,14 @@ func UpgradeVM(app *application.Avalanche, vmID string, vmBinPath string) error
 // update the RPC version of the VM in the sidecar file
 func UpdateLocalSidecarRPC(app *application.Avalanche, sc models.Sidecar, rpcVersion int) error {
 	// find local network deployment info in sidecar
-	networkData, ok := sc.Networks[models.Local.String()]
+	networkData, ok := sc.Networks[app.GetLocalNetwork().Name()]
 	if !ok {
 		return fmt.Errorf("failed to find local network in sidecar")
 	}
 
 	networkData.RPCVersion = rpcVersion
 
-	sc.Networks[models.Local.String()] = networkData
+	sc.Networks[app.GetLocalNetwork().Name()] = networkData
 
 	if err := app.UpdateSidecar(&sc); err != nil {
 		return fmt.Errorf("failed to update sidecar: %w", err)
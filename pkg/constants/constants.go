@@ -7,17 +7,28 @@ import (
 )
 
 const (
-	DefaultPerms755        = 0o755
-	WriteReadReadPerms     = 0o644
-	WriteReadUserOnlyPerms = 0o600
+	DefaultPerms755            = 0o755
+	WriteReadReadPerms         = 0o644
+	WriteReadUserOnlyPerms     = 0o600
+	UserOnlyWriteReadExecPerms = 0o700
 
 	BaseDirName = ".avalanche-cli"
 	LogDir      = "logs"
 
+	FaucetTransfersLogFile = "faucet-transfers.log"
+
 	ServerRunFile      = "gRPCserver.run"
 	AvalancheCliBinDir = "bin"
 	RunDir             = "runs"
 
+	LocalNetworksDir         = "local-networks"
+	LocalNetworkInstanceFile = "instance.json"
+	LocalNetworkFlag         = "local-network"
+	// port distance between the local network instances, for both node and backend ports
+	LocalNetworkPortsOffset = 100
+	// marks a local network started with network start --deterministic
+	DeterministicNetworkFile = "deterministic"
+
 	SuffixSeparator              = "_"
 	SidecarFileName              = "sidecar.json"
 	GenesisFileName              = "genesis.json"
@@ -101,10 +112,11 @@ const (
 	PrimaryNetworkValidatingStartLeadTime        = 1 * time.Minute
 	AWSCloudServerRunningState                   = "running"
 	TerraformNodeConfigFile                      = "node_config.tf"
+	TerraformDestroyPlanFile                     = "destroy.tfplan"
 	AvalancheCLISuffix                           = "-avalanche-cli"
 	AWSDefaultCredential                         = "default"
 	GCPDefaultImageProvider                      = "ubuntu-os-cloud"
-	GCPImageFilter                               = "family=ubuntu-2004* AND architecture=x86_64"
+	GCPImageFilter                               = "family=ubuntu-2004* AND architecture=%s"
 	GCPEnvVar                                    = "GOOGLE_APPLICATION_CREDENTIALS"
 	GCPDefaultAuthKeyPath                        = "~/.config/gcloud/application_default_credentials.json"
 	CertSuffix                                   = "-kp.pem"
@@ -113,7 +125,21 @@ const (
 	SSHTCPPort                                   = 22
 	AvalanchegoAPIPort                           = 9650
 	AvalanchegoP2PPort                           = 9651
+	NodeExporterPort                             = 9100
+	PrometheusPort                               = 9090
+	GrafanaPort                                  = 3000
+	AWSDefaultMonitoringInstanceType             = "t3.large"
+	GCPDefaultMonitoringInstanceType             = "e2-standard-2"
+	MonitoringServerStorageSize                  = 100
 	CloudServerStorageSize                       = 1000
+	ArchAMD64                                    = "amd64"
+	ArchARM64                                    = "arm64"
+	AWSDefaultInstanceType                       = "c5.2xlarge"
+	AWSDefaultARM64InstanceType                  = "c7g.2xlarge"
+	AWSDefaultDiskType                           = "gp3"
+	GCPDefaultInstanceType                       = "e2-standard-8"
+	GCPDefaultARM64InstanceType                  = "t2a-standard-8"
+	GCPDefaultDiskType                           = "pd-standard"
 	OutboundPort                                 = 0
 	Terraform                                    = "terraform"
 	AnsiblePlaybook                              = "ansible-playbook"
@@ -122,6 +148,7 @@ const (
 	SetupDevnetPlaybook                          = "playbook/setupDevnet.yml"
 	ExportSubnetPlaybook                         = "playbook/exportSubnet.yml"
 	IsBootstrappedPlaybook                       = "playbook/isBootstrapped.yml"
+	GetNodeIDPlaybook                            = "playbook/getNodeID.yml"
 	IsHealthyPlaybook                            = "playbook/isHealthy.yml"
 	IsSubnetSyncedPlaybook                       = "playbook/isSubnetSynced.yml"
 	TrackSubnetPlaybook                          = "playbook/trackSubnet.yml"
@@ -133,9 +160,19 @@ const (
 	BuildEnvGolangVersion                        = "1.21.1"
 	IsHealthyJSONFile                            = "isHealthy.json"
 	IsBootstrappedJSONFile                       = "isBootstrapped.json"
+	NodeIDJSONFile                               = "nodeID.json"
 	AvalancheGoVersionJSONFile                   = "avalancheGoVersion.json"
 	SubnetSyncJSONFile                           = "isSubnetSynced.json"
 	AnsibleInventoryDir                          = "inventories"
+	MonitoringInventoryDir                       = "monitoring_inventories"
+	MonitoringDir                                = "monitoring"
+	GrafanaAdminPasswordFileName                 = "grafana_admin_password"
+	ClustersDir                                  = "clusters"
+	ClusterAvalancheGoConfigFileName             = "avalanchego_config.json"
+	ClusterGenesisSpecFileName                   = "genesis_spec.json"
+	ClusterHealthReportFileName                  = "health_report.json"
+	ClusterSSHKnownHostsFileName                 = "known_hosts"
+	NodeConfigJSONFile                           = "nodeConfig.json"
 	AnsibleTempInventoryDir                      = "temp_inventories"
 	AnsiblePlaybookDir                           = "playbook"
 	AnsibleStatusDir                             = "status"
@@ -155,9 +192,13 @@ const (
 
 	AWSCloudService            = "Amazon Web Services"
 	GCPCloudService            = "Google Cloud Platform"
+	ExistingHostsService       = "Existing Servers"
+	LocalMachineService        = "Local Machine"
 	AnsibleSSHUser             = "ubuntu"
 	AWSNodeAnsiblePrefix       = "aws_node"
 	GCPNodeAnsiblePrefix       = "gcp_node"
+	ExistingNodeAnsiblePrefix  = "existing_node"
+	LocalNodeAnsiblePrefix     = "local_node"
 	CustomVMDir                = "vms"
 	GCPStaticIPPrefix          = "static-ip"
 	AvaLabsOrg                 = "ava-labs"
@@ -169,9 +210,16 @@ const (
 	StopNodePlaybook           = "playbook/stopNode.yml"
 	StartNodePlaybook          = "playbook/startNode.yml"
 	GetNewSubnetEVMPlaybook    = "playbook/getNewSubnetEVMRelease.yml"
+	GetNodeConfigPlaybook      = "playbook/getNodeConfig.yml"
+	SetNodeConfigPlaybook      = "playbook/setNodeConfig.yml"
 	SubnetEVMReleaseURL        = "https://github.com/ava-labs/subnet-evm/releases/download/%s/%s"
-	SubnetEVMArchive           = "subnet-evm_%s_linux_amd64.tar.gz"
-	SubnetEVMBinaryPath        = "/home/ubuntu/.avalanchego/plugins/%s"
+	SubnetEVMArchive           = "subnet-evm_%s_%s_%s.tar.gz"
+	// paths on cloud servers are relative to the ssh user home dir
+	SubnetEVMBinaryPath  = ".avalanchego/plugins/%s"
+	CloudNodeStakingPath = ".avalanchego/staking/"
+	CloudNodeConfigPath  = ".avalanchego/configs/"
+	CloudNodeLogsPath    = ".avalanchego/logs/"
+	CloudNodeCLIBinPath  = "bin/avalanche"
 
 	AvalancheGoInstallDir = "avalanchego"
 	SubnetEVMInstallDir   = "subnet-evm"
@@ -7,7 +7,6 @@ import (
 	"os"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
-	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 )
 
@@ -36,7 +35,7 @@ func GetLocalElasticSubnetsFromFile(app *application.Avalanche) ([]string, error
 
 		// check if sidecar contains local elastic subnets info in Elastic Subnets map
 		// if so, add to list of elastic subnets
-		if _, ok := sc.ElasticSubnet[models.Local.String()]; ok {
+		if _, ok := sc.ElasticSubnet[app.GetLocalNetwork().Name()]; ok {
 			elasticSubnets = append(elasticSubnets, sc.Name)
 		}
 	}
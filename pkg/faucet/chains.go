@@ -0,0 +1,210 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package faucet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/keychain"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ava-labs/avalanchego/wallet/subnet/primary"
+	walletcommon "github.com/ava-labs/avalanchego/wallet/subnet/primary/common"
+	"github.com/ava-labs/coreth/core/types"
+	"github.com/ava-labs/coreth/ethclient"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// EVMTokenDecimals is the number of decimals of the native token of EVM chains, unless
+	// their chain config sets another one
+	EVMTokenDecimals       = 18
+	evmTransferGas         = 21_000
+	receiptPollingInterval = 500 * time.Millisecond
+)
+
+var errTxFailed = errors.New("transaction failed")
+
+type evmChain struct {
+	name     string
+	symbol   string
+	decimals uint8
+	rpcURL   string
+	key      *ecdsa.PrivateKey
+}
+
+// NewEVMChain creates a faucet chain that sends the native token of the EVM
+// chain served at [rpcURL], which has [decimals] decimals, from [key]
+func NewEVMChain(name string, symbol string, decimals uint8, rpcURL string, key *ecdsa.PrivateKey) Chain {
+	return &evmChain{
+		name:     name,
+		symbol:   symbol,
+		decimals: decimals,
+		rpcURL:   rpcURL,
+		key:      key,
+	}
+}
+
+func (c *evmChain) Name() string {
+	return c.name
+}
+
+func (c *evmChain) Symbol() string {
+	return c.symbol
+}
+
+func (*evmChain) ValidateAddress(addr string) error {
+	if !common.IsHexAddress(addr) {
+		return errors.New("expected an hex address")
+	}
+	return nil
+}
+
+// Transfer issues a native token transfer and waits for it to be accepted. Once issued,
+// the tx hash is returned together with any error
+func (c *evmChain) Transfer(ctx context.Context, addr string, amount float64) (string, error) {
+	client, err := ethclient.DialContext(ctx, c.rpcURL)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return "", err
+	}
+	from := crypto.PubkeyToAddress(c.key.PublicKey)
+	nonce, err := client.NonceAt(ctx, from, nil)
+	if err != nil {
+		return "", err
+	}
+	gasTipCap, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return "", err
+	}
+	baseFee, err := client.EstimateBaseFee(ctx)
+	if err != nil {
+		return "", err
+	}
+	to := common.HexToAddress(addr)
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: gasTipCap,
+		GasFeeCap: new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), gasTipCap),
+		Gas:       evmTransferGas,
+		To:        &to,
+		Value:     toBaseUnits(amount, c.decimals),
+	})
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), c.key)
+	if err != nil {
+		return "", err
+	}
+	if err := client.SendTransaction(ctx, signedTx); err != nil {
+		return "", err
+	}
+	for {
+		receipt, err := client.TransactionReceipt(ctx, signedTx.Hash())
+		if err == nil {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return signedTx.Hash().String(), fmt.Errorf("%w: %s", errTxFailed, signedTx.Hash())
+			}
+			return signedTx.Hash().String(), nil
+		}
+		select {
+		case <-ctx.Done():
+			return signedTx.Hash().String(), fmt.Errorf("timeout waiting for tx %s: %w", signedTx.Hash(), ctx.Err())
+		case <-time.After(receiptPollingInterval):
+		}
+	}
+}
+
+type pChain struct {
+	network models.Network
+	kc      keychain.Keychain
+}
+
+// NewPChain creates a faucet chain that sends AVAX on the P-Chain of [network], from [kc]
+func NewPChain(network models.Network, kc keychain.Keychain) Chain {
+	return &pChain{
+		network: network,
+		kc:      kc,
+	}
+}
+
+func (*pChain) Name() string {
+	return "P"
+}
+
+func (*pChain) Symbol() string {
+	return "AVAX"
+}
+
+func (c *pChain) ValidateAddress(addr string) error {
+	chainAlias, hrp, _, err := address.Parse(addr)
+	if err != nil {
+		return err
+	}
+	if chainAlias != "P" {
+		return errors.New("expected a P-Chain address")
+	}
+	if expectedHRP := key.GetHRP(c.network.ID); hrp != expectedHRP {
+		return fmt.Errorf("expected address hrp %s", expectedHRP)
+	}
+	return nil
+}
+
+// Transfer issues a P-Chain base tx, which is accepted when issuing returns. If the tx was
+// signed, its ID is returned together with any error, as it may have been issued
+func (c *pChain) Transfer(ctx context.Context, addr string, amount float64) (string, error) {
+	to, err := address.ParseToID(addr)
+	if err != nil {
+		return "", err
+	}
+	wallet, err := primary.MakeWallet(
+		ctx,
+		&primary.WalletConfig{
+			URI:          c.network.Endpoint,
+			AVAXKeychain: c.kc,
+			EthKeychain:  secp256k1fx.NewKeychain(),
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	output := &avax.TransferableOutput{
+		Asset: avax.Asset{ID: wallet.P().AVAXAssetID()},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: uint64(amount * float64(units.Avax)),
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{to},
+			},
+		},
+	}
+	tx, err := wallet.P().IssueBaseTx([]*avax.TransferableOutput{output}, walletcommon.WithContext(ctx))
+	if err != nil {
+		if tx != nil {
+			return tx.ID().String(), err
+		}
+		return "", err
+	}
+	return tx.ID().String(), nil
+}
+
+// toBaseUnits converts an amount of tokens with [decimals] decimals to its minimal unit
+func toBaseUnits(amount float64, decimals uint8) *big.Int {
+	unit := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	baseUnits, _ := new(big.Float).Mul(big.NewFloat(amount), unit).Int(nil)
+	return baseUnits
+}
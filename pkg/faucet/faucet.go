@@ -0,0 +1,208 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package faucet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+)
+
+// max number of transfers kept in memory to be shown by the faucet
+const maxRecentTransfers = 100
+
+var (
+	ErrUnknownChain   = errors.New("unknown chain")
+	ErrInvalidAddress = errors.New("invalid address")
+	ErrRateLimited    = errors.New("address has recently received tokens")
+)
+
+// Chain is a blockchain the faucet sends tokens on
+type Chain interface {
+	// Name identifies the chain on the faucet API
+	Name() string
+	// Symbol of the token sent on the chain
+	Symbol() string
+	// ValidateAddress returns an error if [addr] is not a valid address for the chain
+	ValidateAddress(addr string) error
+	// Transfer sends [amount] tokens to [addr], returning the ID of the transaction. The ID is
+	// also returned on errors happening once the transaction was issued
+	Transfer(ctx context.Context, addr string, amount float64) (string, error)
+}
+
+// Transfer is a drip issued by the faucet
+type Transfer struct {
+	Time    time.Time `json:"time"`
+	Chain   string    `json:"chain"`
+	Address string    `json:"address"`
+	Amount  float64   `json:"amount"`
+	Symbol  string    `json:"symbol"`
+	TxID    string    `json:"txID"`
+}
+
+type Faucet struct {
+	chains     map[string]Chain
+	chainNames []string
+	amount     float64
+	rateLimit  time.Duration
+	logPath    string
+
+	lock            sync.Mutex
+	chainLocks      map[string]*sync.Mutex
+	lastDrips       map[string]time.Time
+	recentTransfers []Transfer
+
+	// for testing purposes
+	now func() time.Time
+}
+
+// New creates a faucet that sends [amount] tokens per drip on the given chains,
+// at most once every [rateLimit] per chain and address. Issued transfers are
+// appended to [logPath] as json lines
+func New(chains []Chain, amount float64, rateLimit time.Duration, logPath string) (*Faucet, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("invalid drip amount %f", amount)
+	}
+	f := &Faucet{
+		chains:     map[string]Chain{},
+		amount:     amount,
+		rateLimit:  rateLimit,
+		logPath:    logPath,
+		chainLocks: map[string]*sync.Mutex{},
+		lastDrips:  map[string]time.Time{},
+		now:        time.Now,
+	}
+	for _, chain := range chains {
+		if _, ok := f.chains[chain.Name()]; ok {
+			return nil, fmt.Errorf("duplicated faucet chain %s", chain.Name())
+		}
+		f.chains[chain.Name()] = chain
+		f.chainNames = append(f.chainNames, chain.Name())
+		f.chainLocks[chain.Name()] = &sync.Mutex{}
+	}
+	sort.Strings(f.chainNames)
+	return f, nil
+}
+
+// Chains returns the faucet chains, sorted by name
+func (f *Faucet) Chains() []Chain {
+	chains := make([]Chain, len(f.chainNames))
+	for i, chainName := range f.chainNames {
+		chains[i] = f.chains[chainName]
+	}
+	return chains
+}
+
+// RecentTransfers returns the latest transfers issued by the faucet, newest first
+func (f *Faucet) RecentTransfers() []Transfer {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	transfers := make([]Transfer, len(f.recentTransfers))
+	for i, transfer := range f.recentTransfers {
+		transfers[len(transfers)-1-i] = transfer
+	}
+	return transfers
+}
+
+// Drip sends the drip amount to [addr] on the given chain, unless the address
+// already received tokens on it in the rate limit period
+func (f *Faucet) Drip(ctx context.Context, chainName string, addr string) (Transfer, error) {
+	chain, ok := f.chains[chainName]
+	if !ok {
+		return Transfer{}, fmt.Errorf("%w %q", ErrUnknownChain, chainName)
+	}
+	addr = strings.TrimSpace(addr)
+	if err := chain.ValidateAddress(addr); err != nil {
+		return Transfer{}, fmt.Errorf("%w %q for %s: %s", ErrInvalidAddress, addr, chainName, err)
+	}
+	dripKey := chainName + "/" + strings.ToLower(addr)
+	previousDrip, err := f.reserveDrip(dripKey)
+	if err != nil {
+		return Transfer{}, err
+	}
+	// transfers on the same chain are serialized as they are issued from the same key
+	chainLock := f.chainLocks[chainName]
+	chainLock.Lock()
+	txID, err := chain.Transfer(ctx, addr, f.amount)
+	chainLock.Unlock()
+	if err != nil {
+		if txID != "" {
+			// the tx may still be accepted, so it keeps counting for the rate limit
+			return Transfer{}, fmt.Errorf("failed sending tokens on %s, tx %s: %w", chainName, txID, err)
+		}
+		f.releaseDrip(dripKey, previousDrip)
+		return Transfer{}, fmt.Errorf("failed sending tokens on %s: %w", chainName, err)
+	}
+	transfer := Transfer{
+		Time:    f.now(),
+		Chain:   chainName,
+		Address: addr,
+		Amount:  f.amount,
+		Symbol:  chain.Symbol(),
+		TxID:    txID,
+	}
+	if err := f.recordTransfer(transfer); err != nil {
+		return transfer, fmt.Errorf("tokens sent on tx %s, but failed logging the transfer: %w", txID, err)
+	}
+	return transfer, nil
+}
+
+// reserveDrip marks [dripKey] as served now, returning the time it was previously served
+func (f *Faucet) reserveDrip(dripKey string) (time.Time, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	now := f.now()
+	previousDrip, ok := f.lastDrips[dripKey]
+	if ok && now.Sub(previousDrip) < f.rateLimit {
+		retryIn := previousDrip.Add(f.rateLimit).Sub(now).Round(time.Second)
+		return time.Time{}, fmt.Errorf("%w, try again in %s", ErrRateLimited, retryIn)
+	}
+	f.lastDrips[dripKey] = now
+	return previousDrip, nil
+}
+
+// releaseDrip restores the time [dripKey] was served before a drip that issued no tx
+func (f *Faucet) releaseDrip(dripKey string, previousDrip time.Time) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if previousDrip.IsZero() {
+		delete(f.lastDrips, dripKey)
+	} else {
+		f.lastDrips[dripKey] = previousDrip
+	}
+}
+
+func (f *Faucet) recordTransfer(transfer Transfer) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.recentTransfers = append(f.recentTransfers, transfer)
+	if len(f.recentTransfers) > maxRecentTransfers {
+		f.recentTransfers = f.recentTransfers[len(f.recentTransfers)-maxRecentTransfers:]
+	}
+	if f.logPath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(f.logPath), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	transferBytes, err := json.Marshal(transfer)
+	if err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(f.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, constants.WriteReadReadPerms)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	_, err = logFile.Write(append(transferBytes, '\n'))
+	return err
+}
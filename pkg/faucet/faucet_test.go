@@ -0,0 +1,146 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package faucet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChain struct {
+	transfers  int
+	fail       bool
+	failIssued bool
+}
+
+func (*fakeChain) Name() string {
+	return "test"
+}
+
+func (*fakeChain) Symbol() string {
+	return "TEST"
+}
+
+func (*fakeChain) ValidateAddress(addr string) error {
+	if !strings.HasPrefix(addr, "0x") {
+		return errors.New("expected 0x prefix")
+	}
+	return nil
+}
+
+func (c *fakeChain) Transfer(context.Context, string, float64) (string, error) {
+	if c.fail {
+		return "", errors.New("transfer failed")
+	}
+	c.transfers++
+	if c.failIssued {
+		return fmt.Sprintf("tx%d", c.transfers), errors.New("timeout waiting for tx")
+	}
+	return fmt.Sprintf("tx%d", c.transfers), nil
+}
+
+func TestDrip(t *testing.T) {
+	require := require.New(t)
+	chain := &fakeChain{}
+	logPath := filepath.Join(t.TempDir(), "logs", "faucet.log")
+	f, err := New([]Chain{chain}, 2.5, time.Hour, logPath)
+	require.NoError(err)
+	now := time.Date(2023, 10, 20, 12, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { return now }
+
+	transfer, err := f.Drip(context.Background(), "test", "0xabc")
+	require.NoError(err)
+	require.Equal("tx1", transfer.TxID)
+	require.Equal(2.5, transfer.Amount)
+
+	_, err = f.Drip(context.Background(), "other", "0xabc")
+	require.ErrorIs(err, ErrUnknownChain)
+	_, err = f.Drip(context.Background(), "test", "abc")
+	require.ErrorIs(err, ErrInvalidAddress)
+
+	// rate limit is per address, case insensitive
+	_, err = f.Drip(context.Background(), "test", "0xABC")
+	require.ErrorIs(err, ErrRateLimited)
+	require.ErrorContains(err, "1h0m0s")
+	now = now.Add(time.Hour)
+	_, err = f.Drip(context.Background(), "test", "0xABC")
+	require.NoError(err)
+
+	// failed transfers do not count for the rate limit
+	chain.fail = true
+	_, err = f.Drip(context.Background(), "test", "0xdef")
+	require.Error(err)
+	chain.fail = false
+	_, err = f.Drip(context.Background(), "test", "0xdef")
+	require.NoError(err)
+
+	// issued transfers that fail afterwards still count for the rate limit
+	chain.failIssued = true
+	_, err = f.Drip(context.Background(), "test", "0x123")
+	require.ErrorContains(err, "tx4")
+	chain.failIssued = false
+	_, err = f.Drip(context.Background(), "test", "0x123")
+	require.ErrorIs(err, ErrRateLimited)
+
+	transfers := f.RecentTransfers()
+	require.Len(transfers, 3)
+	require.Equal("tx3", transfers[0].TxID)
+
+	logBytes, err := os.ReadFile(logPath)
+	require.NoError(err)
+	lines := strings.Split(strings.TrimSpace(string(logBytes)), "\n")
+	require.Len(lines, 3)
+	var logged Transfer
+	require.NoError(json.Unmarshal([]byte(lines[0]), &logged))
+	require.Equal("0xabc", logged.Address)
+}
+
+func TestToBaseUnits(t *testing.T) {
+	require := require.New(t)
+	require.Equal("1500000000000000000", toBaseUnits(1.5, EVMTokenDecimals).String())
+	require.Equal("1500000", toBaseUnits(1.5, 6).String())
+	require.Equal("2", toBaseUnits(2, 0).String())
+}
+
+func TestHandler(t *testing.T) {
+	require := require.New(t)
+	f, err := New([]Chain{&fakeChain{}}, 1, time.Hour, "")
+	require.NoError(err)
+	server := httptest.NewServer(f.Handler())
+	defer server.Close()
+
+	drip := func(body string) int {
+		resp, err := http.Post(server.URL+"/api/drip", "application/json", strings.NewReader(body))
+		require.NoError(err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+	require.Equal(http.StatusOK, drip(`{"chain": "test", "address": "0x1"}`))
+	require.Equal(http.StatusTooManyRequests, drip(`{"chain": "test", "address": "0x1"}`))
+	require.Equal(http.StatusBadRequest, drip(`{"chain": "test", "address": "1"}`))
+	require.Equal(http.StatusBadRequest, drip(`{"chain": "C", "address": "0x1"}`))
+	require.Equal(http.StatusBadRequest, drip(`not json`))
+
+	resp, err := http.Get(server.URL + "/")
+	require.NoError(err)
+	defer resp.Body.Close()
+	require.Equal(http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/api/transfers")
+	require.NoError(err)
+	defer resp.Body.Close()
+	var transfers []Transfer
+	require.NoError(json.NewDecoder(resp.Body).Decode(&transfers))
+	require.Len(transfers, 1)
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package faucet
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+)
+
+// max size accepted for a drip request body
+const maxRequestSize = 4096
+
+type dripRequest struct {
+	Chain   string `json:"chain"`
+	Address string `json:"address"`
+}
+
+type chainResponse struct {
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+var pageTemplate = template.Must(template.New("faucet").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Avalanche Faucet</title>
+<style>
+body { font-family: sans-serif; max-width: 720px; margin: 2em auto; }
+input, select, button { font-size: 1em; padding: 0.3em; }
+#address { width: 30em; }
+table { border-collapse: collapse; margin-top: 2em; font-size: 0.85em; }
+td, th { border: 1px solid #ccc; padding: 0.3em; }
+</style>
+</head>
+<body>
+<h1>Avalanche Faucet</h1>
+<p>Each request sends {{.Amount}} tokens to the given address.</p>
+<form id="drip">
+<select id="chain">
+{{range .Chains}}<option value="{{.Name}}">{{.Name}} ({{.Symbol}})</option>
+{{end}}</select>
+<input id="address" placeholder="0x... or P-...">
+<button type="submit">Send</button>
+</form>
+<p id="result"></p>
+<table id="transfers"><tr><th>Time</th><th>Chain</th><th>Address</th><th>Amount</th><th>Tx ID</th></tr></table>
+<script>
+function showTransfers() {
+  fetch("/api/transfers").then(r => r.json()).then(transfers => {
+    const table = document.getElementById("transfers");
+    while (table.rows.length > 1) table.deleteRow(1);
+    for (const t of transfers) {
+      const row = table.insertRow();
+      for (const v of [new Date(t.time).toLocaleString(), t.chain, t.address, t.amount + " " + t.symbol, t.txID]) {
+        row.insertCell().textContent = v;
+      }
+    }
+  });
+}
+document.getElementById("drip").addEventListener("submit", e => {
+  e.preventDefault();
+  const result = document.getElementById("result");
+  result.textContent = "Sending...";
+  fetch("/api/drip", {
+    method: "POST",
+    headers: {"Content-Type": "application/json"},
+    body: JSON.stringify({chain: document.getElementById("chain").value, address: document.getElementById("address").value}),
+  }).then(r => r.json()).then(resp => {
+    result.textContent = resp.error ? "Error: " + resp.error : "Sent on tx " + resp.txID;
+    showTransfers();
+  });
+});
+showTransfers();
+</script>
+</body>
+</html>
+`))
+
+// Handler returns the faucet HTTP handler, serving the faucet page at / and the
+// json API at /api/chains, /api/drip and /api/transfers
+func (f *Faucet) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", f.handlePage)
+	mux.HandleFunc("/api/chains", f.handleChains)
+	mux.HandleFunc("/api/drip", f.handleDrip)
+	mux.HandleFunc("/api/transfers", f.handleTransfers)
+	return mux
+}
+
+func (f *Faucet) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pageTemplate.Execute(w, struct {
+		Amount float64
+		Chains []Chain
+	}{
+		Amount: f.amount,
+		Chains: f.Chains(),
+	})
+}
+
+func (f *Faucet) handleChains(w http.ResponseWriter, _ *http.Request) {
+	chains := []chainResponse{}
+	for _, chain := range f.Chains() {
+		chains = append(chains, chainResponse{Name: chain.Name(), Symbol: chain.Symbol()})
+	}
+	writeJSON(w, http.StatusOK, chains)
+}
+
+func (f *Faucet) handleTransfers(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, f.RecentTransfers())
+}
+
+func (f *Faucet) handleDrip(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "drip requests must use POST"})
+		return
+	}
+	var req dripRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid request: " + err.Error()})
+		return
+	}
+	transfer, err := f.Drip(r.Context(), req.Chain, req.Address)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, ErrRateLimited):
+			status = http.StatusTooManyRequests
+		case errors.Is(err, ErrUnknownChain), errors.Is(err, ErrInvalidAddress):
+			status = http.StatusBadRequest
+		}
+		writeJSON(w, status, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, transfer)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
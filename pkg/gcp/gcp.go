@@ -14,8 +14,13 @@ import (
 	"google.golang.org/api/compute/v1"
 )
 
-func GetUbuntuImageID(gcpClient *compute.Service) (string, error) {
-	imageListCall := gcpClient.Images.List(constants.GCPDefaultImageProvider).Filter(constants.GCPImageFilter)
+// GetUbuntuImageID returns the latest Ubuntu 20.04 image for the cpu architecture [arch] (amd64 / arm64)
+func GetUbuntuImageID(gcpClient *compute.Service, arch string) (string, error) {
+	gcpArch := "x86_64"
+	if arch == constants.ArchARM64 {
+		gcpArch = "arm64"
+	}
+	imageListCall := gcpClient.Images.List(constants.GCPDefaultImageProvider).Filter(fmt.Sprintf(constants.GCPImageFilter, gcpArch))
 	imageList, err := imageListCall.Do()
 	if err != nil {
 		return "", err
@@ -107,13 +112,41 @@ func StopGCPNode(gcpClient *compute.Service, nodeConfig models.NodeConfig, proje
 		return err
 	}
 	if releasePublicIP && nodeConfig.ElasticIP != "" {
-		ux.Logger.PrintToUser(fmt.Sprintf("Releasing static IP address %s ...", nodeConfig.ElasticIP))
-		// GCP node region is stored in format of "us-east1-b", we need "us-east1"
-		region := strings.Join(strings.Split(nodeConfig.Region, "-")[:2], "-")
-		addressReleaseCall := gcpClient.Addresses.Delete(projectName, region, fmt.Sprintf("%s-%s", constants.GCPStaticIPPrefix, nodeConfig.NodeID))
-		if _, err = addressReleaseCall.Do(); err != nil {
-			return fmt.Errorf("%s, %w", constants.ErrReleasingGCPStaticIP, err)
-		}
+		return releaseStaticIP(gcpClient, nodeConfig, projectName)
+	}
+	return nil
+}
+
+func releaseStaticIP(gcpClient *compute.Service, nodeConfig models.NodeConfig, projectName string) error {
+	ux.Logger.PrintToUser(fmt.Sprintf("Releasing static IP address %s ...", nodeConfig.ElasticIP))
+	// GCP node region is stored in format of "us-east1-b", we need "us-east1"
+	region := strings.Join(strings.Split(nodeConfig.Region, "-")[:2], "-")
+	addressReleaseCall := gcpClient.Addresses.Delete(projectName, region, fmt.Sprintf("%s-%s", constants.GCPStaticIPPrefix, nodeConfig.NodeID))
+	if _, err := addressReleaseCall.Do(); err != nil {
+		return fmt.Errorf("%s, %w", constants.ErrReleasingGCPStaticIP, err)
 	}
 	return nil
 }
+
+// TerminateGCPNode deletes the instance of [nodeConfig], and releases its static IP once
+// the instance is gone
+func TerminateGCPNode(gcpClient *compute.Service, nodeConfig models.NodeConfig, projectName, clusterName string) error {
+	ux.Logger.PrintToUser(fmt.Sprintf("Terminating node instance %s in cluster %s...", nodeConfig.NodeID, clusterName))
+	operation, err := gcpClient.Instances.Delete(projectName, nodeConfig.Region, nodeConfig.NodeID).Do()
+	if err != nil {
+		return err
+	}
+	for operation.Status != "DONE" {
+		operation, err = gcpClient.ZoneOperations.Wait(projectName, nodeConfig.Region, operation.Name).Do()
+		if err != nil {
+			return err
+		}
+	}
+	if operation.Error != nil && len(operation.Error.Errors) > 0 {
+		return fmt.Errorf("failed to delete instance %s: %s", nodeConfig.NodeID, operation.Error.Errors[0].Message)
+	}
+	if nodeConfig.ElasticIP == "" {
+		return nil
+	}
+	return releaseStaticIP(gcpClient, nodeConfig, projectName)
+}
@@ -8,7 +8,7 @@ import (
 	"errors"
 	"strings"
 
-	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanchego/api/info"
 )
 
@@ -16,15 +16,18 @@ type StatusChecker interface {
 	GetCurrentNetworkVersion() (string, int, bool, error)
 }
 
-type networkStatusChecker struct{}
+type networkStatusChecker struct {
+	network models.Network
+}
 
-func NewStatusChecker() StatusChecker {
-	return networkStatusChecker{}
+// NewStatusChecker returns a StatusChecker for the local [network]
+func NewStatusChecker(network models.Network) StatusChecker {
+	return networkStatusChecker{network: network}
 }
 
-func (networkStatusChecker) GetCurrentNetworkVersion() (string, int, bool, error) {
+func (c networkStatusChecker) GetCurrentNetworkVersion() (string, int, bool, error) {
 	ctx := context.Background()
-	infoClient := info.NewClient(constants.LocalAPIEndpoint)
+	infoClient := info.NewClient(c.network.Endpoint)
 	versionResponse, err := infoClient.GetNodeVersion(ctx)
 	if err != nil {
 		// not actually an error, network just not running
@@ -0,0 +1,100 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ClusterAvalancheGoConfig is the avalanchego config desired for the nodes of a cluster, as
+// changes to apply on top of the node.json of each node. Keys set to nil are removed from it
+type ClusterAvalancheGoConfig struct {
+	Cluster map[string]interface{}            // applied to all nodes
+	Nodes   map[string]map[string]interface{} // overrides of the cluster config, by node cloud ID
+}
+
+// GetNodeChanges returns the changes to apply on [node], the cluster ones overridden by the node ones
+func (c ClusterAvalancheGoConfig) GetNodeChanges(node string) map[string]interface{} {
+	changes := map[string]interface{}{}
+	for key, value := range c.Cluster {
+		changes[key] = value
+	}
+	for key, value := range c.Nodes[node] {
+		changes[key] = value
+	}
+	return changes
+}
+
+// Set sets [key] to [value] for all nodes if [node] is empty, or else only for [node]
+func (c *ClusterAvalancheGoConfig) Set(node string, key string, value interface{}) {
+	if node == "" {
+		if c.Cluster == nil {
+			c.Cluster = map[string]interface{}{}
+		}
+		c.Cluster[key] = value
+		return
+	}
+	if c.Nodes == nil {
+		c.Nodes = map[string]map[string]interface{}{}
+	}
+	if c.Nodes[node] == nil {
+		c.Nodes[node] = map[string]interface{}{}
+	}
+	c.Nodes[node][key] = value
+}
+
+// GetDesiredNodeConfig returns the node.json of [node] with the desired changes applied to [current]
+func (c ClusterAvalancheGoConfig) GetDesiredNodeConfig(node string, current map[string]interface{}) map[string]interface{} {
+	desired := map[string]interface{}{}
+	for key, value := range current {
+		desired[key] = value
+	}
+	for key, value := range c.GetNodeChanges(node) {
+		if value == nil {
+			delete(desired, key)
+			continue
+		}
+		desired[key] = value
+	}
+	return desired
+}
+
+// AvalancheGoConfigChange is a difference between the current and desired node.json of a node
+type AvalancheGoConfigChange struct {
+	Key      string
+	Current  interface{} // nil if not set
+	Desired  interface{} // nil if removed
+	IsNew    bool
+	IsRemove bool
+}
+
+// DiffAvalancheGoConfig returns the changes from [current] to [desired], sorted by key
+func DiffAvalancheGoConfig(current map[string]interface{}, desired map[string]interface{}) []AvalancheGoConfigChange {
+	keys := []string{}
+	for key := range current {
+		keys = append(keys, key)
+	}
+	for key := range desired {
+		if _, ok := current[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	changes := []AvalancheGoConfigChange{}
+	for _, key := range keys {
+		currentValue, inCurrent := current[key]
+		desiredValue, inDesired := desired[key]
+		if inCurrent && inDesired && reflect.DeepEqual(currentValue, desiredValue) {
+			continue
+		}
+		changes = append(changes, AvalancheGoConfigChange{
+			Key:      key,
+			Current:  currentValue,
+			Desired:  desiredValue,
+			IsNew:    !inCurrent,
+			IsRemove: !inDesired,
+		})
+	}
+	return changes
+}
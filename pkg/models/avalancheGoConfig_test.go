@@ -0,0 +1,41 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterAvalancheGoConfig(t *testing.T) {
+	require := require.New(t)
+	config := ClusterAvalancheGoConfig{}
+	config.Set("", "log-level", "debug")
+	config.Set("", "api-admin-enabled", true)
+	config.Set("node1", "log-level", "info")
+	config.Set("node1", "http-host", nil)
+
+	current := map[string]interface{}{
+		"http-host": "",
+		"public-ip": "1.2.3.4",
+		"log-level": "info",
+	}
+	require.Equal(map[string]interface{}{
+		"http-host":         "",
+		"public-ip":         "1.2.3.4",
+		"log-level":         "debug",
+		"api-admin-enabled": true,
+	}, config.GetDesiredNodeConfig("node2", current))
+	desired := config.GetDesiredNodeConfig("node1", current)
+	require.Equal(map[string]interface{}{
+		"public-ip":         "1.2.3.4",
+		"log-level":         "info",
+		"api-admin-enabled": true,
+	}, desired)
+	require.Equal([]AvalancheGoConfigChange{
+		{Key: "api-admin-enabled", Desired: true, IsNew: true},
+		{Key: "http-host", Current: "", IsRemove: true},
+	}, DiffAvalancheGoConfig(current, desired))
+	require.Empty(DiffAvalancheGoConfig(desired, desired))
+}
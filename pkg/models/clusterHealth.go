@@ -0,0 +1,115 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	HealthCheckBootstrapped    = "bootstrapped"
+	HealthCheckHealthy         = "healthy"
+	HealthCheckSubnetSynced    = "subnet-synced"
+	HealthCheckValidatorUptime = "validator-uptime"
+)
+
+// HealthCheckResult is the result of one health check on one node of a cluster, or on all
+// of them when the check itself failed to run
+type HealthCheckResult struct {
+	Check  string
+	Node   string // empty for results of the whole cluster
+	Subnet string `json:",omitempty"`
+	OK     bool
+	Detail string `json:",omitempty"`
+}
+
+// Key identifies the check and node of the result, to compare it with other runs
+func (r HealthCheckResult) Key() string {
+	if r.Subnet != "" {
+		return fmt.Sprintf("%s/%s/%s", r.Check, r.Node, r.Subnet)
+	}
+	return fmt.Sprintf("%s/%s", r.Check, r.Node)
+}
+
+func (r HealthCheckResult) String() string {
+	state := "OK"
+	if !r.OK {
+		state = "FAILED"
+	}
+	node := r.Node
+	if node == "" {
+		node = "all nodes"
+	}
+	s := fmt.Sprintf("%s %s on %s", r.Check, state, node)
+	if r.Subnet != "" {
+		s += fmt.Sprintf(" for subnet %s", r.Subnet)
+	}
+	if r.Detail != "" {
+		s += fmt.Sprintf(" (%s)", r.Detail)
+	}
+	return s
+}
+
+// ClusterHealthReport holds the results of a run of the health checks of a cluster
+type ClusterHealthReport struct {
+	ClusterName string
+	Time        time.Time
+	Results     []HealthCheckResult
+}
+
+// Failed returns the results of the checks that failed
+func (r ClusterHealthReport) Failed() []HealthCheckResult {
+	failed := []HealthCheckResult{}
+	for _, result := range r.Results {
+		if !result.OK {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Changes returns the results that changed state since [previous]. Checks not run before
+// are considered to have been OK. Failed results that are gone are returned as recovered:
+// results of the whole cluster, as the check could be run again, and results of a node, as
+// the node was removed or the check now fails for the whole cluster
+func (r ClusterHealthReport) Changes(previous ClusterHealthReport) []HealthCheckResult {
+	previousOK := map[string]bool{}
+	for _, result := range previous.Results {
+		previousOK[result.Key()] = result.OK
+	}
+	current := map[string]bool{}
+	changes := []HealthCheckResult{}
+	for _, result := range r.Results {
+		current[result.Key()] = true
+		wasOK, found := previousOK[result.Key()]
+		if !found {
+			wasOK = true
+		}
+		if result.OK != wasOK {
+			changes = append(changes, result)
+		}
+	}
+	for _, result := range previous.Results {
+		if !result.OK && !current[result.Key()] {
+			changes = append(changes, HealthCheckResult{Check: result.Check, Node: result.Node, Subnet: result.Subnet, OK: true})
+		}
+	}
+	return changes
+}
+
+// Summary returns a human readable summary of [changes], to be sent as alert message
+func (r ClusterHealthReport) Summary(changes []HealthCheckResult) string {
+	lines := []string{}
+	failed := r.Failed()
+	if len(failed) == 0 {
+		lines = append(lines, fmt.Sprintf("Cluster %s is healthy", r.ClusterName))
+	} else {
+		lines = append(lines, fmt.Sprintf("Cluster %s has %d failing check(s)", r.ClusterName, len(failed)))
+	}
+	for _, change := range changes {
+		lines = append(lines, "- "+change.String())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterHealthReportChanges(t *testing.T) {
+	require := require.New(t)
+	previous := ClusterHealthReport{
+		ClusterName: "c1",
+		Results: []HealthCheckResult{
+			{Check: HealthCheckHealthy, Node: "node1", OK: true},
+			{Check: HealthCheckHealthy, Node: "node2", OK: false},
+			{Check: HealthCheckSubnetSynced, Node: "node1", Subnet: "s1", OK: true},
+		},
+	}
+	current := ClusterHealthReport{
+		ClusterName: "c1",
+		Results: []HealthCheckResult{
+			{Check: HealthCheckHealthy, Node: "node1", OK: false},
+			{Check: HealthCheckHealthy, Node: "node2", OK: true},
+			{Check: HealthCheckSubnetSynced, Node: "node1", Subnet: "s1", OK: true},
+			{Check: HealthCheckSubnetSynced, Node: "node1", Subnet: "s2", OK: true},
+			{Check: HealthCheckValidatorUptime, Node: "node1", OK: false, Detail: "uptime 50.00%"},
+		},
+	}
+	changes := current.Changes(previous)
+	require.Equal([]HealthCheckResult{
+		{Check: HealthCheckHealthy, Node: "node1", OK: false},
+		{Check: HealthCheckHealthy, Node: "node2", OK: true},
+		{Check: HealthCheckValidatorUptime, Node: "node1", OK: false, Detail: "uptime 50.00%"},
+	}, changes)
+	require.Len(current.Failed(), 2)
+	require.Empty(current.Changes(current))
+	require.Empty(ClusterHealthReport{Results: []HealthCheckResult{{Check: HealthCheckHealthy, Node: "node1", OK: true}}}.Changes(ClusterHealthReport{}))
+	require.Equal(`Cluster c1 has 2 failing check(s)
+- healthy FAILED on node1
+- healthy OK on node2
+- validator-uptime FAILED on node1 (uptime 50.00%)`, current.Summary(changes))
+}
+
+func TestClusterHealthReportClusterChanges(t *testing.T) {
+	require := require.New(t)
+	failed := ClusterHealthReport{
+		ClusterName: "c1",
+		Results: []HealthCheckResult{
+			{Check: HealthCheckHealthy, Node: "node1", OK: true},
+			{Check: HealthCheckValidatorUptime, OK: false, Detail: "P-Chain API unreachable"},
+		},
+	}
+	recovered := ClusterHealthReport{
+		ClusterName: "c1",
+		Results: []HealthCheckResult{
+			{Check: HealthCheckHealthy, Node: "node1", OK: true},
+			{Check: HealthCheckValidatorUptime, Node: "node1", OK: true},
+		},
+	}
+	changes := failed.Changes(recovered)
+	require.Equal([]HealthCheckResult{
+		{Check: HealthCheckValidatorUptime, OK: false, Detail: "P-Chain API unreachable"},
+	}, changes)
+	require.Equal(`Cluster c1 has 1 failing check(s)
+- validator-uptime FAILED on all nodes (P-Chain API unreachable)`, failed.Summary(changes))
+	require.Equal([]HealthCheckResult{
+		{Check: HealthCheckValidatorUptime, OK: true},
+	}, recovered.Changes(failed))
+}
+
+func TestClusterHealthReportNodeResultsGone(t *testing.T) {
+	require := require.New(t)
+	previous := ClusterHealthReport{
+		ClusterName: "c1",
+		Results: []HealthCheckResult{
+			{Check: HealthCheckHealthy, Node: "node1", OK: true},
+			{Check: HealthCheckHealthy, Node: "node2", OK: false},
+			{Check: HealthCheckSubnetSynced, Node: "node1", Subnet: "s1", OK: false, Detail: "Unknown"},
+		},
+	}
+	// node2 was removed, and the subnet sync check now fails for the whole cluster
+	current := ClusterHealthReport{
+		ClusterName: "c1",
+		Results: []HealthCheckResult{
+			{Check: HealthCheckHealthy, Node: "node1", OK: true},
+			{Check: HealthCheckSubnetSynced, Subnet: "s1", OK: false, Detail: "blockchain not found"},
+		},
+	}
+	require.Equal([]HealthCheckResult{
+		{Check: HealthCheckSubnetSynced, Subnet: "s1", OK: false, Detail: "blockchain not found"},
+		{Check: HealthCheckHealthy, Node: "node2", OK: true},
+		{Check: HealthCheckSubnetSynced, Node: "node1", Subnet: "s1", OK: true},
+	}, current.Changes(previous))
+	// results gone that were OK are not changes
+	require.Empty(ClusterHealthReport{}.Changes(ClusterHealthReport{
+		Results: []HealthCheckResult{{Check: HealthCheckHealthy, Node: "node1", OK: true}},
+	}))
+}
@@ -8,8 +8,9 @@ type GCPConfig struct {
 }
 
 type ClusterConfig struct {
-	Nodes   []string
-	Network Network
+	Nodes              []string
+	Network            Network
+	MonitoringInstance string // cloud ID of the server running Prometheus and Grafana for the cluster, if set up
 }
 
 type ClustersConfig struct {
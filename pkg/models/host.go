@@ -33,6 +33,10 @@ func HostCloudIDToAnsibleID(cloudService string, hostCloudID string) (string, er
 		return fmt.Sprintf("%s_%s", constants.GCPNodeAnsiblePrefix, hostCloudID), nil
 	case constants.AWSCloudService:
 		return fmt.Sprintf("%s_%s", constants.AWSNodeAnsiblePrefix, hostCloudID), nil
+	case constants.ExistingHostsService:
+		return fmt.Sprintf("%s_%s", constants.ExistingNodeAnsiblePrefix, hostCloudID), nil
+	case constants.LocalMachineService:
+		return fmt.Sprintf("%s_%s", constants.LocalNodeAnsiblePrefix, hostCloudID), nil
 	}
 	return "", fmt.Errorf("unknown cloud service %s", cloudService)
 }
@@ -42,6 +46,10 @@ func HostAnsibleIDToCloudID(hostAnsibleID string) (string, string, error) {
 		return constants.AWSCloudService, strings.TrimPrefix(hostAnsibleID, constants.AWSNodeAnsiblePrefix+"_"), nil
 	} else if strings.HasPrefix(hostAnsibleID, constants.GCPNodeAnsiblePrefix) {
 		return constants.GCPCloudService, strings.TrimPrefix(hostAnsibleID, constants.GCPNodeAnsiblePrefix+"_"), nil
+	} else if strings.HasPrefix(hostAnsibleID, constants.ExistingNodeAnsiblePrefix) {
+		return constants.ExistingHostsService, strings.TrimPrefix(hostAnsibleID, constants.ExistingNodeAnsiblePrefix+"_"), nil
+	} else if strings.HasPrefix(hostAnsibleID, constants.LocalNodeAnsiblePrefix) {
+		return constants.LocalMachineService, strings.TrimPrefix(hostAnsibleID, constants.LocalNodeAnsiblePrefix+"_"), nil
 	}
 	return "", "", fmt.Errorf("unknown cloud service prefix in %s", hostAnsibleID)
 }
@@ -37,6 +37,8 @@ type Network struct {
 	Kind     NetworkKind
 	ID       uint32
 	Endpoint string
+	// Instance is the name of the local network instance, empty for the default one
+	Instance string `json:",omitempty"`
 }
 
 var (
@@ -55,6 +57,13 @@ func NewNetwork(kind NetworkKind, id uint32, endpoint string) Network {
 	}
 }
 
+// NewLocalNetwork returns the named local network [instance], reachable at [endpoint]
+func NewLocalNetwork(instance string, endpoint string) Network {
+	network := NewNetwork(Local, constants.LocalNetworkID, endpoint)
+	network.Instance = instance
+	return network
+}
+
 func NewDevnetNetwork(ip string, port int) Network {
 	endpoint := fmt.Sprintf("http://%s:%d", ip, port)
 	return NewNetwork(Devnet, constants.DevnetNetworkID, endpoint)
@@ -88,7 +97,12 @@ func NetworkFromNetworkID(networkID uint32) Network {
 	return UndefinedNetwork
 }
 
+// Name identifies the network, and is the key of its deploy info in the sidecars. Each
+// named local network instance has its own name
 func (n Network) Name() string {
+	if n.Kind == Local && n.Instance != "" {
+		return fmt.Sprintf("%s %s", n.Kind.String(), n.Instance)
+	}
 	return n.Kind.String()
 }
 
@@ -11,4 +11,11 @@ type NodeConfig struct {
 	SecurityGroup string // security group used on cloud server
 	ElasticIP     string // public IP address of the cloud server
 	CloudService  string // which cloud service node is hosted on (AWS / GCP)
+	HTTPPort      uint32 // avalanchego API port, only set for nodes running on the local machine
+	StakingPort   uint32 // avalanchego staking port, only set for nodes running on the local machine
+	Arch          string // cpu architecture of the cloud server (amd64 / arm64), amd64 if not set
+	InstanceType  string // instance type of the cloud server
+	DiskSize      int    // disk size in GB of the cloud server
+	DiskType      string // disk type of the cloud server
+	DiskIOPS      int    // provisioned disk IOPS of the cloud server, 0 if not set
 }
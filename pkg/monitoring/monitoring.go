@@ -0,0 +1,148 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package monitoring
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed dashboards/*.json
+var dashboards embed.FS
+
+const (
+	PrometheusConfigFileName  = "prometheus.yml"
+	DatasourceConfigFileName  = "datasource.yml"
+	DashboardsConfigFileName  = "dashboards.yml"
+	DashboardsDir             = "dashboards"
+	prometheusScrapeInterval  = "15s"
+	prometheusDatasourceUID   = "prometheus"
+	grafanaDashboardsHostPath = "/var/lib/grafana/dashboards"
+)
+
+// DashboardUIDs are the uids of the dashboards preloaded on Grafana
+var DashboardUIDs = []string{"avalanchego", "subnet-evm"}
+
+type staticConfig struct {
+	Targets []string          `yaml:"targets"`
+	Labels  map[string]string `yaml:"labels"`
+}
+
+type scrapeConfig struct {
+	JobName       string         `yaml:"job_name"`
+	StaticConfigs []staticConfig `yaml:"static_configs"`
+}
+
+type prometheusConfig struct {
+	Global struct {
+		ScrapeInterval string `yaml:"scrape_interval"`
+	} `yaml:"global"`
+	ScrapeConfigs []scrapeConfig `yaml:"scrape_configs"`
+}
+
+// GeneratePrometheusConfig returns a Prometheus config scraping the node_exporter of each one of
+// [targets], which maps host IDs to IPs. avalanchego metrics are exported through node_exporter,
+// and series are labeled with the host ID as instance
+func GeneratePrometheusConfig(targets map[string]string) ([]byte, error) {
+	hostIDs := []string{}
+	for hostID := range targets {
+		hostIDs = append(hostIDs, hostID)
+	}
+	sort.Strings(hostIDs)
+	config := prometheusConfig{}
+	config.Global.ScrapeInterval = prometheusScrapeInterval
+	scrape := scrapeConfig{JobName: "avalanchego", StaticConfigs: []staticConfig{}}
+	for _, hostID := range hostIDs {
+		scrape.StaticConfigs = append(scrape.StaticConfigs, staticConfig{
+			Targets: []string{fmt.Sprintf("%s:%d", targets[hostID], constants.NodeExporterPort)},
+			Labels:  map[string]string{"instance": hostID},
+		})
+	}
+	config.ScrapeConfigs = []scrapeConfig{scrape}
+	return yaml.Marshal(config)
+}
+
+// generateDatasourceConfig returns the Grafana provisioning config of the local Prometheus datasource
+func generateDatasourceConfig() ([]byte, error) {
+	return yaml.Marshal(map[string]interface{}{
+		"apiVersion": 1,
+		"datasources": []map[string]interface{}{
+			{
+				"name":      "Prometheus",
+				"type":      "prometheus",
+				"uid":       prometheusDatasourceUID,
+				"access":    "proxy",
+				"url":       fmt.Sprintf("http://localhost:%d", constants.PrometheusPort),
+				"isDefault": true,
+			},
+		},
+	})
+}
+
+// generateDashboardsConfig returns the Grafana provisioning config loading the dashboards from
+// their dir on the monitoring host
+func generateDashboardsConfig() ([]byte, error) {
+	return yaml.Marshal(map[string]interface{}{
+		"apiVersion": 1,
+		"providers": []map[string]interface{}{
+			{
+				"name":    "avalanche",
+				"type":    "file",
+				"options": map[string]string{"path": grafanaDashboardsHostPath},
+			},
+		},
+	})
+}
+
+// WriteMonitoringFiles writes into [dir] the Prometheus config to scrape [targets], and the Grafana
+// datasource, dashboards provider and dashboards, to be uploaded to the monitoring host. Returns the
+// paths of the written files, relative to [dir]
+func WriteMonitoringFiles(dir string, targets map[string]string) ([]string, error) {
+	if err := os.MkdirAll(filepath.Join(dir, DashboardsDir), constants.DefaultPerms755); err != nil {
+		return nil, err
+	}
+	prometheusConfig, err := GeneratePrometheusConfig(targets)
+	if err != nil {
+		return nil, err
+	}
+	datasourceConfig, err := generateDatasourceConfig()
+	if err != nil {
+		return nil, err
+	}
+	dashboardsConfig, err := generateDashboardsConfig()
+	if err != nil {
+		return nil, err
+	}
+	files := map[string][]byte{
+		PrometheusConfigFileName: prometheusConfig,
+		DatasourceConfigFileName: datasourceConfig,
+		DashboardsConfigFileName: dashboardsConfig,
+	}
+	dashboardFiles, err := dashboards.ReadDir(DashboardsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, dashboardFile := range dashboardFiles {
+		dashboard, err := dashboards.ReadFile(path.Join(DashboardsDir, dashboardFile.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Join(DashboardsDir, dashboardFile.Name())] = dashboard
+	}
+	fileNames := []string{}
+	for fileName, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, fileName), content, constants.WriteReadReadPerms); err != nil {
+			return nil, err
+		}
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	return fileNames, nil
+}
@@ -0,0 +1,44 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package monitoring
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGeneratePrometheusConfig(t *testing.T) {
+	configBytes, err := GeneratePrometheusConfig(map[string]string{
+		"aws_node_i-2": "10.0.0.2",
+		"aws_node_i-1": "10.0.0.1",
+	})
+	require.NoError(t, err)
+	config := prometheusConfig{}
+	require.NoError(t, yaml.Unmarshal(configBytes, &config))
+	require.Len(t, config.ScrapeConfigs, 1)
+	require.Equal(t, []staticConfig{
+		{Targets: []string{"10.0.0.1:9100"}, Labels: map[string]string{"instance": "aws_node_i-1"}},
+		{Targets: []string{"10.0.0.2:9100"}, Labels: map[string]string{"instance": "aws_node_i-2"}},
+	}, config.ScrapeConfigs[0].StaticConfigs)
+}
+
+func TestWriteMonitoringFiles(t *testing.T) {
+	dir := t.TempDir()
+	fileNames, err := WriteMonitoringFiles(dir, map[string]string{"aws_node_i-1": "10.0.0.1"})
+	require.NoError(t, err)
+	require.Contains(t, fileNames, PrometheusConfigFileName)
+	for _, uid := range DashboardUIDs {
+		fileName := filepath.Join(DashboardsDir, uid+".json")
+		require.Contains(t, fileNames, fileName)
+		dashboardBytes, err := os.ReadFile(filepath.Join(dir, fileName))
+		require.NoError(t, err)
+		dashboard := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(dashboardBytes, &dashboard))
+		require.Equal(t, uid, dashboard["uid"])
+	}
+}
@@ -0,0 +1,351 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package primarygenesis
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanchego/config"
+	avagogenesis "github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	avagoconstants "github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/coreth/core"
+	coreth_params "github.com/ava-labs/coreth/params"
+)
+
+// difference between unlock schedule locktime and startime in original genesis
+const (
+	genesisLocktimeStartimeDelta      = 2836800
+	hexa0Str                          = "0x0"
+	defaultLocalCChainFundedAddress   = "8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"
+	defaultLocalCChainFundedBalance   = "0x295BE96E64066972000000"
+	allocationCommonEthAddress        = "0xb3d82b1367d362de99ab59a658165aff520cbd4d"
+	defaultInitialStakeDuration       = 31536000
+	defaultInitialStakeDurationOffset = 5400
+	defaultDelegationFee              = 1000000
+)
+
+// Spec customizes the generated primary network genesis, and the staking parameters
+// nodes use with it. Zero values keep the defaults
+type Spec struct {
+	// initial stake duration of the genesis validators, in seconds
+	InitialStakeDuration uint64 `json:"initialStakeDuration"`
+	// difference between the stake end time of consecutive genesis validators, in seconds
+	InitialStakeDurationOffset uint64 `json:"initialStakeDurationOffset"`
+	// staking parameters for validators added after genesis, amounts in nAVAX and
+	// durations in seconds
+	MinValidatorStake uint64 `json:"minValidatorStake"`
+	MinDelegatorStake uint64 `json:"minDelegatorStake"`
+	MinStakeDuration  uint64 `json:"minStakeDuration"`
+	MaxStakeDuration  uint64 `json:"maxStakeDuration"`
+	// additional funded addresses on X-Chain and P-Chain
+	Allocations []Allocation `json:"allocations"`
+	// additional funded addresses on C-Chain, as hex address to hex balance in wei
+	CChainAllocations map[string]string `json:"cChainAllocations"`
+	// full C-Chain genesis to use instead of the generated one, can't be set together
+	// with CChainAllocations
+	CChainGenesis json.RawMessage `json:"cChainGenesis"`
+	// network ID of the genesis, for networks that allow to choose it
+	NetworkID uint32 `json:"networkID"`
+	// address funded with the default allocation instead of the ewoq one, in which case
+	// the ewoq C-Chain address is not funded either
+	FundedAddr string `json:"fundedAddr"`
+	// name of a stored key to fund instead of the ewoq one, resolved by the caller into
+	// FundedAddr and a C-Chain allocation
+	FundedKey string `json:"fundedKey"`
+	// address receiving the rewards of the genesis validators, the funded one by default
+	RewardAddr string `json:"rewardAddr"`
+	// delegation fee of the genesis validators, in units of 0.0001%. Not set keeps the
+	// default one, so that 0 can be given
+	DelegationFee *uint32 `json:"delegationFee"`
+	// message included in the genesis
+	Message string `json:"message"`
+}
+
+// Allocation funds [AVAXAddr] with [XAmount] nAVAX on X-Chain and [PAmount] nAVAX on P-Chain,
+// plus the P-Chain amounts locked until the times given in [UnlockSchedule]
+type Allocation struct {
+	AVAXAddr       string         `json:"avaxAddr"`
+	XAmount        uint64         `json:"xAmount"`
+	PAmount        uint64         `json:"pAmount"`
+	UnlockSchedule []LockedAmount `json:"unlockSchedule"`
+}
+
+// LockedAmount is an amount of nAVAX locked until unix time [Locktime]
+type LockedAmount struct {
+	Amount   uint64 `json:"amount"`
+	Locktime uint64 `json:"locktime"`
+}
+
+// LoadSpec reads a genesis spec from [specPath], failing on unknown fields
+func LoadSpec(specPath string) (*Spec, error) {
+	specBytes, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, err
+	}
+	// misspelled fields would be silently ignored, keeping the defaults
+	decoder := json.NewDecoder(bytes.NewReader(specBytes))
+	decoder.DisallowUnknownFields()
+	spec := Spec{}
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("failed to parse genesis spec %s: %w", specPath, err)
+	}
+	if err := spec.Check(); err != nil {
+		return nil, fmt.Errorf("invalid genesis spec %s: %w", specPath, err)
+	}
+	return &spec, nil
+}
+
+// Check checks the consistency of the spec fields
+func (s *Spec) Check() error {
+	if len(s.CChainGenesis) > 0 && len(s.CChainAllocations) > 0 {
+		return errors.New("C-Chain allocations can't be given together with a full C-Chain genesis")
+	}
+	if s.FundedAddr != "" && s.FundedKey != "" {
+		return errors.New("funded address and funded key can't be given together")
+	}
+	if s.DelegationFee != nil && *s.DelegationFee > defaultDelegationFee {
+		return fmt.Errorf("delegation fee must be at most %d", defaultDelegationFee)
+	}
+	switch s.NetworkID {
+	case avagoconstants.MainnetID, avagoconstants.FujiID:
+		return fmt.Errorf("network ID %d is reserved for %s", s.NetworkID, avagoconstants.NetworkName(s.NetworkID))
+	}
+	if s.FundedAddr != "" {
+		if _, err := address.ParseToID(s.FundedAddr); err != nil {
+			return fmt.Errorf("invalid funded address %s: %w", s.FundedAddr, err)
+		}
+	}
+	if s.RewardAddr != "" {
+		if _, err := address.ParseToID(s.RewardAddr); err != nil {
+			return fmt.Errorf("invalid reward address %s: %w", s.RewardAddr, err)
+		}
+	}
+	for _, alloc := range s.Allocations {
+		if alloc.AVAXAddr == "" {
+			return errors.New("allocations must have an address")
+		}
+		if _, err := address.ParseToID(alloc.AVAXAddr); err != nil {
+			return fmt.Errorf("invalid allocation address %s: %w", alloc.AVAXAddr, err)
+		}
+	}
+	return nil
+}
+
+// IsFullGenesis returns true if [genesisBytes] contain a full primary network
+// genesis, instead of a spec to generate one
+func IsFullGenesis(genesisBytes []byte) bool {
+	genesisMap := map[string]interface{}{}
+	if err := json.Unmarshal(genesisBytes, &genesisMap); err != nil {
+		return false
+	}
+	_, hasStakers := genesisMap["initialStakers"]
+	_, hasCChainGenesis := genesisMap["cChainGenesis"]
+	return hasStakers && hasCChainGenesis
+}
+
+// NodeFlags returns the avalanchego flags needed by nodes to use the spec staking params
+func (s *Spec) NodeFlags() map[string]interface{} {
+	flags := map[string]interface{}{}
+	if s.MinValidatorStake != 0 {
+		flags[config.MinValidatorStakeKey] = s.MinValidatorStake
+	}
+	if s.MinDelegatorStake != 0 {
+		flags[config.MinDelegatorStakeKey] = s.MinDelegatorStake
+	}
+	if s.MinStakeDuration != 0 {
+		flags[config.MinStakeDurationKey] = (time.Duration(s.MinStakeDuration) * time.Second).String()
+	}
+	if s.MaxStakeDuration != 0 {
+		flags[config.MaxStakeDurationKey] = (time.Duration(s.MaxStakeDuration) * time.Second).String()
+	}
+	return flags
+}
+
+func (s *Spec) stakingConfig(networkID uint32) avagogenesis.StakingConfig {
+	stakingConfig := avagogenesis.GetStakingConfig(networkID)
+	if s.MinValidatorStake != 0 {
+		stakingConfig.MinValidatorStake = s.MinValidatorStake
+	}
+	if s.MinDelegatorStake != 0 {
+		stakingConfig.MinDelegatorStake = s.MinDelegatorStake
+	}
+	if s.MinStakeDuration != 0 {
+		stakingConfig.MinStakeDuration = time.Duration(s.MinStakeDuration) * time.Second
+	}
+	if s.MaxStakeDuration != 0 {
+		stakingConfig.MaxStakeDuration = time.Duration(s.MaxStakeDuration) * time.Second
+	}
+	return stakingConfig
+}
+
+// SetFundedKey sets the key at [keyPath] as the one funded by the genesis of [networkID], on
+// X-Chain, P-Chain and, unless a full C-Chain genesis is given, on C-Chain
+func (s *Spec) SetFundedKey(networkID uint32, keyPath string) error {
+	k, err := key.LoadSoft(networkID, keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to load funded key %s: %w", s.FundedKey, err)
+	}
+	s.FundedAddr = k.X()[0]
+	if len(s.CChainGenesis) == 0 {
+		if s.CChainAllocations == nil {
+			s.CChainAllocations = map[string]string{}
+		}
+		s.CChainAllocations[k.C()] = defaultLocalCChainFundedBalance
+	}
+	return nil
+}
+
+func generateCChainGenesis(spec *Spec) ([]byte, error) {
+	if len(spec.CChainGenesis) > 0 {
+		return spec.CChainGenesis, nil
+	}
+	alloc := map[string]interface{}{}
+	if spec.FundedAddr == "" {
+		alloc[defaultLocalCChainFundedAddress] = map[string]interface{}{
+			"balance": defaultLocalCChainFundedBalance,
+		}
+	}
+	for addr, balance := range spec.CChainAllocations {
+		alloc[addr] = map[string]interface{}{
+			"balance": balance,
+		}
+	}
+	cChainGenesisMap := map[string]interface{}{}
+	cChainGenesisMap["config"] = coreth_params.AvalancheLocalChainConfig
+	cChainGenesisMap["nonce"] = hexa0Str
+	cChainGenesisMap["timestamp"] = hexa0Str
+	cChainGenesisMap["extraData"] = "0x00"
+	cChainGenesisMap["gasLimit"] = "0x5f5e100"
+	cChainGenesisMap["difficulty"] = hexa0Str
+	cChainGenesisMap["mixHash"] = "0x0000000000000000000000000000000000000000000000000000000000000000"
+	cChainGenesisMap["coinbase"] = "0x0000000000000000000000000000000000000000"
+	cChainGenesisMap["alloc"] = alloc
+	cChainGenesisMap["number"] = hexa0Str
+	cChainGenesisMap["gasUsed"] = hexa0Str
+	cChainGenesisMap["parentHash"] = "0x0000000000000000000000000000000000000000000000000000000000000000"
+	return json.Marshal(cChainGenesisMap)
+}
+
+// Generate creates a primary network genesis where [nodeIDs] are the initial stakers,
+// [walletAddr] is funded and receives the staking rewards, and [stakingAddr] holds the
+// initial staked funds. The funded and reward addresses of [spec] take precedence over
+// [walletAddr]
+func Generate(networkID uint32, spec *Spec, walletAddr string, stakingAddr string, nodeIDs []string) ([]byte, error) {
+	genesisMap := map[string]interface{}{}
+	if spec.FundedAddr != "" {
+		walletAddr = spec.FundedAddr
+	}
+	rewardAddr := walletAddr
+	if spec.RewardAddr != "" {
+		rewardAddr = spec.RewardAddr
+	}
+	delegationFee := uint32(defaultDelegationFee)
+	if spec.DelegationFee != nil {
+		delegationFee = *spec.DelegationFee
+	}
+
+	// cchain
+	cChainGenesisBytes, err := generateCChainGenesis(spec)
+	if err != nil {
+		return nil, err
+	}
+	genesisMap["cChainGenesis"] = string(cChainGenesisBytes)
+
+	// pchain genesis
+	genesisMap["networkID"] = networkID
+	startTime := time.Now().Unix()
+	genesisMap["startTime"] = startTime
+	initialStakers := []map[string]interface{}{}
+	for _, nodeID := range nodeIDs {
+		initialStaker := map[string]interface{}{
+			"nodeID":        nodeID,
+			"rewardAddress": rewardAddr,
+			"delegationFee": delegationFee,
+		}
+		initialStakers = append(initialStakers, initialStaker)
+	}
+	genesisMap["initialStakeDuration"] = defaultInitialStakeDuration
+	if spec.InitialStakeDuration != 0 {
+		genesisMap["initialStakeDuration"] = spec.InitialStakeDuration
+	}
+	genesisMap["initialStakeDurationOffset"] = defaultInitialStakeDurationOffset
+	if spec.InitialStakeDurationOffset != 0 {
+		genesisMap["initialStakeDurationOffset"] = spec.InitialStakeDurationOffset
+	}
+	genesisMap["initialStakers"] = initialStakers
+	lockTime := startTime + genesisLocktimeStartimeDelta
+	allocations := []interface{}{}
+	alloc := map[string]interface{}{
+		"avaxAddr":      walletAddr,
+		"ethAddr":       allocationCommonEthAddress,
+		"initialAmount": 300000000000000000,
+		"unlockSchedule": []interface{}{
+			map[string]interface{}{"amount": 20000000000000000},
+			map[string]interface{}{"amount": 10000000000000000, "locktime": lockTime},
+		},
+	}
+	allocations = append(allocations, alloc)
+	alloc = map[string]interface{}{
+		"avaxAddr":      stakingAddr,
+		"ethAddr":       allocationCommonEthAddress,
+		"initialAmount": 0,
+		"unlockSchedule": []interface{}{
+			map[string]interface{}{"amount": 10000000000000000, "locktime": lockTime},
+		},
+	}
+	allocations = append(allocations, alloc)
+	for _, specAlloc := range spec.Allocations {
+		unlockSchedule := []interface{}{}
+		if specAlloc.PAmount != 0 {
+			unlockSchedule = append(unlockSchedule, map[string]interface{}{"amount": specAlloc.PAmount})
+		}
+		for _, lockedAmount := range specAlloc.UnlockSchedule {
+			unlockSchedule = append(unlockSchedule, map[string]interface{}{"amount": lockedAmount.Amount, "locktime": lockedAmount.Locktime})
+		}
+		allocations = append(allocations, map[string]interface{}{
+			"avaxAddr":       specAlloc.AVAXAddr,
+			"ethAddr":        allocationCommonEthAddress,
+			"initialAmount":  specAlloc.XAmount,
+			"unlockSchedule": unlockSchedule,
+		})
+	}
+	genesisMap["allocations"] = allocations
+	genesisMap["initialStakedFunds"] = []interface{}{
+		stakingAddr,
+	}
+	genesisMap["message"] = spec.Message
+
+	return json.MarshalIndent(genesisMap, "", " ")
+}
+
+// Validate checks that [genesisBytes] is a valid primary network genesis for
+// [networkID], given the staking params of [spec], and returns its initial stakers
+func Validate(networkID uint32, genesisBytes []byte, spec *Spec) ([]ids.NodeID, error) {
+	stakingConfig := spec.stakingConfig(networkID)
+	genesisContent := base64.StdEncoding.EncodeToString(genesisBytes)
+	if _, _, err := avagogenesis.FromFlag(networkID, genesisContent, &stakingConfig); err != nil {
+		return nil, err
+	}
+	unparsedConfig := avagogenesis.UnparsedConfig{}
+	if err := json.Unmarshal(genesisBytes, &unparsedConfig); err != nil {
+		return nil, err
+	}
+	var cChainGenesis core.Genesis
+	if err := json.Unmarshal([]byte(unparsedConfig.CChainGenesis), &cChainGenesis); err != nil {
+		return nil, fmt.Errorf("invalid C-Chain genesis: %w", err)
+	}
+	nodeIDs := make([]ids.NodeID, len(unparsedConfig.InitialStakers))
+	for i, staker := range unparsedConfig.InitialStakers {
+		nodeIDs[i] = staker.NodeID
+	}
+	return nodeIDs, nil
+}
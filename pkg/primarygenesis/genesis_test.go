@@ -0,0 +1,145 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package primarygenesis
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/key"
+	"github.com/ava-labs/avalanchego/config"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testWalletAddr  = "X-local18jma8ppw3nhx5r4ap8clazz0dps7rv5u00z96u"
+	testStakingAddr = "X-local1g65uqn6t77p656w64023nh8nd9updzmxyymev2"
+)
+
+func TestGenerate(t *testing.T) {
+	require := require.New(t)
+	nodeIDs := []ids.NodeID{ids.GenerateTestNodeID(), ids.GenerateTestNodeID()}
+	nodeIDStrs := []string{nodeIDs[0].String(), nodeIDs[1].String()}
+	spec := &Spec{
+		InitialStakeDuration: 2 * 31536000,
+		MaxStakeDuration:     2 * 31536000,
+		MinValidatorStake:    1000,
+		Allocations: []Allocation{
+			{AVAXAddr: testStakingAddr, XAmount: 5, PAmount: 7},
+		},
+		CChainAllocations: map[string]string{
+			"0x0000000000000000000000000000000000000001": "0x10",
+		},
+	}
+	genesisBytes, err := Generate(constants.LocalNetworkID, spec, testWalletAddr, testStakingAddr, nodeIDStrs)
+	require.NoError(err)
+	require.True(IsFullGenesis(genesisBytes))
+
+	stakers, err := Validate(constants.LocalNetworkID, genesisBytes, spec)
+	require.NoError(err)
+	require.Equal(nodeIDs, stakers)
+
+	// initial stake duration longer than the default max stake duration
+	_, err = Validate(constants.LocalNetworkID, genesisBytes, &Spec{})
+	require.Error(err)
+	// network ID mismatch
+	_, err = Validate(constants.LocalNetworkID+1, genesisBytes, spec)
+	require.Error(err)
+
+	genesisMap := map[string]interface{}{}
+	require.NoError(json.Unmarshal(genesisBytes, &genesisMap))
+	require.Len(genesisMap["allocations"], 3)
+	require.Contains(genesisMap["cChainGenesis"], "0x0000000000000000000000000000000000000001")
+}
+
+func TestLoadSpec(t *testing.T) {
+	require := require.New(t)
+	specPath := filepath.Join(t.TempDir(), "spec.json")
+	specBytes := []byte(`{"minStakeDuration": 3600, "minDelegatorStake": 10}`)
+	require.NoError(os.WriteFile(specPath, specBytes, constants.WriteReadReadPerms))
+	require.False(IsFullGenesis(specBytes))
+
+	spec, err := LoadSpec(specPath)
+	require.NoError(err)
+	require.Equal(map[string]interface{}{
+		config.MinStakeDurationKey:  "1h0m0s",
+		config.MinDelegatorStakeKey: uint64(10),
+	}, spec.NodeFlags())
+
+	require.NoError(os.WriteFile(specPath, []byte(`{"minStakingDuration": 3600}`), constants.WriteReadReadPerms))
+	_, err = LoadSpec(specPath)
+	require.ErrorContains(err, "minStakingDuration")
+
+	require.NoError(os.WriteFile(specPath, []byte("not json"), constants.WriteReadReadPerms))
+	_, err = LoadSpec(specPath)
+	require.Error(err)
+}
+
+func TestGenerateFundedKey(t *testing.T) {
+	require := require.New(t)
+	k, err := key.NewSoft(constants.LocalNetworkID)
+	require.NoError(err)
+	keyPath := filepath.Join(t.TempDir(), "funded.pk")
+	require.NoError(k.Save(keyPath))
+
+	delegationFee := uint32(20000)
+	spec := &Spec{
+		FundedKey:     "funded",
+		DelegationFee: &delegationFee,
+		Message:       "devnet",
+		Allocations: []Allocation{
+			{AVAXAddr: testStakingAddr, UnlockSchedule: []LockedAmount{{Amount: 3, Locktime: 1000}}},
+		},
+	}
+	require.NoError(spec.Check())
+	require.NoError(spec.SetFundedKey(constants.LocalNetworkID, keyPath))
+	require.Equal(k.X()[0], spec.FundedAddr)
+
+	nodeIDs := []string{ids.GenerateTestNodeID().String()}
+	genesisBytes, err := Generate(constants.LocalNetworkID, spec, testWalletAddr, testStakingAddr, nodeIDs)
+	require.NoError(err)
+	_, err = Validate(constants.LocalNetworkID, genesisBytes, spec)
+	require.NoError(err)
+
+	genesisMap := map[string]interface{}{}
+	require.NoError(json.Unmarshal(genesisBytes, &genesisMap))
+	require.Equal("devnet", genesisMap["message"])
+	staker := genesisMap["initialStakers"].([]interface{})[0].(map[string]interface{})
+	require.Equal(k.X()[0], staker["rewardAddress"])
+	require.Equal(float64(20000), staker["delegationFee"])
+	// the ewoq C-Chain address is not funded, the funded key one is
+	require.NotContains(genesisMap["cChainGenesis"], defaultLocalCChainFundedAddress)
+	require.Contains(genesisMap["cChainGenesis"], k.C())
+}
+
+func TestSpecCheck(t *testing.T) {
+	require := require.New(t)
+	require.NoError((&Spec{}).Check())
+	require.Error((&Spec{CChainGenesis: json.RawMessage(`{}`), CChainAllocations: map[string]string{"0x1": "0x1"}}).Check())
+	require.Error((&Spec{FundedAddr: testWalletAddr, FundedKey: "funded"}).Check())
+	delegationFee := uint32(1000001)
+	require.Error((&Spec{DelegationFee: &delegationFee}).Check())
+	require.Error((&Spec{FundedAddr: "local18jma8ppw3nhx5r4ap8clazz0dps7rv5u00z96u"}).Check())
+	require.Error((&Spec{RewardAddr: "X-local1invalid"}).Check())
+	require.Error((&Spec{Allocations: []Allocation{{AVAXAddr: "0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"}}}).Check())
+	require.Error((&Spec{NetworkID: 1}).Check())
+	require.Error((&Spec{Allocations: []Allocation{{XAmount: 1}}}).Check())
+	require.NoError((&Spec{FundedAddr: testWalletAddr, RewardAddr: testWalletAddr, Allocations: []Allocation{{AVAXAddr: testStakingAddr}}}).Check())
+}
+
+func TestGenerateZeroDelegationFee(t *testing.T) {
+	require := require.New(t)
+	delegationFee := uint32(0)
+	spec := &Spec{DelegationFee: &delegationFee}
+	require.NoError(spec.Check())
+	genesisBytes, err := Generate(constants.LocalNetworkID, spec, testWalletAddr, testStakingAddr, []string{ids.GenerateTestNodeID().String()})
+	require.NoError(err)
+	genesisMap := map[string]interface{}{}
+	require.NoError(json.Unmarshal(genesisBytes, &genesisMap))
+	staker := genesisMap["initialStakers"].([]interface{})[0].(map[string]interface{})
+	require.Equal(float64(0), staker["delegationFee"])
+}
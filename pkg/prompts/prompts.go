@@ -79,6 +79,7 @@ type Prompter interface {
 	CaptureGitURL(promptStr string) (*url.URL, error)
 	CaptureStringAllowEmpty(promptStr string) (string, error)
 	CaptureEmail(promptStr string) (string, error)
+	CapturePassword(promptStr string) (string, error)
 	CaptureIndex(promptStr string, options []any) (int, error)
 	CaptureVersion(promptStr string) (string, error)
 	CaptureFujiDuration(promptStr string) (time.Duration, error)
@@ -507,6 +508,21 @@ func (*realPrompter) CaptureEmail(promptStr string) (string, error) {
 	return str, nil
 }
 
+func (*realPrompter) CapturePassword(promptStr string) (string, error) {
+	prompt := promptui.Prompt{
+		Label:    promptStr,
+		Mask:     '*',
+		Validate: validateNonEmpty,
+	}
+
+	str, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+
+	return str, nil
+}
+
 func (*realPrompter) CaptureStringAllowEmpty(promptStr string) (string, error) {
 	prompt := promptui.Prompt{
 		Label: promptStr,
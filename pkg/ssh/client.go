@@ -0,0 +1,197 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ssh
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/pkg/sftp"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+const (
+	dialTimeout = 30 * time.Second
+	// number of output lines kept on command errors
+	errorOutputLines = 20
+)
+
+// Client runs commands and transfers files on a remote host
+type Client struct {
+	Host   models.Host
+	port   int
+	client *gossh.Client
+}
+
+// Connect opens an ssh connection to [host], authenticating with its private key
+func Connect(host models.Host) (*Client, error) {
+	return connect(host, constants.SSHTCPPort)
+}
+
+func connect(host models.Host, port int) (*Client, error) {
+	keyBytes, err := os.ReadFile(host.SSHPrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading ssh private key of host %s: %w", host.NodeID, err)
+	}
+	signer, err := gossh.ParsePrivateKey(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing ssh private key of host %s: %w", host.NodeID, err)
+	}
+	config := &gossh.ClientConfig{
+		User: host.SSHUser,
+		Auth: []gossh.AuthMethod{gossh.PublicKeys(signer)},
+		// same as StrictHostKeyChecking=no used for ssh commands: cloud servers are
+		// created by the CLI, so their host keys are not known beforehand
+		HostKeyCallback: gossh.InsecureIgnoreHostKey(), //nolint:gosec
+		Timeout:         dialTimeout,
+	}
+	client, err := gossh.Dial("tcp", net.JoinHostPort(host.IP, strconv.Itoa(port)), config)
+	if err != nil {
+		return nil, fmt.Errorf("failed connecting to host %s: %w", host.NodeID, err)
+	}
+	return &Client{
+		Host:   host,
+		port:   port,
+		client: client,
+	}, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// Run executes [cmd] on the remote host, feeding it [stdin] if given, and returns
+// its combined output
+func (c *Client) Run(cmd string, stdin []byte) ([]byte, error) {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+	if stdin != nil {
+		session.Stdin = bytes.NewReader(stdin)
+	}
+	var output bytes.Buffer
+	session.Stdout = &output
+	session.Stderr = &output
+	if err := session.Run(cmd); err != nil {
+		return output.Bytes(), fmt.Errorf("command failed on host %s: %w%s", c.Host.NodeID, err, formatOutputTail(output.String()))
+	}
+	return output.Bytes(), nil
+}
+
+// Stream executes [cmd] on the remote host, copying its stdout to [stdout] as it is produced
+func (c *Client) Stream(cmd string, stdout io.Writer) error {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	var stderr bytes.Buffer
+	session.Stdout = stdout
+	session.Stderr = &stderr
+	if err := session.Run(cmd); err != nil {
+		return fmt.Errorf("command failed on host %s: %w%s", c.Host.NodeID, err, formatOutputTail(stderr.String()))
+	}
+	return nil
+}
+
+// Output executes [cmd] on the remote host and returns its stdout only
+func (c *Client) Output(cmd string) ([]byte, error) {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+	if err := session.Run(cmd); err != nil {
+		return nil, fmt.Errorf("command failed on host %s: %w%s", c.Host.NodeID, err, formatOutputTail(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// Upload copies [localPath] into [remotePath] over sftp, creating the remote dir if needed.
+// [remotePath] is taken as a dir if it ends with a slash
+func (c *Client) Upload(localPath string, remotePath string) error {
+	if strings.HasSuffix(remotePath, "/") {
+		remotePath += filepath.Base(localPath)
+	}
+	localFile, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer localFile.Close()
+	sftpClient, err := sftp.NewClient(c.client)
+	if err != nil {
+		return fmt.Errorf("failed opening sftp session on host %s: %w", c.Host.NodeID, err)
+	}
+	defer sftpClient.Close()
+	// remote paths are always slash separated
+	if err := sftpClient.MkdirAll(path.Dir(remotePath)); err != nil {
+		return fmt.Errorf("failed uploading %s: %w", localPath, err)
+	}
+	remoteFile, err := sftpClient.Create(remotePath)
+	if err != nil {
+		return fmt.Errorf("failed uploading %s: %w", localPath, err)
+	}
+	defer remoteFile.Close()
+	if _, err := remoteFile.ReadFrom(localFile); err != nil {
+		return fmt.Errorf("failed uploading %s: %w", localPath, err)
+	}
+	return nil
+}
+
+// Download copies [remotePath] into [localPath] over sftp, creating the local dir if needed
+func (c *Client) Download(remotePath string, localPath string) error {
+	sftpClient, err := sftp.NewClient(c.client)
+	if err != nil {
+		return fmt.Errorf("failed opening sftp session on host %s: %w", c.Host.NodeID, err)
+	}
+	defer sftpClient.Close()
+	remoteFile, err := sftpClient.Open(remotePath)
+	if err != nil {
+		return fmt.Errorf("failed downloading %s: %w", remotePath, err)
+	}
+	defer remoteFile.Close()
+	if err := os.MkdirAll(filepath.Dir(localPath), constants.DefaultPerms755); err != nil {
+		return err
+	}
+	localFile, err := os.OpenFile(localPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, constants.WriteReadUserOnlyPerms)
+	if err != nil {
+		return err
+	}
+	defer localFile.Close()
+	if _, err := remoteFile.WriteTo(localFile); err != nil {
+		return fmt.Errorf("failed downloading %s: %w", remotePath, err)
+	}
+	return localFile.Close()
+}
+
+// shellQuote quotes [s] to be used as a single argument of a remote shell command
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+func formatOutputTail(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return ""
+	}
+	if len(lines) > errorOutputLines {
+		lines = lines[len(lines)-errorOutputLines:]
+	}
+	return "\n  " + strings.Join(lines, "\n  ")
+}
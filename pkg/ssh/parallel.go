@@ -0,0 +1,75 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ssh
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+)
+
+// HostErrors maps the node IDs of the hosts an operation failed on, to their errors
+type HostErrors map[string]error
+
+func (e HostErrors) Error() string {
+	hostIDs := make([]string, 0, len(e))
+	for hostID := range e {
+		hostIDs = append(hostIDs, hostID)
+	}
+	sort.Strings(hostIDs)
+	msgs := make([]string, 0, len(hostIDs))
+	for _, hostID := range hostIDs {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", hostID, e[hostID]))
+	}
+	return fmt.Sprintf("operation failed on host(s) %s:\n%s", strings.Join(hostIDs, ", "), strings.Join(msgs, "\n"))
+}
+
+// RunOnHosts connects to all [hosts] in parallel, and runs [op] on each of them. Returns
+// the results of the hosts [op] succeeded on, keyed by host node ID, and a HostErrors
+// error with the failures, if any
+func RunOnHosts[T any](hosts []models.Host, op func(*Client) (T, error)) (map[string]T, error) {
+	return runOnHosts(hosts, Connect, op)
+}
+
+func runOnHosts[T any](
+	hosts []models.Host,
+	connect func(models.Host) (*Client, error),
+	op func(*Client) (T, error),
+) (map[string]T, error) {
+	var (
+		lock    sync.Mutex
+		wg      sync.WaitGroup
+		results = map[string]T{}
+		errs    = HostErrors{}
+	)
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(host models.Host) {
+			defer wg.Done()
+			result, err := func() (T, error) {
+				var zero T
+				client, err := connect(host)
+				if err != nil {
+					return zero, err
+				}
+				defer client.Close()
+				return op(client)
+			}()
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				errs[host.NodeID] = err
+				return
+			}
+			results[host.NodeID] = result
+		}(host)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return results, errs
+	}
+	return results, nil
+}
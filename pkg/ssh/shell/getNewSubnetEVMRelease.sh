@@ -0,0 +1,5 @@
+#!/usr/bin/env bash
+set -e
+cd ~
+wget -N "{{ .SubnetEVMReleaseURL }}"
+tar xvf "{{ .SubnetEVMArchive }}"
@@ -0,0 +1,26 @@
+#!/usr/bin/env bash
+set -e
+cd {{ .LogsDir }}
+CUTOFF=""
+NOW=""
+YEAR=""
+{{- if gt .LogSinceSeconds 0 }}
+# avalanchego log lines start with [MM-DD|HH:MM:SS.mmm], in the host local time, without the year:
+# it is taken as the current one, or as the previous one for dates after the current date
+NOW_SECONDS=$(date +%s)
+SINCE=$(( NOW_SECONDS - {{ .LogSinceSeconds }} ))
+CUTOFF=$(date -d "@$SINCE" '+%Y-%m-%d|%H:%M:%S' 2>/dev/null || date -r "$SINCE" '+%Y-%m-%d|%H:%M:%S')
+NOW=$(date -d "@$NOW_SECONDS" '+%m-%d|%H:%M:%S' 2>/dev/null || date -r "$NOW_SECONDS" '+%m-%d|%H:%M:%S')
+YEAR=$(date -d "@$NOW_SECONDS" '+%Y' 2>/dev/null || date -r "$NOW_SECONDS" '+%Y')
+{{- end }}
+tail -n {{ if gt .LogSinceSeconds 0 }}+1{{ else }}{{ .LogLines }}{{ end }}{{ if .LogFollow }} -F{{ end }} {{ .LogFiles }} | awk -v cutoff="$CUTOFF" -v now="$NOW" -v year="$YEAR" -v name="{{ .LogName }}" '
+# logs are in time order, so once a line of a log is within the cutoff, all the following ones are,
+# including the ones logged after the current date when following
+/^==> .* <==$/ { name = $2; sub(/\.log$/, "", name); next }
+/^$/ { next }
+cutoff != "" && !(name in started) && substr($0, 1, 1) == "[" {
+  stamp = substr($0, 2, 14)
+  if ((stamp > now ? year - 1 : year) "-" stamp >= cutoff) { started[name] = 1 }
+}
+cutoff == "" || name in started { print name " | " $0; fflush() }
+'
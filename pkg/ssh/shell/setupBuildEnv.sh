@@ -0,0 +1,27 @@
+#!/usr/bin/env bash
+set -e
+cd ~
+sudo apt update
+# install gcc
+if ! gcc --version; then
+  sudo apt install gcc -y
+  gcc --version
+fi
+# install go
+if ! bash -i -c "go version"; then
+  GOFILE=go{{ .GoVersion }}.linux-$(dpkg --print-architecture).tar.gz
+  sudo rm -rf $GOFILE go
+  wget -nv https://go.dev/dl/$GOFILE
+  tar xfz $GOFILE
+  echo >> ~/.bashrc
+  echo export PATH=\$PATH:~/go/bin >> ~/.bashrc
+  echo export CGO_ENABLED=1 >> ~/.bashrc
+  bash -i -c "go version"
+fi
+# install rust
+if ! bash -i -c "cargo version"; then
+  curl --proto '=https' --tlsv1.2 -sSf https://sh.rustup.rs | sh -s - -y
+  echo >> ~/.bashrc
+  echo export PATH=\$PATH:~/.cargo/bin >> ~/.bashrc
+  bash -i -c "cargo version"
+fi
@@ -0,0 +1,8 @@
+#!/usr/bin/env bash
+set -e
+cd ~
+rm -rf avalanche-cli
+git clone --single-branch -b {{ .CLIBranch }} https://github.com/ava-labs/avalanche-cli
+cd avalanche-cli
+bash -i -c ./scripts/build.sh
+cp bin/avalanche {{ .CLIBinPath }}
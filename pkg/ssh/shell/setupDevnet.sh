@@ -0,0 +1,8 @@
+#!/usr/bin/env bash
+set -e
+sudo systemctl stop avalanchego
+# remove previous avalanchego db, chain data and logs
+rm -rf $HOME/.avalanchego/db/
+rm -rf $HOME/.avalanchego/chainData/
+rm -rf $HOME/.avalanchego/logs/
+sudo systemctl start avalanchego
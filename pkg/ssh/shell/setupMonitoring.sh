@@ -0,0 +1,22 @@
+#!/usr/bin/env bash
+set -e
+cd ~
+# install prometheus and grafana
+sudo apt-get -y update
+sudo DEBIAN_FRONTEND=noninteractive apt-get -y install prometheus apt-transport-https software-properties-common wget gnupg
+sudo mkdir -p /etc/apt/keyrings
+wget -q -O - https://apt.grafana.com/gpg.key | gpg --dearmor | sudo tee /etc/apt/keyrings/grafana.gpg > /dev/null
+echo "deb [signed-by=/etc/apt/keyrings/grafana.gpg] https://apt.grafana.com stable main" | sudo tee /etc/apt/sources.list.d/grafana.list > /dev/null
+sudo apt-get -y update
+sudo DEBIAN_FRONTEND=noninteractive apt-get -y install grafana
+# install configs and dashboards
+sudo cp {{ .MonitoringDir }}/prometheus.yml /etc/prometheus/prometheus.yml
+sudo mkdir -p /etc/grafana/provisioning/datasources /etc/grafana/provisioning/dashboards /var/lib/grafana/dashboards
+sudo cp {{ .MonitoringDir }}/datasource.yml /etc/grafana/provisioning/datasources/avalanche.yml
+sudo cp {{ .MonitoringDir }}/dashboards.yml /etc/grafana/provisioning/dashboards/avalanche.yml
+sudo cp {{ .MonitoringDir }}/dashboards/*.json /var/lib/grafana/dashboards/
+sudo chown -R grafana:grafana /var/lib/grafana/dashboards
+sudo systemctl enable prometheus grafana-server
+sudo systemctl restart prometheus
+sudo systemctl restart grafana-server
+sudo grafana-cli --homepath /usr/share/grafana --config /etc/grafana/grafana.ini admin reset-admin-password '{{ .GrafanaAdminPassword }}'
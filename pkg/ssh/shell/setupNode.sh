@@ -0,0 +1,17 @@
+#!/usr/bin/env bash
+set -e
+cd ~
+# install avalanchego
+wget -nd -m https://raw.githubusercontent.com/ava-labs/avalanche-docs/master/scripts/avalanchego-installer.sh
+chmod 755 avalanchego-installer.sh
+./avalanchego-installer.sh --ip static --rpc private --state-sync on --fuji --version {{ .AvalancheGoVersion }}
+{{- if .IsDevnet }}
+# stop node if devnet
+sudo systemctl stop avalanchego
+{{- end }}
+# install avalanche cli
+wget -nd -m https://raw.githubusercontent.com/ava-labs/avalanche-cli/main/scripts/install.sh
+chmod 755 install.sh
+./install.sh -n
+mkdir -p .avalanche-cli
+echo '{"MetricsEnabled":false}' > .avalanche-cli/config
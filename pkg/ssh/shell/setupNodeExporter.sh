@@ -0,0 +1,58 @@
+#!/usr/bin/env bash
+set -e
+cd ~
+# install node_exporter
+sudo apt-get -y update
+sudo DEBIAN_FRONTEND=noninteractive apt-get -y install prometheus-node-exporter curl python3
+# enable avalanchego metrics API, restarting avalanchego only if its config changed
+mkdir -p ~/.avalanchego/configs
+if python3 - ~/.avalanchego/configs/node.json <<'PYTHON'
+import json, os, sys
+path = sys.argv[1]
+config = {}
+if os.path.exists(path):
+    with open(path) as f:
+        config = json.load(f)
+if config.get("api-metrics-enabled") is True:
+    sys.exit(1)
+config["api-metrics-enabled"] = True
+with open(path, "w") as f:
+    json.dump(config, f, indent=2)
+PYTHON
+then
+  if systemctl is-active --quiet avalanchego; then
+    sudo systemctl restart avalanchego
+  fi
+fi
+# export avalanchego metrics through the node_exporter textfile collector, so the node API stays private
+TEXTFILE_DIR=/var/lib/prometheus/node-exporter
+sudo mkdir -p $TEXTFILE_DIR
+sudo tee /usr/local/bin/avalanchego-metrics.sh > /dev/null <<SCRIPT
+#!/usr/bin/env bash
+curl -sf http://127.0.0.1:{{ .AvalanchegoAPIPort }}/ext/metrics > $TEXTFILE_DIR/avalanchego.prom.tmp && mv $TEXTFILE_DIR/avalanchego.prom.tmp $TEXTFILE_DIR/avalanchego.prom
+SCRIPT
+sudo chmod 755 /usr/local/bin/avalanchego-metrics.sh
+sudo tee /etc/systemd/system/avalanchego-metrics.service > /dev/null <<UNIT
+[Unit]
+Description=Export avalanchego metrics to node_exporter
+
+[Service]
+Type=oneshot
+ExecStart=/usr/local/bin/avalanchego-metrics.sh
+UNIT
+sudo tee /etc/systemd/system/avalanchego-metrics.timer > /dev/null <<UNIT
+[Unit]
+Description=Export avalanchego metrics to node_exporter every 15s
+
+[Timer]
+OnBootSec=15s
+OnUnitActiveSec=15s
+AccuracySec=1s
+
+[Install]
+WantedBy=timers.target
+UNIT
+sudo systemctl daemon-reload
+sudo systemctl enable --now avalanchego-metrics.timer
+sudo systemctl enable prometheus-node-exporter
+sudo systemctl restart prometheus-node-exporter
@@ -0,0 +1,3 @@
+#!/usr/bin/env bash
+set -e
+sudo systemctl start avalanchego
@@ -0,0 +1,7 @@
+#!/usr/bin/env bash
+set -e
+cd ~
+sudo systemctl stop avalanchego
+bash -i -c "{{ .CLIBinPath }} subnet import file {{ .SubnetExportFileName }} --force"
+{{ .CLIBinPath }} subnet join {{ .SubnetName }} {{ .NetworkFlag }} --avalanchego-config $HOME/.avalanchego/configs/node.json --plugin-dir $HOME/.avalanchego/plugins --force-write
+sudo systemctl start avalanchego
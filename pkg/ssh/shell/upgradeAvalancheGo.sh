@@ -0,0 +1,4 @@
+#!/usr/bin/env bash
+set -e
+cd ~
+./avalanchego-installer.sh --version {{ .AvalancheGoVersion }}
@@ -0,0 +1,4 @@
+#!/usr/bin/env bash
+set -e
+cd ~
+cp subnet-evm {{ .SubnetEVMBinaryPath }}
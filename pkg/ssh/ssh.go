@@ -0,0 +1,364 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ssh
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/constants"
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+)
+
+//go:embed shell/*.sh
+var script embed.FS
+
+// scriptInputs are the values available to the shell script templates
+type scriptInputs struct {
+	AvalancheGoVersion   string
+	IsDevnet             bool
+	GoVersion            string
+	CLIBranch            string
+	CLIBinPath           string
+	SubnetName           string
+	SubnetExportFileName string
+	NetworkFlag          string
+	SubnetEVMReleaseURL  string
+	SubnetEVMArchive     string
+	SubnetEVMBinaryPath  string
+	AvalanchegoAPIPort   int
+	MonitoringDir        string
+	GrafanaAdminPassword string
+	LogsDir              string
+	LogFiles             string
+	LogName              string
+	LogLines             int
+	LogSinceSeconds      int
+	LogFollow            bool
+}
+
+// RunScript renders the embedded shell script [scriptName] with [inputs], and runs it on the host
+func (c *Client) RunScript(scriptName string, inputs scriptInputs) error {
+	scriptBytes, err := renderScript(scriptName, inputs)
+	if err != nil {
+		return err
+	}
+	if _, err := c.Run("bash -c "+shellQuote(string(scriptBytes)), nil); err != nil {
+		return fmt.Errorf("%s: %w", scriptName, err)
+	}
+	return nil
+}
+
+func renderScript(scriptName string, inputs scriptInputs) ([]byte, error) {
+	tmpl, err := template.ParseFS(script, filepath.Join("shell", scriptName))
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, inputs); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// RunSSHSetupNode installs avalanchego and avalanche-cli on the host. On devnets, avalanchego
+// is left stopped, as it can only start after the devnet genesis is set up
+func RunSSHSetupNode(c *Client, avalancheGoVersion string, isDevnet bool) error {
+	return c.RunScript("setupNode.sh", scriptInputs{
+		AvalancheGoVersion: avalancheGoVersion,
+		IsDevnet:           isDevnet,
+	})
+}
+
+// RunSSHCopyStakingFiles uploads the staking files found in [nodeInstanceDir] to the host
+func RunSSHCopyStakingFiles(c *Client, nodeInstanceDir string) error {
+	for _, fileName := range []string{constants.StakerCertFileName, constants.StakerKeyFileName, constants.BLSKeyFileName} {
+		if err := c.Upload(filepath.Join(nodeInstanceDir, fileName), constants.CloudNodeStakingPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunSSHSetupDevnet uploads the devnet genesis and node config found in [nodeInstanceDir] to
+// the host, and restarts avalanchego from a clean state
+func RunSSHSetupDevnet(c *Client, nodeInstanceDir string) error {
+	for _, fileName := range []string{constants.GenesisFileName, constants.NodeFileName} {
+		if err := c.Upload(filepath.Join(nodeInstanceDir, fileName), constants.CloudNodeConfigPath); err != nil {
+			return err
+		}
+	}
+	return c.RunScript("setupDevnet.sh", scriptInputs{})
+}
+
+// RunSSHSetupBuildEnv installs gcc, golang and rust on the host
+func RunSSHSetupBuildEnv(c *Client) error {
+	return c.RunScript("setupBuildEnv.sh", scriptInputs{GoVersion: constants.BuildEnvGolangVersion})
+}
+
+// RunSSHSetupCLIFromSource installs avalanche-cli from [cliBranch] on the host
+func RunSSHSetupCLIFromSource(c *Client, cliBranch string) error {
+	return c.RunScript("setupCLIFromSource.sh", scriptInputs{
+		CLIBranch:  cliBranch,
+		CLIBinPath: constants.CloudNodeCLIBinPath,
+	})
+}
+
+// RunSSHExportSubnet uploads the exported subnet at [subnetPath] to the same path on the host
+func RunSSHExportSubnet(c *Client, subnetPath string) error {
+	return c.Upload(subnetPath, subnetPath)
+}
+
+// RunSSHTrackSubnet imports the subnet exported at [importPath] on the host, and makes
+// avalanchego track it
+func RunSSHTrackSubnet(c *Client, network models.Network, subnetName string, importPath string) error {
+	return c.RunScript("trackSubnet.sh", scriptInputs{
+		CLIBinPath:           constants.CloudNodeCLIBinPath,
+		SubnetName:           subnetName,
+		SubnetExportFileName: importPath,
+		NetworkFlag:          getNetworkFlag(network),
+	})
+}
+
+// RunSSHUpdateSubnet reimports the subnet exported at [importPath] on the host, and restarts
+// avalanchego with the updated subnet config of [network]
+func RunSSHUpdateSubnet(c *Client, network models.Network, subnetName string, importPath string) error {
+	return c.RunScript("updateSubnet.sh", scriptInputs{
+		CLIBinPath:           constants.CloudNodeCLIBinPath,
+		SubnetName:           subnetName,
+		SubnetExportFileName: importPath,
+		NetworkFlag:          getNetworkFlag(network),
+	})
+}
+
+// RunSSHUpgradeAvalancheGo installs [avalancheGoVersion] on the host
+func RunSSHUpgradeAvalancheGo(c *Client, avalancheGoVersion string) error {
+	return c.RunScript("upgradeAvalancheGo.sh", scriptInputs{AvalancheGoVersion: avalancheGoVersion})
+}
+
+// RunSSHStartNode starts avalanchego
+func RunSSHStartNode(c *Client) error {
+	return c.RunScript("startNode.sh", scriptInputs{})
+}
+
+// RunSSHStopNode stops avalanchego
+func RunSSHStopNode(c *Client) error {
+	return c.RunScript("stopNode.sh", scriptInputs{})
+}
+
+// RunSSHGetNewSubnetEVMRelease downloads and unpacks a subnet-evm release on the host
+func RunSSHGetNewSubnetEVMRelease(c *Client, subnetEVMReleaseURL string, subnetEVMArchive string) error {
+	return c.RunScript("getNewSubnetEVMRelease.sh", scriptInputs{
+		SubnetEVMReleaseURL: subnetEVMReleaseURL,
+		SubnetEVMArchive:    subnetEVMArchive,
+	})
+}
+
+// RunSSHUpgradeSubnetEVM replaces the VM binary at [subnetEVMBinaryPath] with the subnet-evm
+// release previously downloaded on the host
+func RunSSHUpgradeSubnetEVM(c *Client, subnetEVMBinaryPath string) error {
+	return c.RunScript("upgradeSubnetEVM.sh", scriptInputs{SubnetEVMBinaryPath: subnetEVMBinaryPath})
+}
+
+// RunSSHSetupNodeExporter installs node_exporter on the host, and makes it export the
+// avalanchego metrics, so they can be scraped without opening the avalanchego API
+func RunSSHSetupNodeExporter(c *Client) error {
+	return c.RunScript("setupNodeExporter.sh", scriptInputs{AvalanchegoAPIPort: constants.AvalanchegoAPIPort})
+}
+
+// RunSSHSetupMonitoring uploads the monitoring files [fileNames] found in [monitoringDir] to the
+// host, and installs Prometheus and Grafana with them, setting [grafanaAdminPassword] as the
+// Grafana admin password
+func RunSSHSetupMonitoring(c *Client, monitoringDir string, fileNames []string, grafanaAdminPassword string) error {
+	for _, fileName := range fileNames {
+		remotePath := filepath.ToSlash(filepath.Join(constants.MonitoringDir, fileName))
+		if err := c.Upload(filepath.Join(monitoringDir, fileName), remotePath); err != nil {
+			return err
+		}
+	}
+	return c.RunScript("setupMonitoring.sh", scriptInputs{
+		MonitoringDir:        constants.MonitoringDir,
+		GrafanaAdminPassword: grafanaAdminPassword,
+	})
+}
+
+// RunSSHListLogFiles returns the names of the avalanchego log files on the host, rotated
+// (and possibly compressed) logs included
+func RunSSHListLogFiles(c *Client) ([]string, error) {
+	output, err := c.Output(fmt.Sprintf(`cd %s && ls -1 | grep -E '\.log(\.gz)?$'`, constants.CloudNodeLogsPath))
+	if err != nil {
+		return nil, fmt.Errorf("no avalanchego logs found on host %s: %w", c.Host.NodeID, err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// RenderLogsScript returns a script printing the lines of [logFiles] at [logsDir], each one prefixed
+// with the name of its log. Prints the last [lines] lines of each log, or all lines logged within
+// [since] if it is set. If [follow], keeps printing new lines as they are logged
+func RenderLogsScript(logsDir string, logFiles []string, lines int, since time.Duration, follow bool) ([]byte, error) {
+	if len(logFiles) == 0 {
+		return nil, errors.New("no log files given")
+	}
+	quotedLogFiles := make([]string, 0, len(logFiles))
+	for _, logFile := range logFiles {
+		quotedLogFiles = append(quotedLogFiles, shellQuote(logFile))
+	}
+	return renderScript("logs.sh", scriptInputs{
+		LogsDir:         shellQuote(logsDir),
+		LogFiles:        strings.Join(quotedLogFiles, " "),
+		LogName:         strings.TrimSuffix(logFiles[0], ".log"), // tail prints no file headers for a single file
+		LogLines:        lines,
+		LogSinceSeconds: int(since.Seconds()),
+		LogFollow:       follow,
+	})
+}
+
+// RunSSHStreamLogs prints to [output] the lines of the avalanchego logs [logFiles] of the host,
+// as selected by [lines], [since] and [follow]. See RenderLogsScript
+func RunSSHStreamLogs(c *Client, logFiles []string, lines int, since time.Duration, follow bool, output io.Writer) error {
+	scriptBytes, err := RenderLogsScript(constants.CloudNodeLogsPath, logFiles, lines, since, follow)
+	if err != nil {
+		return err
+	}
+	return c.Stream("bash -c "+shellQuote(string(scriptBytes)), output)
+}
+
+// RunSSHGetNodeConfig returns the avalanchego config file of the host, or an empty config if
+// there is none
+func RunSSHGetNodeConfig(c *Client) ([]byte, error) {
+	nodeConfigPath := constants.CloudNodeConfigPath + constants.NodeFileName
+	return c.Output(fmt.Sprintf("if [ -f %s ]; then cat %s; else echo '{}'; fi", nodeConfigPath, nodeConfigPath))
+}
+
+// RunSSHSetNodeConfig uploads [nodeConfigPath] as the avalanchego config file of the host
+func RunSSHSetNodeConfig(c *Client, nodeConfigPath string) error {
+	return c.Upload(nodeConfigPath, constants.CloudNodeConfigPath+constants.NodeFileName)
+}
+
+// RunSSHGetVMVersions returns the versions of the VMs run by avalanchego, keyed by VM name
+// or ID. The "platform" version is the avalanchego version
+func RunSSHGetVMVersions(c *Client) (map[string]string, error) {
+	result := struct {
+		VMVersions map[string]string `json:"vmVersions"`
+	}{}
+	if err := callNodeAPI(c, "/ext/info", "info.getNodeVersion", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.VMVersions, nil
+}
+
+// RunSSHGetNodeID returns the NodeID the node is running with
+func RunSSHGetNodeID(c *Client) (string, error) {
+	result := struct {
+		NodeID string `json:"nodeID"`
+	}{}
+	if err := callNodeAPI(c, "/ext/info", "info.getNodeID", nil, &result); err != nil {
+		return "", err
+	}
+	return result.NodeID, nil
+}
+
+// RunSSHCheckBootstrapped returns true if the node is bootstrapped to the primary network
+func RunSSHCheckBootstrapped(c *Client) (bool, error) {
+	result := struct {
+		IsBootstrapped bool `json:"isBootstrapped"`
+	}{}
+	params := map[string]string{"chain": "X"}
+	if err := callNodeAPI(c, "/ext/info", "info.isBootstrapped", params, &result); err != nil {
+		return false, err
+	}
+	return result.IsBootstrapped, nil
+}
+
+// RunSSHCheckHealthy returns true if the node is healthy
+func RunSSHCheckHealthy(c *Client) (bool, error) {
+	result := struct {
+		Healthy bool `json:"healthy"`
+	}{}
+	if err := callNodeAPI(c, "/ext/health", "health.health", nil, &result); err != nil {
+		return false, err
+	}
+	return result.Healthy, nil
+}
+
+// RunSSHSubnetSyncStatus returns the status of blockchain [blockchainID] on the node, as
+// given by platform.getBlockchainStatus
+func RunSSHSubnetSyncStatus(c *Client, blockchainID string) (string, error) {
+	result := struct {
+		Status string `json:"status"`
+	}{}
+	params := map[string]string{"blockchainID": blockchainID}
+	if err := callNodeAPI(c, "/ext/bc/P", "platform.getBlockchainStatus", params, &result); err != nil {
+		return "", err
+	}
+	return result.Status, nil
+}
+
+// callNodeAPI calls the json rpc [method] of the node API, which is only accessible from
+// the host itself, and decodes the response result into [result]
+func callNodeAPI(c *Client, endpoint string, method string, params interface{}, result interface{}) error {
+	request := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+	}
+	if params != nil {
+		request["params"] = params
+	}
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	cmd := fmt.Sprintf(
+		"curl -s -X POST -H 'content-type:application/json' --data %s %s%s",
+		shellQuote(string(requestBytes)),
+		constants.DefaultNodeRunURL,
+		endpoint,
+	)
+	output, err := c.Output(cmd)
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	return parseNodeAPIResponse(method, output, result)
+}
+
+func parseNodeAPIResponse(method string, output []byte, result interface{}) error {
+	response := struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return fmt.Errorf("%s: unexpected node API response %q: %w", method, output, err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("%s: %s", method, response.Error.Message)
+	}
+	if response.Result == nil {
+		return fmt.Errorf("%s: %w", method, errors.New("node API response has no result"))
+	}
+	return json.Unmarshal(response.Result, result)
+}
+
+func getNetworkFlag(network models.Network) string {
+	switch network.Kind {
+	case models.Local:
+		return "--local"
+	case models.Devnet:
+		return "--devnet"
+	case models.Fuji:
+		return "--fuji"
+	case models.Mainnet:
+		return "--mainnet"
+	}
+	return ""
+}
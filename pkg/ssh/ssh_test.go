@@ -0,0 +1,275 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanche-cli/pkg/models"
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/require"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// startTestServer starts an ssh server that runs exec requests on the local shell and
+// serves sftp on the local filesystem, and returns a host to connect to it, and the server port
+func startTestServer(t *testing.T) (models.Host, int) {
+	require := require.New(t)
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	hostSigner, err := gossh.NewSignerFromKey(hostKey)
+	require.NoError(err)
+	clientPub, clientKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	authorizedKey, err := gossh.NewPublicKey(clientPub)
+	require.NoError(err)
+
+	keyBlock, err := gossh.MarshalPrivateKey(clientKey, "")
+	require.NoError(err)
+	keyPath := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(os.WriteFile(keyPath, pem.EncodeToMemory(keyBlock), 0o600))
+
+	config := &gossh.ServerConfig{
+		PublicKeyCallback: func(_ gossh.ConnMetadata, key gossh.PublicKey) (*gossh.Permissions, error) {
+			if string(key.Marshal()) != string(authorizedKey.Marshal()) {
+				return nil, errors.New("unknown key")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestConn(conn, config)
+		}
+	}()
+
+	host := models.Host{
+		NodeID:            "test_node",
+		IP:                "127.0.0.1",
+		SSHUser:           "test",
+		SSHPrivateKeyPath: keyPath,
+	}
+	return host, listener.Addr().(*net.TCPAddr).Port
+}
+
+func serveTestConn(conn net.Conn, config *gossh.ServerConfig) {
+	_, chans, reqs, err := gossh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go gossh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			defer channel.Close()
+			for req := range requests {
+				if req.Type == "subsystem" && string(req.Payload[4:]) == "sftp" {
+					_ = req.Reply(true, nil)
+					go gossh.DiscardRequests(requests)
+					server, err := sftp.NewServer(channel)
+					if err != nil {
+						return
+					}
+					_ = server.Serve()
+					return
+				}
+				if req.Type != "exec" {
+					_ = req.Reply(false, nil)
+					continue
+				}
+				cmdLen := binary.BigEndian.Uint32(req.Payload)
+				cmd := exec.Command("sh", "-c", string(req.Payload[4:4+cmdLen]))
+				cmd.Stdin = channel
+				cmd.Stdout = channel
+				cmd.Stderr = channel.Stderr()
+				_ = req.Reply(true, nil)
+				exitStatus := uint32(0)
+				if err := cmd.Run(); err != nil {
+					exitStatus = 1
+				}
+				_, _ = channel.SendRequest("exit-status", false, gossh.Marshal(struct{ Status uint32 }{exitStatus}))
+				return
+			}
+		}()
+	}
+}
+
+func TestRun(t *testing.T) {
+	require := require.New(t)
+	host, port := startTestServer(t)
+	client, err := connect(host, port)
+	require.NoError(err)
+	defer client.Close()
+
+	output, err := client.Run("cat; echo done", []byte("input\n"))
+	require.NoError(err)
+	require.Equal("input\ndone\n", string(output))
+
+	output, err = client.Output("echo out; echo err >&2")
+	require.NoError(err)
+	require.Equal("out\n", string(output))
+
+	_, err = client.Run("echo failure reason; exit 1", nil)
+	require.ErrorContains(err, "test_node")
+	require.ErrorContains(err, "failure reason")
+}
+
+func TestUploadDownload(t *testing.T) {
+	require := require.New(t)
+	host, port := startTestServer(t)
+	client, err := connect(host, port)
+	require.NoError(err)
+	defer client.Close()
+
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "it's a file")
+	content := []byte("some content\n")
+	require.NoError(os.WriteFile(localPath, content, 0o600))
+
+	remoteDir := filepath.Join(dir, "remote") + "/"
+	require.NoError(client.Upload(localPath, remoteDir))
+	uploaded, err := os.ReadFile(filepath.Join(remoteDir, "it's a file"))
+	require.NoError(err)
+	require.Equal(content, uploaded)
+
+	downloadPath := filepath.Join(dir, "download", "file")
+	require.NoError(client.Download(filepath.Join(remoteDir, "it's a file"), downloadPath))
+	downloaded, err := os.ReadFile(downloadPath)
+	require.NoError(err)
+	require.Equal(content, downloaded)
+
+	require.Error(client.Download(filepath.Join(dir, "missing"), downloadPath))
+}
+
+func TestRunOnHosts(t *testing.T) {
+	require := require.New(t)
+	host, port := startTestServer(t)
+	connectToServer := func(host models.Host) (*Client, error) {
+		return connect(host, port)
+	}
+	hosts := []models.Host{}
+	for _, nodeID := range []string{"node1", "node2", "node3"} {
+		h := host
+		h.NodeID = nodeID
+		hosts = append(hosts, h)
+	}
+
+	results, err := runOnHosts(hosts, connectToServer, func(c *Client) (string, error) {
+		output, err := c.Output("echo " + c.Host.NodeID)
+		return strings.TrimSpace(string(output)), err
+	})
+	require.NoError(err)
+	require.Equal(map[string]string{"node1": "node1", "node2": "node2", "node3": "node3"}, results)
+
+	results, err = runOnHosts(hosts, connectToServer, func(c *Client) (string, error) {
+		if c.Host.NodeID == "node2" {
+			return "", errors.New("node2 failed")
+		}
+		return c.Host.NodeID, nil
+	})
+	var hostErrs HostErrors
+	require.True(errors.As(err, &hostErrs))
+	require.Len(hostErrs, 1)
+	require.ErrorContains(hostErrs["node2"], "node2 failed")
+	require.Equal(map[string]string{"node1": "node1", "node3": "node3"}, results)
+}
+
+func TestRenderScript(t *testing.T) {
+	require := require.New(t)
+	script, err := renderScript("setupNode.sh", scriptInputs{AvalancheGoVersion: "v1.10.11", IsDevnet: true})
+	require.NoError(err)
+	require.Contains(string(script), "--version v1.10.11")
+	require.Contains(string(script), "sudo systemctl stop avalanchego")
+
+	script, err = renderScript("setupNode.sh", scriptInputs{AvalancheGoVersion: "v1.10.11"})
+	require.NoError(err)
+	require.NotContains(string(script), "sudo systemctl stop avalanchego")
+
+	script, err = renderScript("updateSubnet.sh", scriptInputs{
+		CLIBinPath:           "/bin/avalanche",
+		SubnetName:           "mySubnet",
+		SubnetExportFileName: "/tmp/mySubnet-export.dat",
+		NetworkFlag:          getNetworkFlag(models.DevnetNetwork),
+	})
+	require.NoError(err)
+	require.Contains(string(script), "/bin/avalanche subnet join mySubnet --devnet")
+	require.NotContains(string(script), "--fuji")
+
+	script, err = renderScript("trackSubnet.sh", scriptInputs{
+		CLIBinPath:           "/bin/avalanche",
+		SubnetName:           "mySubnet",
+		SubnetExportFileName: "/tmp/mySubnet-export.dat",
+		NetworkFlag:          getNetworkFlag(models.FujiNetwork),
+	})
+	require.NoError(err)
+	require.Contains(string(script), "/bin/avalanche subnet join mySubnet --fuji")
+	require.NotContains(string(script), "{{")
+
+	script, err = renderScript("setupNodeExporter.sh", scriptInputs{AvalanchegoAPIPort: 9650})
+	require.NoError(err)
+	require.Contains(string(script), "http://127.0.0.1:9650/ext/metrics")
+
+	script, err = renderScript("setupMonitoring.sh", scriptInputs{MonitoringDir: "monitoring", GrafanaAdminPassword: "secret"})
+	require.NoError(err)
+	require.Contains(string(script), "sudo cp monitoring/prometheus.yml /etc/prometheus/prometheus.yml")
+	require.Contains(string(script), "reset-admin-password 'secret'")
+	require.NotContains(string(script), "{{")
+
+	script, err = RenderLogsScript(".avalanchego/logs/", []string{"main.log", "P.log"}, 50, 0, true)
+	require.NoError(err)
+	require.Contains(string(script), "tail -n 50 -F 'main.log' 'P.log'")
+	require.NotContains(string(script), "CUTOFF=$(date")
+
+	script, err = RenderLogsScript(".avalanchego/logs/", []string{"main.log"}, 50, time.Hour, false)
+	require.NoError(err)
+	require.Contains(string(script), "NOW_SECONDS - 3600")
+	require.Contains(string(script), "'+%Y-%m-%d|%H:%M:%S'")
+	require.Contains(string(script), "tail -n +1 'main.log'")
+	require.Contains(string(script), `-v name="main"`)
+
+	_, err = RenderLogsScript(".avalanchego/logs/", nil, 50, 0, false)
+	require.Error(err)
+}
+
+func TestParseNodeAPIResponse(t *testing.T) {
+	require := require.New(t)
+	versions := struct {
+		VMVersions map[string]string `json:"vmVersions"`
+	}{}
+	output := []byte(`{"jsonrpc":"2.0","result":{"version":"avalanche/1.10.11","vmVersions":{"platform":"v1.10.11","subnet-evm":"v0.5.6"}},"id":1}`)
+	require.NoError(parseNodeAPIResponse("info.getNodeVersion", output, &versions))
+	require.Equal("v1.10.11", versions.VMVersions["platform"])
+	require.Equal("v0.5.6", versions.VMVersions["subnet-evm"])
+
+	status := struct {
+		Status string `json:"status"`
+	}{}
+	output = []byte(`{"jsonrpc":"2.0","error":{"code":-32000,"message":"problem"},"id":1}`)
+	require.ErrorContains(parseNodeAPIResponse("platform.getBlockchainStatus", output, &status), "problem")
+	require.Error(parseNodeAPIResponse("platform.getBlockchainStatus", []byte(""), &status))
+	require.Error(parseNodeAPIResponse("platform.getBlockchainStatus", []byte(`{"id":1}`), &status))
+}
@@ -7,7 +7,6 @@ import (
 	"os"
 
 	"github.com/ava-labs/avalanche-cli/pkg/application"
-	"github.com/ava-labs/avalanche-cli/pkg/models"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 )
 
@@ -36,7 +35,7 @@ func GetLocallyDeployedSubnetsFromFile(app *application.Avalanche) ([]string, er
 
 		// check if sidecar contains local deployment info in Networks map
 		// if so, add to list of deployed subnets
-		if _, ok := sc.Networks[models.Local.String()]; ok {
+		if _, ok := sc.Networks[app.GetLocalNetwork().Name()]; ok {
 			deployedSubnets = append(deployedSubnets, sc.Name)
 		}
 	}
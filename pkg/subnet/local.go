@@ -5,7 +5,6 @@ package subnet
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -14,6 +13,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"golang.org/x/exp/maps"
 
@@ -25,9 +25,11 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-cli/pkg/vm"
 	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/network"
 	"github.com/ava-labs/avalanche-network-runner/rpcpb"
 	"github.com/ava-labs/avalanche-network-runner/server"
 	anrutils "github.com/ava-labs/avalanche-network-runner/utils"
+	"github.com/ava-labs/avalanchego/config"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/crypto/keychain"
@@ -154,6 +156,7 @@ func importFromXChain(wallet primary.Wallet, owner *secp256k1fx.OutputOwners) er
 }
 
 func IssueTransformSubnetTx(
+	network models.Network,
 	elasticSubnetConfig models.ElasticSubnetConfig,
 	kc keychain.Keychain,
 	subnetID ids.ID,
@@ -162,7 +165,7 @@ func IssueTransformSubnetTx(
 	maxSupply uint64,
 ) (ids.ID, ids.ID, error) {
 	ctx := context.Background()
-	api := constants.LocalAPIEndpoint
+	api := network.Endpoint
 	wallet, err := primary.MakeWallet(
 		ctx,
 		&primary.WalletConfig{
@@ -210,6 +213,7 @@ func IssueTransformSubnetTx(
 }
 
 func IssueAddPermissionlessValidatorTx(
+	network models.Network,
 	kc keychain.Keychain,
 	subnetID ids.ID,
 	nodeID ids.NodeID,
@@ -219,7 +223,7 @@ func IssueAddPermissionlessValidatorTx(
 	endTime uint64,
 ) (ids.ID, error) {
 	ctx := context.Background()
-	api := constants.LocalAPIEndpoint
+	api := network.Endpoint
 	wallet, err := primary.MakeWallet(
 		ctx,
 		&primary.WalletConfig{
@@ -264,6 +268,7 @@ func IssueAddPermissionlessValidatorTx(
 }
 
 func IssueAddPermissionlessDelegatorTx(
+	network models.Network,
 	kc keychain.Keychain,
 	subnetID ids.ID,
 	nodeID ids.NodeID,
@@ -273,7 +278,7 @@ func IssueAddPermissionlessDelegatorTx(
 	endTime uint64,
 ) (ids.ID, error) {
 	ctx := context.Background()
-	api := constants.LocalAPIEndpoint
+	api := network.Endpoint
 	wallet, err := primary.MakeWallet(
 		ctx,
 		&primary.WalletConfig{
@@ -323,8 +328,8 @@ func (d *LocalDeployer) StartServer() error {
 	return nil
 }
 
-func GetCurrentSupply(subnetID ids.ID) error {
-	api := constants.LocalAPIEndpoint
+func GetCurrentSupply(network models.Network, subnetID ids.ID) error {
+	api := network.Endpoint
 	pClient := platformvm.NewClient(api)
 	ctx, cancel := utils.GetAPIContext()
 	defer cancel()
@@ -413,7 +418,7 @@ func (d *LocalDeployer) doDeploy(chain string, chainGenesis []byte, genesisPath
 	}
 	rootDir = clusterInfo.GetRootDataDir()
 
-	if alreadyDeployed(chainVMID, clusterInfo) {
+	if alreadyDeployed(chainVMID, sc.Networks[d.app.GetLocalNetwork().Name()].BlockchainID, clusterInfo) {
 		ux.Logger.PrintToUser("Subnet %s has already been deployed", chain)
 		return ids.Empty, ids.Empty, nil
 	}
@@ -427,11 +432,21 @@ func (d *LocalDeployer) doDeploy(chain string, chainGenesis []byte, genesisPath
 	// we select one to be used for creating the next blockchain, for that we use the
 	// number of currently created blockchains as the index to select the next subnet ID,
 	// so we get incremental selection
+	// networks booted from a custom genesis have no preloaded subnets, so a new
+	// subnet is created for each blockchain
 	sort.Strings(subnetIDs)
-	if len(subnetIDs) == 0 {
-		return ids.Empty, ids.Empty, errors.New("the network has not preloaded subnet IDs")
+	var subnetIDStr *string
+	if len(subnetIDs) > 0 {
+		deterministic := d.app.IsDeterministicLocalNetwork()
+		if deterministic && numBlockchains >= len(subnetIDs) {
+			// reusing a subnet would make the result depend on previous deploys to it
+			return ids.Empty, ids.Empty, fmt.Errorf("deterministic network supports at most %d blockchains", len(subnetIDs))
+		}
+		subnetIDStr = &subnetIDs[numBlockchains%len(subnetIDs)]
+		if deterministic {
+			ux.Logger.PrintToUser("Deterministic network: deploying blockchain #%d into preloaded subnet %s", numBlockchains+1, *subnetIDStr)
+		}
 	}
-	subnetIDStr := subnetIDs[numBlockchains%len(subnetIDs)]
 
 	// if a chainConfig has been configured
 	var (
@@ -467,7 +482,7 @@ func (d *LocalDeployer) doDeploy(chain string, chainGenesis []byte, genesisPath
 		{
 			VmName:   chain,
 			Genesis:  genesisPath,
-			SubnetId: &subnetIDStr,
+			SubnetId: subnetIDStr,
 			SubnetSpec: &rpcpb.SubnetSpec{
 				SubnetConfig: subnetConfig,
 			},
@@ -502,7 +517,14 @@ func (d *LocalDeployer) doDeploy(chain string, chainGenesis []byte, genesisPath
 		return ids.Empty, ids.Empty, fmt.Errorf("failed to query network health: %w", err)
 	}
 
-	endpoint := GetFirstEndpoint(clusterInfo, chain)
+	// blockchains removed from the network with the same VM are still registered on the
+	// P-Chain, so the new one is identified by the ID returned on creation
+	var blockchainID ids.ID
+	if len(deployBlockchainsInfo.ChainIds) > 0 {
+		blockchainID, _ = ids.FromString(deployBlockchainsInfo.ChainIds[0])
+	}
+
+	endpoint := GetFirstEndpoint(clusterInfo, blockchainID.String())
 
 	fmt.Println()
 	ux.Logger.PrintToUser("Blockchain ready to use. Local network node endpoints:")
@@ -520,12 +542,11 @@ func (d *LocalDeployer) doDeploy(chain string, chainGenesis []byte, genesisPath
 	}
 
 	// we can safely ignore errors here as the subnets have already been generated
-	subnetID, _ := ids.FromString(subnetIDStr)
-	var blockchainID ids.ID
-	for _, info := range clusterInfo.CustomChains {
-		if info.VmId == chainVMID.String() {
-			blockchainID, _ = ids.FromString(info.ChainId)
-		}
+	var subnetID ids.ID
+	if subnetIDStr != nil {
+		subnetID, _ = ids.FromString(*subnetIDStr)
+	} else if info, ok := clusterInfo.CustomChains[blockchainID.String()]; ok {
+		subnetID, _ = ids.FromString(info.SubnetId)
 	}
 	return subnetID, blockchainID, nil
 }
@@ -560,6 +581,12 @@ func (d *LocalDeployer) SetupLocalEnv() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed setting up snapshots: %w", err)
 	}
+	// named local network instances use their own port range
+	if offset := d.app.GetLocalNetworkPortsOffset(); offset != 0 {
+		if err := SetSnapshotNodePorts(d.app.GetSnapshotsDir(), constants.DefaultSnapshotName, constants.AvalanchegoAPIPort+offset); err != nil {
+			return "", fmt.Errorf("failed setting up snapshot ports: %w", err)
+		}
+	}
 
 	avagoDir, err := d.setupLocalEnv()
 	if err != nil {
@@ -609,12 +636,12 @@ func WaitForHealthy(
 	return resp.ClusterInfo, nil
 }
 
-// GetFirstEndpoint get a human readable endpoint for the given chain
-func GetFirstEndpoint(clusterInfo *rpcpb.ClusterInfo, chain string) string {
+// GetFirstEndpoint get a human readable endpoint for the given blockchain
+func GetFirstEndpoint(clusterInfo *rpcpb.ClusterInfo, blockchainIDStr string) string {
 	var endpoint string
 	for _, nodeInfo := range clusterInfo.NodeInfos {
 		for blockchainID, chainInfo := range clusterInfo.CustomChains {
-			if chainInfo.ChainName == chain && nodeInfo.Name == clusterInfo.NodeNames[0] {
+			if chainInfo.ChainId == blockchainIDStr && nodeInfo.Name == clusterInfo.NodeNames[0] {
 				endpoint = fmt.Sprintf("Endpoint at node %s for blockchain %q with VM ID %q: %s/ext/bc/%s/rpc", nodeInfo.Name, blockchainID, chainInfo.VmId, nodeInfo.GetUri(), blockchainID)
 			}
 		}
@@ -627,11 +654,13 @@ func HasEndpoints(clusterInfo *rpcpb.ClusterInfo) bool {
 	return len(clusterInfo.CustomChains) > 0
 }
 
-// return true if vm has already been deployed
-func alreadyDeployed(chainVMID ids.ID, clusterInfo *rpcpb.ClusterInfo) bool {
+// return true if vm has already been deployed as [blockchainID]. Blockchains removed from the
+// network with subnet redeploy are still registered on the P-Chain, so only the blockchain
+// recorded in the sidecar is considered
+func alreadyDeployed(chainVMID ids.ID, blockchainID ids.ID, clusterInfo *rpcpb.ClusterInfo) bool {
 	if clusterInfo != nil {
 		for _, chainInfo := range clusterInfo.CustomChains {
-			if chainInfo.VmId == chainVMID.String() {
+			if chainInfo.VmId == chainVMID.String() && chainInfo.ChainId == blockchainID.String() {
 				return true
 			}
 		}
@@ -729,6 +758,221 @@ func SetDefaultSnapshot(snapshotsDir string, force bool) error {
 	return nil
 }
 
+func getSnapshotNetworkConfigPath(snapshotsDir string, snapshotName string) string {
+	return filepath.Join(snapshotsDir, "anr-snapshot-"+snapshotName, "network.json")
+}
+
+func loadSnapshotNetworkConfig(networkConfigPath string) (*network.Config, error) {
+	networkConfigBytes, err := os.ReadFile(networkConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	var networkConfig network.Config
+	if err := json.Unmarshal(networkConfigBytes, &networkConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot network config %s: %w", networkConfigPath, err)
+	}
+	return &networkConfig, nil
+}
+
+// SetSnapshotNodePorts assigns consecutive API and staking ports, starting at [apiPort],
+// to the nodes of the given snapshot. Does nothing if the snapshot does not exist
+func SetSnapshotNodePorts(snapshotsDir string, snapshotName string, apiPort int) error {
+	networkConfigPath := getSnapshotNetworkConfigPath(snapshotsDir, snapshotName)
+	networkConfig, err := loadSnapshotNetworkConfig(networkConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	sort.SliceStable(networkConfig.NodeConfigs, func(i, j int) bool {
+		return networkConfig.NodeConfigs[i].Name < networkConfig.NodeConfigs[j].Name
+	})
+	for i := range networkConfig.NodeConfigs {
+		if networkConfig.NodeConfigs[i].Flags == nil {
+			networkConfig.NodeConfigs[i].Flags = map[string]interface{}{}
+		}
+		networkConfig.NodeConfigs[i].Flags[config.HTTPPortKey] = apiPort + 2*i
+		networkConfig.NodeConfigs[i].Flags[config.StakingPortKey] = apiPort + 2*i + 1
+	}
+	networkConfigBytes, err := json.MarshalIndent(networkConfig, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(networkConfigPath, networkConfigBytes, constants.WriteReadReadPerms)
+}
+
+// CheckDeterministicSnapshot verifies that booting the given snapshot does not depend
+// on generated data: all nodes must have fixed staking keys, and the genesis must have
+// a fixed start time, which is returned
+func CheckDeterministicSnapshot(snapshotsDir string, snapshotName string) (time.Time, error) {
+	networkConfig, err := loadSnapshotNetworkConfig(getSnapshotNetworkConfigPath(snapshotsDir, snapshotName))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed loading snapshot %s: %w", snapshotName, err)
+	}
+	if len(networkConfig.NodeConfigs) == 0 {
+		return time.Time{}, fmt.Errorf("snapshot %s has no nodes", snapshotName)
+	}
+	for _, nodeConfig := range networkConfig.NodeConfigs {
+		if nodeConfig.StakingKey == "" || nodeConfig.StakingCert == "" || nodeConfig.StakingSigningKey == "" {
+			return time.Time{}, fmt.Errorf("node %s of snapshot %s has no fixed staking keys", nodeConfig.Name, snapshotName)
+		}
+	}
+	genesis := struct {
+		StartTime uint64 `json:"startTime"`
+	}{}
+	if err := json.Unmarshal([]byte(networkConfig.Genesis), &genesis); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse genesis of snapshot %s: %w", snapshotName, err)
+	}
+	if genesis.StartTime == 0 {
+		return time.Time{}, fmt.Errorf("genesis of snapshot %s has no fixed start time", snapshotName)
+	}
+	return time.Unix(int64(genesis.StartTime), 0).UTC(), nil
+}
+
+// GetSnapshotNodeIDs returns the node IDs of the nodes of the given snapshot, computed
+// from their staking keys
+func GetSnapshotNodeIDs(snapshotsDir string, snapshotName string) ([]ids.NodeID, error) {
+	networkConfig, err := loadSnapshotNetworkConfig(getSnapshotNetworkConfigPath(snapshotsDir, snapshotName))
+	if err != nil {
+		return nil, fmt.Errorf("failed loading snapshot %s: %w", snapshotName, err)
+	}
+	nodeIDs := make([]ids.NodeID, len(networkConfig.NodeConfigs))
+	for i, nodeConfig := range networkConfig.NodeConfigs {
+		nodeIDs[i], err = utils.ToNodeID([]byte(nodeConfig.StakingCert), []byte(nodeConfig.StakingKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed computing node ID of node %s: %w", nodeConfig.Name, err)
+		}
+	}
+	return nodeIDs, nil
+}
+
+// SetSnapshotGenesis makes the given snapshot boot a fresh network from [genesisBytes], with
+// [flags] added to all nodes. The nodes keep their staking keys, while their db and the
+// preloaded subnets are cleared
+func SetSnapshotGenesis(snapshotsDir string, snapshotName string, genesisBytes []byte, flags map[string]interface{}) error {
+	networkConfigPath := getSnapshotNetworkConfigPath(snapshotsDir, snapshotName)
+	networkConfig, err := loadSnapshotNetworkConfig(networkConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed loading snapshot %s: %w", snapshotName, err)
+	}
+	networkConfig.Genesis = string(genesisBytes)
+	delete(networkConfig.Flags, config.TrackSubnetsKey)
+	snapshotDir := filepath.Dir(networkConfigPath)
+	dbDir := filepath.Join(snapshotDir, "db")
+	if err := os.RemoveAll(dbDir); err != nil {
+		return err
+	}
+	for i := range networkConfig.NodeConfigs {
+		if networkConfig.NodeConfigs[i].Flags == nil {
+			networkConfig.NodeConfigs[i].Flags = map[string]interface{}{}
+		}
+		delete(networkConfig.NodeConfigs[i].Flags, config.TrackSubnetsKey)
+		for k, v := range flags {
+			networkConfig.NodeConfigs[i].Flags[k] = v
+		}
+		// nodes db is loaded from the snapshot, so it must exist even if empty
+		if err := os.MkdirAll(filepath.Join(dbDir, networkConfig.NodeConfigs[i].Name), constants.DefaultPerms755); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(filepath.Join(snapshotDir, "state.json")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	networkConfigBytes, err := json.MarshalIndent(networkConfig, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(networkConfigPath, networkConfigBytes, constants.WriteReadReadPerms)
+}
+
+// untrackSubnet removes [subnetID] from the tracked subnets of the given node flags
+func untrackSubnet(flags map[string]interface{}, subnetID ids.ID) error {
+	trackSubnetsIntf, ok := flags[config.TrackSubnetsKey]
+	if !ok {
+		return nil
+	}
+	trackSubnets, ok := trackSubnetsIntf.(string)
+	if !ok {
+		return fmt.Errorf("expected node flag %s to have type string, obtained %T", config.TrackSubnetsKey, trackSubnetsIntf)
+	}
+	tracked := []string{}
+	for _, trackedSubnetID := range strings.Split(trackSubnets, ",") {
+		if trackedSubnetID != "" && trackedSubnetID != subnetID.String() {
+			tracked = append(tracked, trackedSubnetID)
+		}
+	}
+	flags[config.TrackSubnetsKey] = strings.Join(tracked, ",")
+	return nil
+}
+
+// RemoveSnapshotSubnet makes the nodes of the given snapshot stop tracking [subnetID], so they
+// don't run its blockchain [blockchainID] anymore, and removes their configs and the chain data
+// they keep for it. The blockchain can't be removed from the P-Chain, but the state of the other
+// blockchains is kept. Does nothing if the snapshot does not exist
+func RemoveSnapshotSubnet(snapshotsDir string, snapshotName string, subnetID ids.ID, blockchainID ids.ID) error {
+	networkConfigPath := getSnapshotNetworkConfigPath(snapshotsDir, snapshotName)
+	networkConfig, err := loadSnapshotNetworkConfig(networkConfigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed loading snapshot %s: %w", snapshotName, err)
+	}
+	if err := untrackSubnet(networkConfig.Flags, subnetID); err != nil {
+		return err
+	}
+	delete(networkConfig.ChainConfigFiles, blockchainID.String())
+	delete(networkConfig.UpgradeConfigFiles, blockchainID.String())
+	delete(networkConfig.SubnetConfigFiles, subnetID.String())
+	for i := range networkConfig.NodeConfigs {
+		if err := untrackSubnet(networkConfig.NodeConfigs[i].Flags, subnetID); err != nil {
+			return err
+		}
+		delete(networkConfig.NodeConfigs[i].ChainConfigFiles, blockchainID.String())
+		delete(networkConfig.NodeConfigs[i].UpgradeConfigFiles, blockchainID.String())
+		delete(networkConfig.NodeConfigs[i].SubnetConfigFiles, subnetID.String())
+	}
+	networkConfigBytes, err := json.MarshalIndent(networkConfig, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(networkConfigPath, networkConfigBytes, constants.WriteReadReadPerms); err != nil {
+		return err
+	}
+	// node dbs are saved as db/<node>/<network>/, where the chain data dir of the blockchain,
+	// if any, is found next to the one of its db
+	chainDataDirs, err := filepath.Glob(filepath.Join(filepath.Dir(networkConfigPath), "db", "*", "*", blockchainID.String()))
+	if err != nil {
+		return err
+	}
+	for _, chainDataDir := range chainDataDirs {
+		if err := os.RemoveAll(chainDataDir); err != nil {
+			return fmt.Errorf("failed removing chain data %s: %w", chainDataDir, err)
+		}
+	}
+	return nil
+}
+
+// GetTrackedCustomChains returns the custom chains of [clusterInfo] whose subnet is tracked by
+// some node. The local network lists all the blockchains of the P-Chain, including the ones
+// removed from the network, which no node runs anymore
+func GetTrackedCustomChains(clusterInfo *rpcpb.ClusterInfo) map[string]*rpcpb.CustomChainInfo {
+	trackedSubnets := map[string]bool{}
+	for _, nodeInfo := range clusterInfo.NodeInfos {
+		for _, subnetID := range strings.Split(nodeInfo.WhitelistedSubnets, ",") {
+			trackedSubnets[subnetID] = true
+		}
+	}
+	customChains := map[string]*rpcpb.CustomChainInfo{}
+	for blockchainID, chainInfo := range clusterInfo.CustomChains {
+		if trackedSubnets[chainInfo.SubnetId] {
+			customChains[blockchainID] = chainInfo
+		}
+	}
+	return customChains
+}
+
 // start the network
 func (d *LocalDeployer) startNetwork(
 	ctx context.Context,
@@ -791,9 +1035,9 @@ func GetLocallyDeployedSubnets() (map[string]struct{}, error) {
 	return deployedNames, nil
 }
 
-func IssueRemoveSubnetValidatorTx(kc keychain.Keychain, subnetID ids.ID, nodeID ids.NodeID) (ids.ID, error) {
+func IssueRemoveSubnetValidatorTx(network models.Network, kc keychain.Keychain, subnetID ids.ID, nodeID ids.NodeID) (ids.ID, error) {
 	ctx := context.Background()
-	api := constants.LocalAPIEndpoint
+	api := network.Endpoint
 	wallet, err := primary.MakeWallet(
 		ctx,
 		&primary.WalletConfig{
@@ -811,8 +1055,8 @@ func IssueRemoveSubnetValidatorTx(kc keychain.Keychain, subnetID ids.ID, nodeID
 	return tx.ID(), err
 }
 
-func GetSubnetValidators(subnetID ids.ID) ([]platformvm.ClientPermissionlessValidator, error) {
-	api := constants.LocalAPIEndpoint
+func GetSubnetValidators(network models.Network, subnetID ids.ID) ([]platformvm.ClientPermissionlessValidator, error) {
+	api := network.Endpoint
 	pClient := platformvm.NewClient(api)
 	ctx, cancel := utils.GetAPIContext()
 	defer cancel()
@@ -820,8 +1064,8 @@ func GetSubnetValidators(subnetID ids.ID) ([]platformvm.ClientPermissionlessVali
 	return pClient.GetCurrentValidators(ctx, subnetID, nil)
 }
 
-func CheckNodeIsInSubnetPendingValidators(subnetID ids.ID, nodeID string) (bool, error) {
-	api := constants.LocalAPIEndpoint
+func CheckNodeIsInSubnetPendingValidators(network models.Network, subnetID ids.ID, nodeID string) (bool, error) {
+	api := network.Endpoint
 	pClient := platformvm.NewClient(api)
 	ctx, cancel := utils.GetAPIContext()
 	defer cancel()
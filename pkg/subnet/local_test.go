@@ -3,6 +3,7 @@
 package subnet
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,10 +18,15 @@ import (
 	"github.com/ava-labs/avalanche-cli/pkg/config"
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 	"github.com/ava-labs/avalanche-cli/pkg/prompts"
+	"github.com/ava-labs/avalanche-cli/pkg/utils"
 	"github.com/ava-labs/avalanche-cli/pkg/ux"
 	"github.com/ava-labs/avalanche-network-runner/client"
+	"github.com/ava-labs/avalanche-network-runner/network"
+	"github.com/ava-labs/avalanche-network-runner/network/node"
 	"github.com/ava-labs/avalanche-network-runner/rpcpb"
+	avagoconfig "github.com/ava-labs/avalanchego/config"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/perms"
 	"github.com/stretchr/testify/mock"
@@ -164,12 +170,160 @@ func TestGetLatestAvagoVersion(t *testing.T) {
 	require.Equal(v, testVersion)
 }
 
+func TestSnapshotNetworkConfig(t *testing.T) {
+	require := setupTest(t)
+
+	snapshotsDir := t.TempDir()
+	// missing snapshots are skipped
+	require.NoError(SetSnapshotNodePorts(snapshotsDir, "missing", 9750))
+	_, err := CheckDeterministicSnapshot(snapshotsDir, "missing")
+	require.Error(err)
+
+	networkConfig := network.Config{
+		Genesis: `{"startTime": 1630987200}`,
+		NodeConfigs: []node.Config{
+			{Name: "node2", StakingKey: "k2", StakingCert: "c2", StakingSigningKey: "s2"},
+			{Name: "node1", StakingKey: "k1", StakingCert: "c1", StakingSigningKey: "s1"},
+		},
+	}
+	writeTestSnapshot(t, snapshotsDir, "test", networkConfig)
+
+	require.NoError(SetSnapshotNodePorts(snapshotsDir, "test", 9750))
+	updatedConfig, err := loadSnapshotNetworkConfig(getSnapshotNetworkConfigPath(snapshotsDir, "test"))
+	require.NoError(err)
+	require.Equal("node1", updatedConfig.NodeConfigs[0].Name)
+	require.Equal(float64(9750), updatedConfig.NodeConfigs[0].Flags[avagoconfig.HTTPPortKey])
+	require.Equal(float64(9753), updatedConfig.NodeConfigs[1].Flags[avagoconfig.StakingPortKey])
+
+	startTime, err := CheckDeterministicSnapshot(snapshotsDir, "test")
+	require.NoError(err)
+	require.Equal(int64(1630987200), startTime.Unix())
+
+	networkConfig.NodeConfigs[0].StakingKey = ""
+	writeTestSnapshot(t, snapshotsDir, "test", networkConfig)
+	_, err = CheckDeterministicSnapshot(snapshotsDir, "test")
+	require.ErrorContains(err, "node2")
+}
+
+func TestSetSnapshotGenesis(t *testing.T) {
+	require := setupTest(t)
+
+	snapshotsDir := t.TempDir()
+	certBytes, keyBytes, err := staking.NewCertAndKeyBytes()
+	require.NoError(err)
+	networkConfig := network.Config{
+		Genesis: `{"startTime": 1630987200}`,
+		Flags:   map[string]interface{}{avagoconfig.TrackSubnetsKey: "subnet1"},
+		NodeConfigs: []node.Config{
+			{Name: "node1", StakingKey: string(keyBytes), StakingCert: string(certBytes)},
+		},
+	}
+	writeTestSnapshot(t, snapshotsDir, "test", networkConfig)
+	snapshotDir := filepath.Dir(getSnapshotNetworkConfigPath(snapshotsDir, "test"))
+	stateFile := filepath.Join(snapshotDir, "state.json")
+	require.NoError(os.WriteFile(stateFile, []byte("{}"), constants.WriteReadReadPerms))
+
+	nodeIDs, err := GetSnapshotNodeIDs(snapshotsDir, "test")
+	require.NoError(err)
+	expectedNodeID, err := utils.ToNodeID(certBytes, keyBytes)
+	require.NoError(err)
+	require.Equal([]ids.NodeID{expectedNodeID}, nodeIDs)
+
+	flags := map[string]interface{}{avagoconfig.MinStakeDurationKey: "1h0m0s"}
+	require.NoError(SetSnapshotGenesis(snapshotsDir, "test", []byte(`{"networkID": 1337}`), flags))
+	updatedConfig, err := loadSnapshotNetworkConfig(getSnapshotNetworkConfigPath(snapshotsDir, "test"))
+	require.NoError(err)
+	require.Equal(`{"networkID": 1337}`, updatedConfig.Genesis)
+	require.NotContains(updatedConfig.Flags, avagoconfig.TrackSubnetsKey)
+	require.Equal("1h0m0s", updatedConfig.NodeConfigs[0].Flags[avagoconfig.MinStakeDurationKey])
+	require.DirExists(filepath.Join(snapshotDir, "db", "node1"))
+	require.NoFileExists(stateFile)
+}
+
+func TestRemoveSnapshotSubnet(t *testing.T) {
+	require := setupTest(t)
+
+	snapshotsDir := t.TempDir()
+	subnetID1 := ids.GenerateTestID()
+	subnetID2 := ids.GenerateTestID()
+	blockchainID1 := ids.GenerateTestID()
+	blockchainID2 := ids.GenerateTestID()
+	networkConfig := network.Config{
+		Flags:            map[string]interface{}{avagoconfig.TrackSubnetsKey: subnetID1.String() + "," + subnetID2.String()},
+		ChainConfigFiles: map[string]string{blockchainID1.String(): "{}", blockchainID2.String(): "{}"},
+		NodeConfigs: []node.Config{
+			{
+				Name:              "node1",
+				Flags:             map[string]interface{}{avagoconfig.TrackSubnetsKey: subnetID2.String() + "," + subnetID1.String()},
+				ChainConfigFiles:  map[string]string{blockchainID1.String(): "{}", blockchainID2.String(): "{}"},
+				SubnetConfigFiles: map[string]string{subnetID1.String(): "{}"},
+			},
+		},
+	}
+	writeTestSnapshot(t, snapshotsDir, "test", networkConfig)
+	snapshotDir := filepath.Dir(getSnapshotNetworkConfigPath(snapshotsDir, "test"))
+	chainDataDirs := map[ids.ID][]string{}
+	for _, blockchainID := range []ids.ID{blockchainID1, blockchainID2} {
+		for _, dir := range []string{"network-12345", "chainData"} {
+			chainDataDir := filepath.Join(snapshotDir, "db", "node1", dir, blockchainID.String())
+			require.NoError(os.MkdirAll(chainDataDir, constants.DefaultPerms755))
+			chainDataDirs[blockchainID] = append(chainDataDirs[blockchainID], chainDataDir)
+		}
+	}
+
+	require.NoError(RemoveSnapshotSubnet(snapshotsDir, "test", subnetID1, blockchainID1))
+	updatedConfig, err := loadSnapshotNetworkConfig(getSnapshotNetworkConfigPath(snapshotsDir, "test"))
+	require.NoError(err)
+	require.Equal(subnetID2.String(), updatedConfig.Flags[avagoconfig.TrackSubnetsKey])
+	require.Equal(map[string]string{blockchainID2.String(): "{}"}, updatedConfig.ChainConfigFiles)
+	require.Equal(subnetID2.String(), updatedConfig.NodeConfigs[0].Flags[avagoconfig.TrackSubnetsKey])
+	require.Equal(map[string]string{blockchainID2.String(): "{}"}, updatedConfig.NodeConfigs[0].ChainConfigFiles)
+	require.Empty(updatedConfig.NodeConfigs[0].SubnetConfigFiles)
+	for _, chainDataDir := range chainDataDirs[blockchainID1] {
+		require.NoDirExists(chainDataDir)
+	}
+	for _, chainDataDir := range chainDataDirs[blockchainID2] {
+		require.DirExists(chainDataDir)
+	}
+
+	// a missing snapshot has nothing to edit
+	require.NoError(RemoveSnapshotSubnet(snapshotsDir, "missing", subnetID1, blockchainID1))
+}
+
+func TestGetTrackedCustomChains(t *testing.T) {
+	require := setupTest(t)
+	clusterInfo := &rpcpb.ClusterInfo{
+		NodeInfos: map[string]*rpcpb.NodeInfo{
+			"node1": {WhitelistedSubnets: "subnet1,subnet2"},
+			"node2": {WhitelistedSubnets: "subnet1"},
+		},
+		CustomChains: map[string]*rpcpb.CustomChainInfo{
+			"chain1": {SubnetId: "subnet1"},
+			"chain2": {SubnetId: "subnet2"},
+			"chain3": {SubnetId: "subnet3"},
+		},
+	}
+	require.Equal(map[string]*rpcpb.CustomChainInfo{
+		"chain1": {SubnetId: "subnet1"},
+		"chain2": {SubnetId: "subnet2"},
+	}, GetTrackedCustomChains(clusterInfo))
+	require.Empty(GetTrackedCustomChains(&rpcpb.ClusterInfo{CustomChains: clusterInfo.CustomChains}))
+}
+
+func writeTestSnapshot(t *testing.T, snapshotsDir string, snapshotName string, networkConfig network.Config) {
+	networkConfigPath := getSnapshotNetworkConfigPath(snapshotsDir, snapshotName)
+	require.NoError(t, os.MkdirAll(filepath.Dir(networkConfigPath), constants.DefaultPerms755))
+	networkConfigBytes, err := json.Marshal(networkConfig)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(networkConfigPath, networkConfigBytes, constants.WriteReadReadPerms))
+}
+
 func getTestClientFunc(...binutils.GRPCClientOpOption) (client.Client, error) {
 	c := &mocks.Client{}
 	fakeLoadSnapshotResponse := &rpcpb.LoadSnapshotResponse{}
 	fakeSaveSnapshotResponse := &rpcpb.SaveSnapshotResponse{}
 	fakeRemoveSnapshotResponse := &rpcpb.RemoveSnapshotResponse{}
-	fakeCreateBlockchainsResponse := &rpcpb.CreateBlockchainsResponse{}
+	fakeCreateBlockchainsResponse := &rpcpb.CreateBlockchainsResponse{ChainIds: []string{testBlockChainID2}}
 	c.On("LoadSnapshot", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fakeLoadSnapshotResponse, nil)
 	c.On("SaveSnapshot", mock.Anything, mock.Anything).Return(fakeSaveSnapshotResponse, nil)
 	c.On("RemoveSnapshot", mock.Anything, mock.Anything).Return(fakeRemoveSnapshotResponse, nil)
@@ -6,9 +6,11 @@ package terraformaws
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/terraform"
@@ -88,6 +90,12 @@ func SetSecurityGroupRule(rootBody *hclwrite.Body, ipAddress, sgID string, ipInT
 	}
 }
 
+// SetSecurityGroupPortRule allows inbound tcp access from [ipAddress] to [port] on the existing sg [sgID]
+func SetSecurityGroupPortRule(rootBody *hclwrite.Body, ipAddress, sgID string, port int64) {
+	sgRuleName := fmt.Sprintf("ip%s%s", strconv.FormatInt(port, 10), strings.ReplaceAll(ipAddress, ".", ""))
+	addNewSecurityGroupRule(rootBody, sgRuleName, sgID, "ingress", "tcp", ipAddress+"/32", port)
+}
+
 // SetElasticIPs attach elastic IP(s) to the associated ec2 instance(s)
 func SetElasticIPs(rootBody *hclwrite.Body, numNodes int) {
 	eip := rootBody.AppendNewBlock("resource", []string{"aws_eip", "myeip"})
@@ -148,12 +156,13 @@ func SetKeyPair(rootBody *hclwrite.Body, keyName, certName string) {
 }
 
 // SetupInstances adds aws_instance section in terraform state file where we configure all the necessary components of the desired ec2 instance(s)
-func SetupInstances(rootBody *hclwrite.Body, securityGroupName string, useExistingKeyPair bool, existingKeyPairName, ami string, numNodes int) {
+// [diskIOPS] is only set for disk types with provisioned IOPS (gp3, io1, io2), 0 to use the default ones
+func SetupInstances(rootBody *hclwrite.Body, securityGroupName string, useExistingKeyPair bool, existingKeyPairName, ami, instanceType, diskType string, diskSize, diskIOPS, numNodes int) {
 	awsInstance := rootBody.AppendNewBlock("resource", []string{"aws_instance", "aws_node"})
 	awsInstanceBody := awsInstance.Body()
 	awsInstanceBody.SetAttributeValue("count", cty.NumberIntVal(int64(numNodes)))
 	awsInstanceBody.SetAttributeValue("ami", cty.StringVal(ami))
-	awsInstanceBody.SetAttributeValue("instance_type", cty.StringVal("c5.2xlarge"))
+	awsInstanceBody.SetAttributeValue("instance_type", cty.StringVal(instanceType))
 	if !useExistingKeyPair {
 		awsInstanceBody.SetAttributeTraversal("key_name", hcl.Traversal{
 			hcl.TraverseRoot{
@@ -174,7 +183,11 @@ func SetupInstances(rootBody *hclwrite.Body, securityGroupName string, useExisti
 	awsInstanceBody.SetAttributeValue("security_groups", cty.ListVal(securityGroupList))
 	rootBlockDevice := awsInstanceBody.AppendNewBlock("root_block_device", []string{})
 	rootBlockDeviceBody := rootBlockDevice.Body()
-	rootBlockDeviceBody.SetAttributeValue("volume_size", cty.NumberIntVal(constants.CloudServerStorageSize))
+	rootBlockDeviceBody.SetAttributeValue("volume_size", cty.NumberIntVal(int64(diskSize)))
+	rootBlockDeviceBody.SetAttributeValue("volume_type", cty.StringVal(diskType))
+	if diskIOPS > 0 {
+		rootBlockDeviceBody.SetAttributeValue("iops", cty.NumberIntVal(int64(diskIOPS)))
+	}
 }
 
 // SetOutput adds output section in terraform state file so that we can call terraform output command and print instance_ip and instance_id to user
@@ -131,12 +131,12 @@ func createCustomTokens(tokenName string) hclwrite.Tokens {
 }
 
 // SetupInstances adds google_compute_instance section in terraform state file where we configure all the necessary components of the desired GCE instance(s)
-func SetupInstances(rootBody *hclwrite.Body, networkName, sshPublicKey, ami, staticIPName, instanceName string, numNodes int, networkExists bool) {
+func SetupInstances(rootBody *hclwrite.Body, networkName, sshPublicKey, ami, staticIPName, instanceName, instanceType, diskType string, diskSize, numNodes int, networkExists bool) {
 	gcpInstance := rootBody.AppendNewBlock("resource", []string{"google_compute_instance", "gcp-node"})
 	gcpInstanceBody := gcpInstance.Body()
 	gcpInstanceBody.SetAttributeRaw("name", createCustomTokens(instanceName))
 	gcpInstanceBody.SetAttributeValue("count", cty.NumberIntVal(int64(numNodes)))
-	gcpInstanceBody.SetAttributeValue("machine_type", cty.StringVal("e2-standard-8"))
+	gcpInstanceBody.SetAttributeValue("machine_type", cty.StringVal(instanceType))
 	metadataMap := make(map[string]cty.Value)
 	metadataMap["ssh-keys"] = cty.StringVal(fmt.Sprintf("ubuntu:%s", strings.TrimSuffix(sshPublicKey, "\n")))
 	gcpInstanceBody.SetAttributeValue("metadata", cty.ObjectVal(metadataMap))
@@ -178,7 +178,8 @@ func SetupInstances(rootBody *hclwrite.Body, networkName, sshPublicKey, ami, sta
 	initParams := bootDiskBody.AppendNewBlock("initialize_params", []string{})
 	initParamsBody := initParams.Body()
 	initParamsBody.SetAttributeValue("image", cty.StringVal(ami))
-	initParamsBody.SetAttributeValue("size", cty.NumberIntVal(1000))
+	initParamsBody.SetAttributeValue("size", cty.NumberIntVal(int64(diskSize)))
+	initParamsBody.SetAttributeValue("type", cty.StringVal(diskType))
 
 	gcpInstanceBody.SetAttributeValue("allow_stopping_for_update", cty.BoolVal(true))
 }
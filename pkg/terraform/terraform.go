@@ -4,7 +4,11 @@
 package terraform
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -32,7 +36,7 @@ func SaveConf(terraformDir string, hclFile *hclwrite.File) error {
 	return err
 }
 
-// RemoveDirectory remove terraform directory in .avalanche-cli. We need to call this before and after creating ec2 instance
+// RemoveDirectory remove terraform directory in .avalanche-cli, once all resources of its state are destroyed
 func RemoveDirectory(terraformDir string) error {
 	return os.RemoveAll(terraformDir)
 }
@@ -68,3 +72,126 @@ func GetPublicIPs(terraformDir string) ([]string, error) {
 	}
 	return publicIPs, nil
 }
+
+// StateResource is a resource recorded in a terraform state
+type StateResource struct {
+	Address string
+	Type    string
+	Values  map[string]interface{}
+}
+
+// ID returns the cloud ID of the resource
+func (r StateResource) ID() string {
+	return r.StringValue("id")
+}
+
+// StringValue returns the attribute [key] of the resource if it is a string, "" otherwise
+func (r StateResource) StringValue(key string) string {
+	value, _ := r.Values[key].(string)
+	return value
+}
+
+// runTerraformCmd runs terraform with [args] on [terraformDir], returning stdout. If [output] is not nil,
+// stdout is also copied to it
+func runTerraformCmd(terraformDir string, output io.Writer, args ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd := exec.Command(constants.Terraform, args...) //nolint:gosec
+	cmd.Env = os.Environ()
+	cmd.Dir = terraformDir
+	cmd.Stdout = &stdout
+	if output != nil {
+		cmd.Stdout = io.MultiWriter(output, &stdout)
+	}
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil && stderr.Len() > 0 {
+		err = fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), err
+}
+
+// GetStateResources returns the managed resources recorded in the terraform state of [terraformDir]
+func GetStateResources(terraformDir string) ([]StateResource, error) {
+	stateOutput, err := runTerraformCmd(terraformDir, nil, "show", "-json", "-no-color")
+	if err != nil {
+		return nil, err
+	}
+	state := struct {
+		Values struct {
+			RootModule struct {
+				Resources []struct {
+					Address string                 `json:"address"`
+					Mode    string                 `json:"mode"`
+					Type    string                 `json:"type"`
+					Values  map[string]interface{} `json:"values"`
+				} `json:"resources"`
+			} `json:"root_module"`
+		} `json:"values"`
+	}{}
+	if err := json.Unmarshal(stateOutput, &state); err != nil {
+		return nil, fmt.Errorf("invalid terraform state at %s: %w", terraformDir, err)
+	}
+	resources := []StateResource{}
+	for _, resource := range state.Values.RootModule.Resources {
+		// data sources are only read by terraform, they are not removed on destroy
+		if resource.Mode != "managed" {
+			continue
+		}
+		resources = append(resources, StateResource{
+			Address: resource.Address,
+			Type:    resource.Type,
+			Values:  resource.Values,
+		})
+	}
+	return resources, nil
+}
+
+// RemoveFromState makes terraform forget the resources at [addresses] of the state of [terraformDir],
+// so they are kept on the cloud when the state is destroyed
+func RemoveFromState(terraformDir string, addresses []string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+	_, err := runTerraformCmd(terraformDir, nil, append([]string{"state", "rm"}, addresses...)...)
+	return err
+}
+
+// PlanDestroy prints the plan to destroy the resources of the terraform state of [terraformDir] and
+// saves it to be applied with ApplyDestroy. If [targets] is not empty, only the resources at those
+// addresses are destroyed, otherwise all of them. The state is not changed. Returns false if there
+// is nothing to destroy
+func PlanDestroy(terraformDir string, targets []string) (bool, error) {
+	if _, err := runTerraformCmd(terraformDir, nil, "init", "-input=false"); err != nil {
+		return false, err
+	}
+	args := []string{"plan", "-destroy", "-input=false", "-detailed-exitcode", "-out=" + constants.TerraformDestroyPlanFile}
+	for _, target := range targets {
+		args = append(args, "-target="+target)
+	}
+	_, err := runTerraformCmd(terraformDir, os.Stdout, args...)
+	if err == nil {
+		return false, nil
+	}
+	// with -detailed-exitcode, exit code 2 means that the plan has changes
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+		return true, nil
+	}
+	return false, err
+}
+
+// ApplyDestroy applies the destroy plan saved by PlanDestroy on [terraformDir]
+func ApplyDestroy(terraformDir string) error {
+	_, err := runTerraformCmd(terraformDir, os.Stdout, "apply", "-input=false", constants.TerraformDestroyPlanFile)
+	return err
+}
+
+// Apply creates the resources defined in the terraform configuration of [terraformDir]
+func Apply(terraformDir string) error {
+	if _, err := runTerraformCmd(terraformDir, nil, "init", "-input=false"); err != nil {
+		return err
+	}
+	_, err := runTerraformCmd(terraformDir, os.Stdout, "apply", "-input=false", "-auto-approve")
+	return err
+}
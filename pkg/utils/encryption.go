@@ -0,0 +1,77 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	encryptionSaltLen = 16
+	encryptionKeyLen  = 32
+	// scrypt cost parameters recommended for interactive logins
+	scryptN = 32768
+	scryptR = 8
+	scryptP = 1
+)
+
+var ErrWrongPassword = errors.New("wrong password or corrupted data")
+
+func newPasswordCipher(password string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, encryptionKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptWithPassword encrypts [data] with AES-GCM, using a key derived from [password] with
+// scrypt. The random salt and nonce are prepended to the result
+func EncryptWithPassword(data []byte, password string) ([]byte, error) {
+	salt := make([]byte, encryptionSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := newPasswordCipher(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	encrypted := append(salt, nonce...)
+	return aead.Seal(encrypted, nonce, data, nil), nil
+}
+
+// DecryptWithPassword decrypts [encrypted] as produced by EncryptWithPassword. Returns
+// ErrWrongPassword if [password] is not the one it was encrypted with
+func DecryptWithPassword(encrypted []byte, password string) ([]byte, error) {
+	if len(encrypted) < encryptionSaltLen {
+		return nil, ErrWrongPassword
+	}
+	salt := encrypted[:encryptionSaltLen]
+	aead, err := newPasswordCipher(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	encrypted = encrypted[encryptionSaltLen:]
+	if len(encrypted) < aead.NonceSize() {
+		return nil, ErrWrongPassword
+	}
+	nonce := encrypted[:aead.NonceSize()]
+	data, err := aead.Open(nil, nonce, encrypted[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrWrongPassword
+	}
+	return data, nil
+}
@@ -0,0 +1,31 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptWithPassword(t *testing.T) {
+	require := require.New(t)
+	data := []byte("staking files")
+	encrypted, err := EncryptWithPassword(data, "password")
+	require.NoError(err)
+	require.NotContains(string(encrypted), string(data))
+
+	decrypted, err := DecryptWithPassword(encrypted, "password")
+	require.NoError(err)
+	require.Equal(data, decrypted)
+
+	_, err = DecryptWithPassword(encrypted, "wrong password")
+	require.ErrorIs(err, ErrWrongPassword)
+	_, err = DecryptWithPassword(encrypted[:10], "password")
+	require.ErrorIs(err, ErrWrongPassword)
+
+	// each encryption uses its own salt and nonce
+	encryptedAgain, err := EncryptWithPassword(data, "password")
+	require.NoError(err)
+	require.NotEqual(encrypted, encryptedAgain)
+}
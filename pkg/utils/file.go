@@ -3,6 +3,9 @@
 package utils
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
 	"os"
 	"path/filepath"
 )
@@ -16,6 +19,15 @@ func FileExists(filename string) bool {
 	return !info.IsDir()
 }
 
+// DirectoryExists checks if a directory exists.
+func DirectoryExists(dirName string) bool {
+	info, err := os.Stat(dirName)
+	if err != nil {
+		return false
+	}
+	return info.IsDir()
+}
+
 // UserHomePath returns the absolute path of a file located in the user's home directory.
 func UserHomePath(filePath ...string) string {
 	home, err := os.UserHomeDir()
@@ -25,3 +37,44 @@ func UserHomePath(filePath ...string) string {
 	fullPath := append([]string{home}, filePath...)
 	return filepath.Join(fullPath...)
 }
+
+// CreateTarGz writes to [tarPath] a gzipped tarball with the files of [srcDir], with paths
+// relative to it
+func CreateTarGz(srcDir string, tarPath string) error {
+	tarFile, err := os.Create(tarPath)
+	if err != nil {
+		return err
+	}
+	defer tarFile.Close()
+	gzipWriter := gzip.NewWriter(tarFile)
+	defer gzipWriter.Close()
+	tarWriter := tar.NewWriter(gzipWriter)
+	defer tarWriter.Close()
+	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		relPath, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relPath)
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(tarWriter, file)
+		return err
+	})
+}
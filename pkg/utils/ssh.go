@@ -4,10 +4,72 @@ package utils
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/ava-labs/avalanche-cli/pkg/constants"
 )
 
-func GetSSHConnectionString(publicIP, certFilePath string) string {
-	return fmt.Sprintf("ssh %s %s@%s -i %s", constants.AnsibleSSHShellParams, constants.AnsibleSSHUser, publicIP, certFilePath)
+func GetSSHConnectionString(sshUser, publicIP, certFilePath string) string {
+	return fmt.Sprintf("ssh %s %s@%s -i %s", constants.AnsibleSSHShellParams, sshUser, publicIP, certFilePath)
+}
+
+// GetSCPCommandArgs returns the args of the scp command that recursively copies [src] to [dst],
+// with the same ssh params as GetSSHConnectionString
+func GetSCPCommandArgs(certFilePath, src, dst string) []string {
+	args := []string{"-r"}
+	args = append(args, strings.Fields(constants.AnsibleSSHShellParams)...)
+	return append(args, "-i", certFilePath, src, dst)
+}
+
+// GetSSHConfigHostEntry returns a ssh config Host entry named [aliases], to connect to [publicIP].
+// Host keys are checked against [knownHostsPath], as entries persist after IPs are reused
+func GetSSHConfigHostEntry(aliases []string, sshUser, publicIP, certFilePath, knownHostsPath string) string {
+	return fmt.Sprintf(`Host %s
+  HostName %s
+  User %s
+  IdentityFile %s
+  IdentitiesOnly yes
+  UserKnownHostsFile %s
+`, strings.Join(aliases, " "), publicIP, sshUser, certFilePath, knownHostsPath)
+}
+
+func sshConfigBlockMarkers(blockName string) (string, string) {
+	return fmt.Sprintf("# BEGIN avalanche-cli %s", blockName), fmt.Sprintf("# END avalanche-cli %s", blockName)
+}
+
+// MergeSSHConfigBlock returns [sshConfig] with the entries previously written for [blockName]
+// replaced by [block], which is appended if there were none
+func MergeSSHConfigBlock(sshConfig, blockName, block string) string {
+	begin, end := sshConfigBlockMarkers(blockName)
+	newBlock := begin + "\n" + block
+	if !strings.HasSuffix(newBlock, "\n") {
+		newBlock += "\n"
+	}
+	newBlock += end + "\n"
+	beginIndex := strings.Index(sshConfig, begin+"\n")
+	if beginIndex != -1 {
+		if endIndex := strings.Index(sshConfig[beginIndex:], end+"\n"); endIndex != -1 {
+			endIndex += beginIndex + len(end) + 1
+			return sshConfig[:beginIndex] + newBlock + sshConfig[endIndex:]
+		}
+	}
+	if sshConfig != "" && !strings.HasSuffix(sshConfig, "\n") {
+		sshConfig += "\n"
+	}
+	if sshConfig != "" {
+		sshConfig += "\n"
+	}
+	return sshConfig + newBlock
+}
+
+// AddSSHConfigInclude returns [sshConfig] including [includePath], if it doesn't already. The
+// Include is added at the top, so it doesn't end up as part of a Host entry
+func AddSSHConfigInclude(sshConfig, includePath string) string {
+	include := "Include " + includePath
+	for _, line := range strings.Split(sshConfig, "\n") {
+		if strings.TrimSpace(line) == include {
+			return sshConfig
+		}
+	}
+	return include + "\n\n" + sshConfig
 }
@@ -0,0 +1,61 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeSSHConfigBlock(t *testing.T) {
+	require := require.New(t)
+	entry1 := GetSSHConfigHostEntry([]string{"i-1", "NodeID-1"}, "ubuntu", "1.2.3.4", "/home/user/.ssh/kp.pem", "/home/user/.avalanche-cli/nodes/clusters/c1/known_hosts")
+	entry2 := GetSSHConfigHostEntry([]string{"i-2"}, "ubuntu", "5.6.7.8", "/home/user/.ssh/kp.pem", "/home/user/.avalanche-cli/nodes/clusters/c2/known_hosts")
+	userConfig := "Host myhost\n  HostName 9.9.9.9"
+
+	merged := MergeSSHConfigBlock(userConfig, "cluster c1", entry1)
+	require.Equal(`Host myhost
+  HostName 9.9.9.9
+
+# BEGIN avalanche-cli cluster c1
+Host i-1 NodeID-1
+  HostName 1.2.3.4
+  User ubuntu
+  IdentityFile /home/user/.ssh/kp.pem
+  IdentitiesOnly yes
+  UserKnownHostsFile /home/user/.avalanche-cli/nodes/clusters/c1/known_hosts
+# END avalanche-cli cluster c1
+`, merged)
+
+	// other clusters are appended, and existing blocks replaced in place
+	merged = MergeSSHConfigBlock(merged, "cluster c2", entry2)
+	merged = MergeSSHConfigBlock(merged, "cluster c1", entry1+entry2)
+	require.Equal(`Host myhost
+  HostName 9.9.9.9
+
+# BEGIN avalanche-cli cluster c1
+`+entry1+entry2+`# END avalanche-cli cluster c1
+
+# BEGIN avalanche-cli cluster c2
+`+entry2+`# END avalanche-cli cluster c2
+`, merged)
+	require.Equal(merged, MergeSSHConfigBlock(merged, "cluster c2", entry2))
+
+	require.Equal("# BEGIN avalanche-cli cluster c1\n"+entry1+"# END avalanche-cli cluster c1\n", MergeSSHConfigBlock("", "cluster c1", entry1))
+}
+
+func TestAddSSHConfigInclude(t *testing.T) {
+	require := require.New(t)
+	userConfig := "Host myhost\n  HostName 9.9.9.9\n"
+	withInclude := AddSSHConfigInclude(userConfig, "/home/user/.avalanche-cli/ssh_config")
+	require.Equal("Include /home/user/.avalanche-cli/ssh_config\n\n"+userConfig, withInclude)
+	require.Equal(withInclude, AddSSHConfigInclude(withInclude, "/home/user/.avalanche-cli/ssh_config"))
+}
+
+func TestGetSCPCommandArgs(t *testing.T) {
+	require.Equal(t,
+		[]string{"-r", "-o", "IdentitiesOnly=yes", "-o", "StrictHostKeyChecking=no", "-i", "kp.pem", "file", "ubuntu@1.2.3.4:/tmp/"},
+		GetSCPCommandArgs("kp.pem", "file", "ubuntu@1.2.3.4:/tmp/"),
+	)
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// PrefixWriter writes to an underlying writer whole lines only, each one prefixed with
+// a given prefix, so the output of concurrent writers sharing a lock is not interleaved
+type PrefixWriter struct {
+	w      io.Writer
+	lock   sync.Locker
+	prefix []byte
+	buf    []byte
+}
+
+// NewPrefixWriter returns a PrefixWriter of [w] adding [prefix] to each line, and taking [lock]
+// while writing to [w]
+func NewPrefixWriter(w io.Writer, lock sync.Locker, prefix string) *PrefixWriter {
+	return &PrefixWriter{w: w, lock: lock, prefix: []byte(prefix)}
+}
+
+func (pw *PrefixWriter) Write(p []byte) (int, error) {
+	pw.buf = append(pw.buf, p...)
+	i := bytes.LastIndexByte(pw.buf, '\n')
+	if i == -1 {
+		return len(p), nil
+	}
+	if err := pw.writeLines(pw.buf[:i+1]); err != nil {
+		return 0, err
+	}
+	pw.buf = pw.buf[i+1:]
+	return len(p), nil
+}
+
+// Flush writes the last line, if it is not terminated by a newline
+func (pw *PrefixWriter) Flush() error {
+	if len(pw.buf) == 0 {
+		return nil
+	}
+	err := pw.writeLines(append(pw.buf, '\n'))
+	pw.buf = nil
+	return err
+}
+
+func (pw *PrefixWriter) writeLines(lines []byte) error {
+	var out bytes.Buffer
+	for _, line := range bytes.SplitAfter(lines, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		out.Write(pw.prefix)
+		out.Write(line)
+	}
+	pw.lock.Lock()
+	defer pw.lock.Unlock()
+	_, err := pw.w.Write(out.Bytes())
+	return err
+}
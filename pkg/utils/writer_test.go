@@ -0,0 +1,28 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+package utils
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	require := require.New(t)
+	var out bytes.Buffer
+	var lock sync.Mutex
+	w := NewPrefixWriter(&out, &lock, "[node1] ")
+	_, err := w.Write([]byte("first line\nsecond "))
+	require.NoError(err)
+	require.Equal("[node1] first line\n", out.String())
+	_, err = w.Write([]byte("line\nthird"))
+	require.NoError(err)
+	require.Equal("[node1] first line\n[node1] second line\n", out.String())
+	require.NoError(w.Flush())
+	require.Equal("[node1] first line\n[node1] second line\n[node1] third\n", out.String())
+	require.NoError(w.Flush())
+	require.Equal("[node1] first line\n[node1] second line\n[node1] third\n", out.String())
+}
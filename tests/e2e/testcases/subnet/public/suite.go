@@ -173,7 +173,7 @@ var _ = ginkgo.Describe("[Public Subnet]", func() {
 		gomega.Expect(err).Should(gomega.BeNil())
 
 		// GetCurrentSupply will return error if queried for non-elastic subnet
-		err = subnet.GetCurrentSupply(subnetID)
+		err = subnet.GetCurrentSupply(models.LocalNetwork, subnetID)
 		gomega.Expect(err).Should(gomega.HaveOccurred())
 
 		_, err = commands.SimulateFujiTransformSubnet(subnetName, keyName)
@@ -183,7 +183,7 @@ var _ = ginkgo.Describe("[Public Subnet]", func() {
 		gomega.Expect(exists).Should(gomega.BeTrue())
 
 		// GetCurrentSupply will return result if queried for elastic subnet
-		err = subnet.GetCurrentSupply(subnetID)
+		err = subnet.GetCurrentSupply(models.LocalNetwork, subnetID)
 		gomega.Expect(err).Should(gomega.BeNil())
 
 		_, err = commands.SimulateFujiTransformSubnet(subnetName, keyName)
@@ -218,7 +218,7 @@ var _ = ginkgo.Describe("[Public Subnet]", func() {
 		// confirm current validator set
 		subnetID, err := ids.FromString(subnetIDStr)
 		gomega.Expect(err).Should(gomega.BeNil())
-		validators, err := subnet.GetSubnetValidators(subnetID)
+		validators, err := subnet.GetSubnetValidators(models.LocalNetwork, subnetID)
 		gomega.Expect(err).Should(gomega.BeNil())
 		gomega.Expect(len(validators)).Should(gomega.Equal(5))
 
@@ -236,7 +236,7 @@ var _ = ginkgo.Describe("[Public Subnet]", func() {
 		_ = commands.SimulateFujiRemoveValidator(subnetName, keyName, validatorToRemove)
 
 		// confirm current validator set
-		validators, err = subnet.GetSubnetValidators(subnetID)
+		validators, err = subnet.GetSubnetValidators(models.LocalNetwork, subnetID)
 		gomega.Expect(err).Should(gomega.BeNil())
 		gomega.Expect(len(validators)).Should(gomega.Equal(4))
 
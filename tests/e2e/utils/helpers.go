@@ -951,7 +951,7 @@ func GetValidators(subnetName string) ([]string, error) {
 		return nil, errors.New("no subnet id")
 	}
 	// Get NodeIDs of all validators on the subnet
-	validators, err := subnet.GetSubnetValidators(subnetID)
+	validators, err := subnet.GetSubnetValidators(models.LocalNetwork, subnetID)
 	if err != nil {
 		return nil, err
 	}
@@ -968,7 +968,7 @@ func GetCurrentSupply(subnetName string) error {
 		return err
 	}
 	subnetID := sc.Networks[models.Local.String()].SubnetID
-	return subnet.GetCurrentSupply(subnetID)
+	return subnet.GetCurrentSupply(models.LocalNetwork, subnetID)
 }
 
 func IsNodeInPendingValidator(subnetName string, nodeID string) (bool, error) {
@@ -977,7 +977,7 @@ func IsNodeInPendingValidator(subnetName string, nodeID string) (bool, error) {
 		return false, err
 	}
 	subnetID := sc.Networks[models.Local.String()].SubnetID
-	return subnet.CheckNodeIsInSubnetPendingValidators(subnetID, nodeID)
+	return subnet.CheckNodeIsInSubnetPendingValidators(models.LocalNetwork, subnetID, nodeID)
 }
 
 func CheckAllNodesAreCurrentValidators(subnetName string) (bool, error) {